@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -17,6 +20,7 @@ import (
 	"github.com/insider-one/notification-service/internal/handler"
 	"github.com/insider-one/notification-service/internal/middleware"
 	"github.com/insider-one/notification-service/internal/provider"
+	"github.com/insider-one/notification-service/internal/repository/memory"
 	"github.com/insider-one/notification-service/internal/repository/postgres"
 	"github.com/insider-one/notification-service/internal/repository/redis"
 	"github.com/insider-one/notification-service/internal/service"
@@ -85,17 +89,144 @@ func main() {
 
 	// Initialize repositories
 	notificationRepo := postgres.NewNotificationRepository(db)
+	notificationRepo.SetInstance(cfg.App.Instance)
 	templateRepo := postgres.NewTemplateRepository(db)
 	queue := redis.NewQueue(redisClient)
 	rateLimiter := redis.NewRateLimiter(redisClient, cfg.Worker.RateLimitPerSec)
+	routingStore := redis.NewRoutingStore(redisClient)
+	trackingRepo := postgres.NewTrackingRepository(db)
+	suppressionRepo := postgres.NewSuppressionRepository(db)
+	recipientRepo := postgres.NewRecipientRepository(db)
+	topicRepo := postgres.NewTopicRepository(db)
+	publicationRepo := postgres.NewPublicationRepository(db)
+	inboundRepo := postgres.NewInboundRepository(db)
+	attemptRepo := postgres.NewAttemptRepository(db)
+	scheduleRepo := postgres.NewScheduleRepository(db)
+	planRepo := postgres.NewPlanRepository(db)
+	digestRuleRepo := postgres.NewDigestRuleRepository(db)
+
+	// Initialize providers
+	if err := config.LoadProviders(&cfg.Providers); err != nil {
+		logger.Error("failed to load provider config", "error", err)
+		os.Exit(1)
+	}
+	if err := config.LoadPricing(&cfg.Pricing); err != nil {
+		logger.Error("failed to load pricing config", "error", err)
+		os.Exit(1)
+	}
+	if err := config.LoadQuietHours(&cfg.Quiet); err != nil {
+		logger.Error("failed to load quiet hours config", "error", err)
+		os.Exit(1)
+	}
+	quietHours, err := newQuietHours(cfg.Quiet)
+	if err != nil {
+		logger.Error("invalid quiet hours config", "error", err)
+		os.Exit(1)
+	}
+	dedupPolicy, err := newDedupPolicy(cfg.Dedup)
+	if err != nil {
+		logger.Error("invalid dedup config", "error", err)
+		os.Exit(1)
+	}
+	if err := domain.ValidatePhoneRegion(cfg.Phone.DefaultRegion); err != nil {
+		logger.Error("invalid phone config", "error", err)
+		os.Exit(1)
+	}
+	webhookProvider, err := provider.NewWebhookProvider(cfg.Webhook)
+	if err != nil {
+		logger.Error("failed to initialize webhook provider", "error", err)
+		os.Exit(1)
+	}
+	builtinProviders := map[string]domain.NotificationProvider{
+		provider.WebhookProviderName: webhookProvider,
+	}
+	defaultProvider := provider.WebhookProviderName
+
+	var sandboxStore domain.SandboxStore
+	if cfg.Sandbox.Enabled {
+		if cfg.Sandbox.Store == "redis" {
+			sandboxStore = redis.NewSandboxStore(redisClient, cfg.Sandbox.Capacity)
+		} else {
+			sandboxStore = memory.NewSandboxStore(cfg.Sandbox.Capacity)
+		}
+		builtinProviders[provider.SandboxProviderName] = provider.NewSandboxProvider(cfg.Sandbox, sandboxStore)
+		defaultProvider = provider.SandboxProviderName
+		logger.Warn("sandbox provider enabled, unrouted notifications will not be delivered",
+			"store", cfg.Sandbox.Store,
+			"error_rate", cfg.Sandbox.ErrorRate,
+		)
+	}
 
-	// Initialize provider
-	webhookProvider := provider.NewWebhookProvider(cfg.Webhook)
+	providerRouter, err := provider.NewChannelRouterFromConfig(cfg.Providers, builtinProviders, defaultProvider)
+	if err != nil {
+		logger.Error("failed to initialize providers", "error", err)
+		os.Exit(1)
+	}
+
+	receiptParsers, err := provider.NewReceiptParsers(cfg.Providers.Receipts)
+	if err != nil {
+		logger.Error("failed to initialize delivery receipts", "error", err)
+		os.Exit(1)
+	}
+
+	inboundParsers, err := provider.NewInboundParsers(cfg.Providers.Inbound)
+	if err != nil {
+		logger.Error("failed to initialize inbound messages", "error", err)
+		os.Exit(1)
+	}
 
 	// Initialize services
 	templateService := service.NewTemplateService(templateRepo, logger)
 	notificationService := service.NewNotificationService(notificationRepo, templateRepo, queue, logger)
+	notificationService.SetSuppressions(suppressionRepo)
+	notificationService.SetRecipients(recipientRepo)
+	notificationService.SetDigests(digestRuleRepo)
+	notificationService.SetAttempts(attemptRepo)
+	notificationService.SetExpiryPolicy(newExpiryPolicy(cfg.Expiry))
+	notificationService.SetQuietHours(quietHours)
+	notificationService.SetDedup(redis.NewDedupStore(redisClient), dedupPolicy)
+	notificationService.SetPhoneRegion(cfg.Phone.DefaultRegion)
 	schedulerService := service.NewSchedulerService(notificationRepo, queue, logger, cfg.Worker.SchedulerInterval)
+	schedulerService.SetQuietHours(quietHours)
+	scheduleService := service.NewScheduleService(scheduleRepo, templateRepo, notificationService, logger)
+	schedulerService.SetSchedules(scheduleService)
+	planService := service.NewPlanService(planRepo, templateRepo, notificationService, logger)
+	schedulerService.SetPlans(planService)
+	topicService := service.NewTopicService(topicRepo, publicationRepo, recipientRepo, templateRepo, notificationService, logger)
+	schedulerService.SetTopics(topicService)
+	digestService := service.NewDigestService(digestRuleRepo, templateRepo, notificationService, logger)
+	schedulerService.SetDigests(digestService)
+	routingService := service.NewRoutingService(providerRouter, routingStore, logger, cfg.Providers.SyncInterval)
+	routingService.SetProviderReporter(attemptRepo)
+	reportService := service.NewReportService(notificationRepo)
+	reportService.SetProviderReporter(attemptRepo)
+	receiptService := service.NewReceiptService(notificationRepo, logger)
+	receiptService.SetSuppressions(suppressionRepo)
+	suppressionService := service.NewSuppressionService(suppressionRepo, logger)
+	suppressionService.SetPhoneRegion(cfg.Phone.DefaultRegion)
+	recipientService := service.NewRecipientService(recipientRepo, logger)
+	recipientService.SetPhoneRegion(cfg.Phone.DefaultRegion)
+	inboundKeywords := domain.NewInboundKeywords(cfg.Inbound.OptOutKeywords, cfg.Inbound.OptInKeywords)
+	inboundService := service.NewInboundService(inboundRepo, suppressionRepo, inboundKeywords, logger)
+	inboundService.SetPhoneRegion(cfg.Phone.DefaultRegion)
+	if cfg.Inbound.CallbackURL != "" {
+		forwarder, err := provider.NewInboundForwarder(cfg.Inbound)
+		if err != nil {
+			logger.Error("failed to initialize inbound callback", "error", err)
+			os.Exit(1)
+		}
+		inboundService.SetForwarder(forwarder)
+	}
+	statusPoller := service.NewStatusPollerService(notificationRepo, providerRouter, receiptService, logger, cfg.Poller)
+
+	var trackingService *service.TrackingService
+	if cfg.Tracking.Enabled {
+		trackingService, err = service.NewTrackingService(trackingRepo, cfg.Tracking, logger)
+		if err != nil {
+			logger.Error("failed to initialize tracking", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize WebSocket hub
 	wsHub := handler.NewWebSocketHub(logger)
@@ -106,28 +237,66 @@ func main() {
 		wsHub.BroadcastStatus(n)
 	}
 	notificationService.SetStatusBroadcast(statusBroadcast)
+	receiptService.SetStatusBroadcast(statusBroadcast)
 
 	// Initialize worker processor
 	processor := worker.NewProcessor(
 		notificationRepo,
 		queue,
 		rateLimiter,
-		webhookProvider,
+		providerRouter,
 		logger,
 		cfg.Retry,
 		cfg.Worker,
 	)
 	processor.SetStatusBroadcast(statusBroadcast)
+	processor.SetPricing(newPriceTable(cfg.Pricing))
+	processor.SetSuppressions(suppressionRepo)
+	processor.SetPhoneRegion(cfg.Phone.DefaultRegion)
+	processor.SetAttempts(attemptRepo, cfg.Attempts.BodyMaxBytes)
+	if trackingService != nil {
+		processor.SetContentRewriter(trackingService)
+	}
 
 	// Initialize handlers
 	notificationHandler := handler.NewNotificationHandler(notificationService)
 	templateHandler := handler.NewTemplateHandler(templateService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	planHandler := handler.NewPlanHandler(planService)
+	topicHandler := handler.NewTopicHandler(topicService)
+	digestHandler := handler.NewDigestHandler(digestService)
 	healthHandler := handler.NewHealthHandler()
 	healthHandler.AddChecker("postgres", db)
 	healthHandler.AddChecker("redis", redisClient)
 
+	providerHandler := handler.NewProviderHandler(routingService)
+	reportHandler := handler.NewReportHandler(reportService)
+	receiptHandler := handler.NewReceiptHandler(receiptService, receiptParsers)
+	suppressionHandler := handler.NewSuppressionHandler(suppressionService)
+	recipientHandler := handler.NewRecipientHandler(recipientService)
+	inboundHandler := handler.NewInboundHandler(inboundService, inboundParsers)
+
+	var trackingHandler *handler.TrackingHandler
+	if trackingService != nil {
+		trackingHandler = handler.NewTrackingHandler(trackingService)
+	}
+
 	metrics := handler.NewMetrics()
 	metricsHandler := handler.NewMetricsHandler(metrics, queue)
+	processor.SetAttemptObserver(metrics.RecordDeliveryAttempt)
+	processor.SetCostObserver(func(n *domain.Notification) {
+		metrics.RecordNotificationCost(*n.Provider, string(n.Channel), *n.Currency, *n.Cost)
+	})
+	receiptService.SetDeliveryObserver(func(n *domain.Notification) {
+		if n.SentAt == nil || n.DeliveredAt == nil {
+			return
+		}
+		providerName := ""
+		if n.Provider != nil {
+			providerName = *n.Provider
+		}
+		metrics.RecordDeliveryLatency(providerName, string(n.Channel), n.DeliveredAt.Sub(*n.SentAt))
+	})
 	wsHandler := handler.NewWebSocketHandler(wsHub)
 
 	// Setup router
@@ -158,13 +327,73 @@ func main() {
 
 		// API routes
 		r.Route("/api/v1", func(r chi.Router) {
+			r.Use(middleware.Actor("api"))
 			r.Route("/notifications", func(r chi.Router) {
 				notificationHandler.RegisterRoutes(r)
+				if trackingHandler != nil {
+					trackingHandler.RegisterNotificationRoutes(r)
+				}
 			})
 
 			r.Route("/templates", func(r chi.Router) {
 				templateHandler.RegisterRoutes(r)
 			})
+
+			r.Route("/schedules", func(r chi.Router) {
+				scheduleHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/plans", func(r chi.Router) {
+				planHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/topics", func(r chi.Router) {
+				topicHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/digest-rules", func(r chi.Router) {
+				digestHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/suppressions", func(r chi.Router) {
+				suppressionHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/recipients", func(r chi.Router) {
+				recipientHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/receipts", func(r chi.Router) {
+				receiptHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/inbound", func(r chi.Router) {
+				inboundHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/reports", func(r chi.Router) {
+				reportHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/admin/providers", func(r chi.Router) {
+				providerHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/admin/suppressions", func(r chi.Router) {
+				suppressionHandler.RegisterAdminRoutes(r)
+			})
+
+			if trackingHandler != nil {
+				r.Route("/track", func(r chi.Router) {
+					trackingHandler.RegisterRoutes(r)
+				})
+			}
+
+			if sandboxStore != nil {
+				r.Route("/sandbox", func(r chi.Router) {
+					handler.NewSandboxHandler(sandboxStore).RegisterRoutes(r)
+				})
+			}
 		})
 	})
 
@@ -188,6 +417,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Start provider route sync
+	if err := routingService.Start(ctx); err != nil {
+		logger.Error("failed to start routing service", "error", err)
+		os.Exit(1)
+	}
+
+	// Start provider status polling
+	if cfg.Poller.Enabled {
+		if err := statusPoller.Start(ctx); err != nil {
+			logger.Error("failed to start status poller", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Start server in goroutine
 	go func() {
 		logger.Info("server listening", "port", cfg.Server.Port)
@@ -215,6 +458,8 @@ func main() {
 
 	// Stop scheduler
 	schedulerService.Stop()
+	routingService.Stop()
+	statusPoller.Stop()
 
 	// Stop processor (waits for in-flight work)
 	processor.Stop()
@@ -224,3 +469,75 @@ func main() {
 
 	logger.Info("server stopped")
 }
+
+// newExpiryPolicy converts the configured default TTLs into a domain.ExpiryPolicy
+func newExpiryPolicy(cfg config.ExpiryConfig) domain.ExpiryPolicy {
+	return domain.ExpiryPolicy{
+		Channels: map[domain.Channel]time.Duration{
+			domain.ChannelSMS:   cfg.SMS,
+			domain.ChannelEmail: cfg.Email,
+			domain.ChannelPush:  cfg.Push,
+		},
+		Categories: cfg.Categories,
+	}
+}
+
+func newDedupPolicy(cfg config.DedupConfig) (domain.DedupPolicy, error) {
+	mode := domain.DedupMode(cfg.Mode)
+	if !mode.IsValid() {
+		return domain.DedupPolicy{}, fmt.Errorf("mode must be %q or %q, got %q", domain.DedupReturnOriginal, domain.DedupReject, cfg.Mode)
+	}
+	return domain.DedupPolicy{
+		Windows: map[domain.Channel]time.Duration{
+			domain.ChannelSMS:   cfg.SMS,
+			domain.ChannelEmail: cfg.Email,
+			domain.ChannelPush:  cfg.Push,
+		},
+		Mode: mode,
+	}, nil
+}
+
+func newQuietHours(cfg config.QuietHoursConfig) (*domain.QuietHours, error) {
+	location, err := time.LoadLocation(cfg.DefaultTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("default time zone: %w", err)
+	}
+
+	rules := make([]domain.QuietHoursRule, 0, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		start, err := domain.ParseClock(r.Start)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d start: %w", i, err)
+		}
+		end, err := domain.ParseClock(r.End)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d end: %w", i, err)
+		}
+		rule := domain.QuietHoursRule{
+			Channel:  domain.Channel(r.Channel),
+			Category: r.Category,
+			Start:    start,
+			End:      end,
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return domain.NewQuietHours(rules, location, cfg.ExemptCategories), nil
+}
+
+// newPriceTable converts the configured prices into a domain.PriceTable
+func newPriceTable(cfg config.PricingConfig) *domain.PriceTable {
+	rules := make([]domain.PriceRule, 0, len(cfg.Prices))
+	for _, p := range cfg.Prices {
+		rules = append(rules, domain.PriceRule{
+			Provider:  p.Provider,
+			Channel:   domain.Channel(p.Channel),
+			Country:   strings.TrimPrefix(p.Country, "+"),
+			UnitPrice: p.UnitPrice,
+		})
+	}
+	return domain.NewPriceTable(cfg.Currency, rules)
+}
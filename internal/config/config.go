@@ -1,25 +1,42 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Webhook  WebhookConfig
-	Worker   WorkerConfig
-	Retry    RetryConfig
+	App       AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Webhook   WebhookConfig
+	Providers ProvidersConfig
+	Sandbox   SandboxConfig
+	Pricing   PricingConfig
+	Poller    PollerConfig
+	Tracking  TrackingConfig
+	Inbound   InboundConfig
+	Attempts  AttemptsConfig
+	Expiry    ExpiryConfig
+	Dedup     DedupConfig
+	Phone     PhoneConfig
+	Quiet     QuietHoursConfig
+	Worker    WorkerConfig
+	Retry     RetryConfig
 }
 
 type AppConfig struct {
 	Env      string
 	LogLevel string
+	// Instance identifies this replica in status event history
+	Instance string
 }
 
 type ServerConfig struct {
@@ -46,6 +63,284 @@ type RedisConfig struct {
 type WebhookConfig struct {
 	URL     string
 	Timeout time.Duration
+	Auth    AuthConfig
+}
+
+// AuthConfig configures how outbound provider requests are authenticated.
+// Type is one of "bearer", "basic", "api_key", "hmac" or "oauth2"; an empty
+// Type sends requests unauthenticated. Secret holds the token, password,
+// API key, signing key or OAuth2 client secret; SecretFile takes precedence
+// and is read at startup so secrets can be mounted rather than inlined.
+type AuthConfig struct {
+	Type            string   `json:"type"`
+	Username        string   `json:"username"`
+	Header          string   `json:"header"`
+	TimestampHeader string   `json:"timestamp_header"`
+	Secret          string   `json:"secret"`
+	SecretFile      string   `json:"secret_file"`
+	TokenURL        string   `json:"token_url"`
+	ClientID        string   `json:"client_id"`
+	Scopes          []string `json:"scopes"`
+}
+
+// ResolveSecret returns the configured secret, reading SecretFile if set
+func (a AuthConfig) ResolveSecret() (string, error) {
+	if a.SecretFile == "" {
+		return a.Secret, nil
+	}
+	data, err := os.ReadFile(a.SecretFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ProvidersConfig describes the outbound providers loaded from ConfigFile
+type ProvidersConfig struct {
+	ConfigFile string               `json:"-"`
+	HTTP       []HTTPProviderConfig `json:"http"`
+	// Channels maps a channel name to the provider that delivers it.
+	// Channels without an entry fall back to the webhook provider.
+	Channels map[string]string `json:"channels"`
+	// Routes splits a channel's traffic across providers by weight and
+	// takes precedence over Channels. Weights can be changed at runtime.
+	Routes map[string][]RouteConfig `json:"routes"`
+	// Receipts configures the signed delivery receipt webhook per provider name
+	Receipts map[string]ReceiptConfig `json:"receipts"`
+	// Inbound configures the signed inbound message webhook per provider name
+	Inbound      map[string]InboundWebhookConfig `json:"inbound"`
+	SyncInterval time.Duration                   `json:"-"`
+}
+
+// ReceiptConfig describes how a provider signs and formats the delivery
+// receipts it posts to /api/v1/receipts/{provider}. Each receipt is a JSON
+// object, or a JSON array of them when the provider batches receipts.
+type ReceiptConfig struct {
+	// Signature holds the shared secret and headers of the HMAC-SHA256
+	// signature over "<timestamp>.<body>"
+	Signature AuthConfig `json:"signature"`
+	MaxSkew   Duration   `json:"max_skew"`
+	ReceiptMapping
+}
+
+// ReceiptMapping holds JSONPath-style expressions that locate delivery
+// status fields in a provider payload, and the status values that mean
+// delivered or undeliverable
+type ReceiptMapping struct {
+	// ItemsPath locates the receipt array inside a batched payload
+	ItemsPath           string   `json:"items_path"`
+	MessageIDPath       string   `json:"message_id_path"`
+	StatusPath          string   `json:"status_path"`
+	ReasonPath          string   `json:"reason_path"`
+	TimestampPath       string   `json:"timestamp_path"`
+	DeliveredValues     []string `json:"delivered_values"`
+	UndeliverableValues []string `json:"undeliverable_values"`
+}
+
+// InboundWebhookConfig describes how a provider signs and formats the inbound
+// messages it posts to /api/v1/inbound/{provider}. Signatures use the same
+// scheme as delivery receipts.
+type InboundWebhookConfig struct {
+	Signature AuthConfig `json:"signature"`
+	MaxSkew   Duration   `json:"max_skew"`
+	InboundMapping
+}
+
+// InboundMapping holds JSONPath-style expressions that locate inbound
+// message fields in a provider payload
+type InboundMapping struct {
+	// ItemsPath locates the message array inside a batched payload
+	ItemsPath     string `json:"items_path"`
+	MessageIDPath string `json:"message_id_path"`
+	FromPath      string `json:"from_path"`
+	ToPath        string `json:"to_path"`
+	BodyPath      string `json:"body_path"`
+	TimestampPath string `json:"timestamp_path"`
+}
+
+// HTTPStatusConfig configures status polling for HTTP providers that do not
+// post receipts. URL, header values and Body are Go templates rendered with
+// .IDs (the batch of message IDs) and .ID (the first one).
+type HTTPStatusConfig struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	Body      string            `json:"body"`
+	BatchSize int               `json:"batch_size"`
+	ReceiptMapping
+}
+
+// RouteConfig is one weighted provider in a channel route
+type RouteConfig struct {
+	Provider string `json:"provider"`
+	Weight   int    `json:"weight"`
+}
+
+// HTTPProviderConfig configures a generic HTTP provider. URL, header values
+// and Body are Go templates rendered with the outgoing notification.
+type HTTPProviderConfig struct {
+	Name            string              `json:"name"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	Headers         map[string]string   `json:"headers"`
+	Auth            AuthConfig          `json:"auth"`
+	Body            string              `json:"body"`
+	Timeout         Duration            `json:"timeout"`
+	SuccessStatuses []int               `json:"success_statuses"`
+	Response        HTTPResponseMapping `json:"response"`
+	ErrorRules      []HTTPErrorRule     `json:"error_rules"`
+	Status          *HTTPStatusConfig   `json:"status"`
+}
+
+// HTTPResponseMapping holds JSONPath-style expressions (e.g. "$.data.id")
+// used to extract fields from a successful provider response
+type HTTPResponseMapping struct {
+	MessageIDPath string `json:"message_id_path"`
+	StatusPath    string `json:"status_path"`
+}
+
+// HTTPErrorRule classifies a failed provider response. A rule matches when
+// every condition it sets holds; the first matching rule wins.
+type HTTPErrorRule struct {
+	StatusCodes  []int  `json:"status_codes"`
+	BodyContains string `json:"body_contains"`
+	BodyPath     string `json:"body_path"`
+	BodyEquals   string `json:"body_equals"`
+	Retryable    bool   `json:"retryable"`
+}
+
+// Duration is a time.Duration that unmarshals from strings such as "5s"
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// SandboxConfig configures the sandbox provider, which captures messages
+// instead of delivering them. When enabled it replaces the webhook provider
+// as the default for channels without a configured route.
+type SandboxConfig struct {
+	Enabled  bool
+	Store    string // "memory" or "redis"
+	Capacity int
+	// Failure injection
+	ErrorRate   float64
+	Latency     time.Duration
+	StatusCodes []int
+	Seed        int64
+}
+
+// PricingConfig holds the per-message price table loaded from ConfigFile
+type PricingConfig struct {
+	ConfigFile string        `json:"-"`
+	Currency   string        `json:"currency"`
+	Prices     []PriceConfig `json:"prices"`
+}
+
+// PriceConfig is the unit price for a provider, channel and country.
+// Empty fields match anything; Country is an E.164 calling code prefix.
+type PriceConfig struct {
+	Provider  string  `json:"provider"`
+	Channel   string  `json:"channel"`
+	Country   string  `json:"country"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+// PollerConfig configures status polling of providers without receipts
+type PollerConfig struct {
+	Enabled       bool
+	Interval      time.Duration
+	MaxAge        time.Duration
+	CheckInterval time.Duration
+	BatchLimit    int
+	RatePerSec    int
+}
+
+// TrackingConfig configures email open and click tracking. BaseURL is the
+// public address of this service that tracking links point at.
+type TrackingConfig struct {
+	Enabled    bool
+	BaseURL    string
+	Secret     string
+	SecretFile string
+}
+
+// ResolveSecret returns the link signing secret, reading SecretFile if set
+func (t TrackingConfig) ResolveSecret() (string, error) {
+	return AuthConfig{Secret: t.Secret, SecretFile: t.SecretFile}.ResolveSecret()
+}
+
+// InboundConfig configures inbound message handling. Messages consisting of
+// an opt-out keyword suppress SMS to the sender; opt-in keywords lift an
+// unsubscribe. Every message is forwarded to CallbackURL when it is set.
+type InboundConfig struct {
+	OptOutKeywords  []string
+	OptInKeywords   []string
+	CallbackURL     string
+	CallbackTimeout time.Duration
+	CallbackAuth    AuthConfig
+}
+
+// AttemptsConfig configures the delivery log of provider requests
+type AttemptsConfig struct {
+	// BodyMaxBytes caps the stored, redacted provider response body
+	BodyMaxBytes int
+}
+
+// ExpiryConfig holds the default time to live of notifications that do not
+// set ttl or expires_at. Zero disables the default; Categories maps a
+// notification category to its TTL and takes precedence over the channel.
+type ExpiryConfig struct {
+	SMS        time.Duration
+	Email      time.Duration
+	Push       time.Duration
+	Categories map[string]time.Duration
+}
+
+// DedupConfig holds the content dedup window of each channel. Zero
+// disables dedup on the channel. Mode is "return" to respond to a
+// duplicate with the original notification or "reject" to fail it with 409.
+type DedupConfig struct {
+	SMS   time.Duration
+	Email time.Duration
+	Push  time.Duration
+	Mode  string
+}
+
+// PhoneConfig configures how SMS recipients are parsed. DefaultRegion is
+// the ISO 3166-1 alpha-2 region numbers without a calling code are read in;
+// empty requires numbers in international format.
+type PhoneConfig struct {
+	DefaultRegion string
+}
+
+// QuietHoursConfig holds the send windows loaded from ConfigFile.
+// Recipients without a known time zone use DefaultTimeZone; high-priority
+// notifications in ExemptCategories are sent during quiet hours.
+type QuietHoursConfig struct {
+	ConfigFile       string                 `json:"-"`
+	DefaultTimeZone  string                 `json:"default_timezone"`
+	ExemptCategories []string               `json:"exempt_categories"`
+	Rules            []QuietHoursRuleConfig `json:"rules"`
+}
+
+// QuietHoursRuleConfig is one quiet-hours window. Start and End are "HH:MM"
+// in the recipient's time zone; empty Channel or Category match anything.
+type QuietHoursRuleConfig struct {
+	Channel  string `json:"channel"`
+	Category string `json:"category"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
 }
 
 type WorkerConfig struct {
@@ -67,6 +362,7 @@ func Load() *Config {
 		App: AppConfig{
 			Env:      getEnv("APP_ENV", "development"),
 			LogLevel: getEnv("LOG_LEVEL", "info"),
+			Instance: getEnv("INSTANCE_ID", hostname()),
 		},
 		Server: ServerConfig{
 			Port:            getEnv("SERVER_PORT", "8080"),
@@ -89,6 +385,84 @@ func Load() *Config {
 		Webhook: WebhookConfig{
 			URL:     getEnv("WEBHOOK_URL", "https://webhook.site/test"),
 			Timeout: getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			Auth: AuthConfig{
+				Type:       getEnv("WEBHOOK_AUTH_TYPE", ""),
+				Username:   getEnv("WEBHOOK_AUTH_USERNAME", ""),
+				Header:     getEnv("WEBHOOK_AUTH_HEADER", ""),
+				Secret:     getEnv("WEBHOOK_AUTH_SECRET", ""),
+				SecretFile: getEnv("WEBHOOK_AUTH_SECRET_FILE", ""),
+				TokenURL:   getEnv("WEBHOOK_AUTH_TOKEN_URL", ""),
+				ClientID:   getEnv("WEBHOOK_AUTH_CLIENT_ID", ""),
+				Scopes:     getListEnv("WEBHOOK_AUTH_SCOPES"),
+			},
+		},
+		Providers: ProvidersConfig{
+			ConfigFile:   getEnv("PROVIDERS_CONFIG_FILE", ""),
+			Receipts:     webhookReceipts(),
+			Inbound:      webhookInbound(),
+			SyncInterval: getDurationEnv("PROVIDER_ROUTES_SYNC_INTERVAL", 10*time.Second),
+		},
+		Sandbox: SandboxConfig{
+			Enabled:     getBoolEnv("SANDBOX_ENABLED", false),
+			Store:       getEnv("SANDBOX_STORE", "memory"),
+			Capacity:    getIntEnv("SANDBOX_CAPACITY", 1000),
+			ErrorRate:   getFloatEnv("SANDBOX_ERROR_RATE", 0),
+			Latency:     getDurationEnv("SANDBOX_LATENCY", 0),
+			StatusCodes: getIntListEnv("SANDBOX_STATUS_CODES", []int{http.StatusServiceUnavailable}),
+			Seed:        int64(getIntEnv("SANDBOX_SEED", 0)),
+		},
+		Pricing: PricingConfig{
+			ConfigFile: getEnv("PRICING_CONFIG_FILE", ""),
+			Currency:   getEnv("PRICING_CURRENCY", "USD"),
+		},
+		Poller: PollerConfig{
+			Enabled:       getBoolEnv("STATUS_POLL_ENABLED", true),
+			Interval:      getDurationEnv("STATUS_POLL_INTERVAL", 30*time.Second),
+			MaxAge:        getDurationEnv("STATUS_POLL_MAX_AGE", 24*time.Hour),
+			CheckInterval: getDurationEnv("STATUS_POLL_RECHECK_INTERVAL", 5*time.Minute),
+			BatchLimit:    getIntEnv("STATUS_POLL_BATCH_LIMIT", 500),
+			RatePerSec:    getIntEnv("STATUS_POLL_RATE_PER_PROVIDER", 5),
+		},
+		Tracking: TrackingConfig{
+			Enabled:    getBoolEnv("TRACKING_ENABLED", false),
+			BaseURL:    getEnv("TRACKING_BASE_URL", "http://localhost:8080"),
+			Secret:     getEnv("TRACKING_SECRET", ""),
+			SecretFile: getEnv("TRACKING_SECRET_FILE", ""),
+		},
+		Inbound: InboundConfig{
+			OptOutKeywords:  getStringListEnv("INBOUND_OPT_OUT_KEYWORDS", []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT"}),
+			OptInKeywords:   getStringListEnv("INBOUND_OPT_IN_KEYWORDS", []string{"START", "UNSTOP", "YES"}),
+			CallbackURL:     getEnv("INBOUND_CALLBACK_URL", ""),
+			CallbackTimeout: getDurationEnv("INBOUND_CALLBACK_TIMEOUT", 5*time.Second),
+			CallbackAuth: AuthConfig{
+				Type:       getEnv("INBOUND_CALLBACK_AUTH_TYPE", ""),
+				Header:     getEnv("INBOUND_CALLBACK_AUTH_HEADER", ""),
+				Secret:     getEnv("INBOUND_CALLBACK_AUTH_SECRET", ""),
+				SecretFile: getEnv("INBOUND_CALLBACK_AUTH_SECRET_FILE", ""),
+			},
+		},
+		Attempts: AttemptsConfig{
+			BodyMaxBytes: getIntEnv("ATTEMPT_BODY_MAX_BYTES", 2048),
+		},
+		Expiry: ExpiryConfig{
+			SMS:        getDurationEnv("TTL_DEFAULT_SMS", 0),
+			Email:      getDurationEnv("TTL_DEFAULT_EMAIL", 0),
+			Push:       getDurationEnv("TTL_DEFAULT_PUSH", 0),
+			Categories: getDurationMapEnv("TTL_CATEGORIES", map[string]time.Duration{"otp": 10 * time.Minute}),
+		},
+		Dedup: DedupConfig{
+			SMS:   getDurationEnv("DEDUP_WINDOW_SMS", 0),
+			Email: getDurationEnv("DEDUP_WINDOW_EMAIL", 0),
+			Push:  getDurationEnv("DEDUP_WINDOW_PUSH", 0),
+			Mode:  getEnv("DEDUP_MODE", "return"),
+		},
+		Phone: PhoneConfig{
+			DefaultRegion: getEnv("PHONE_DEFAULT_REGION", ""),
+		},
+		Quiet: QuietHoursConfig{
+			ConfigFile:       getEnv("QUIET_HOURS_CONFIG_FILE", ""),
+			DefaultTimeZone:  getEnv("QUIET_HOURS_DEFAULT_TIMEZONE", "UTC"),
+			ExemptCategories: getStringListEnv("QUIET_HOURS_EXEMPT_CATEGORIES", []string{"transactional", "otp"}),
 		},
 		Worker: WorkerConfig{
 			SMSCount:          getIntEnv("WORKER_COUNT_SMS", 5),
@@ -104,6 +478,86 @@ func Load() *Config {
 	}
 }
 
+// webhookReceipts enables receipts for the webhook provider when a
+// WEBHOOK_RECEIPT_SECRET or WEBHOOK_RECEIPT_SECRET_FILE is set
+func webhookReceipts() map[string]ReceiptConfig {
+	receipts := make(map[string]ReceiptConfig)
+
+	secret := getEnv("WEBHOOK_RECEIPT_SECRET", "")
+	secretFile := getEnv("WEBHOOK_RECEIPT_SECRET_FILE", "")
+	if secret == "" && secretFile == "" {
+		return receipts
+	}
+
+	receipts["webhook"] = ReceiptConfig{
+		Signature: AuthConfig{
+			Type:       "hmac",
+			Secret:     secret,
+			SecretFile: secretFile,
+		},
+	}
+	return receipts
+}
+
+// webhookInbound enables inbound messages for the webhook provider when a
+// WEBHOOK_INBOUND_SECRET or WEBHOOK_INBOUND_SECRET_FILE is set
+func webhookInbound() map[string]InboundWebhookConfig {
+	inbound := make(map[string]InboundWebhookConfig)
+
+	secret := getEnv("WEBHOOK_INBOUND_SECRET", "")
+	secretFile := getEnv("WEBHOOK_INBOUND_SECRET_FILE", "")
+	if secret == "" && secretFile == "" {
+		return inbound
+	}
+
+	inbound["webhook"] = InboundWebhookConfig{
+		Signature: AuthConfig{
+			Type:       "hmac",
+			Secret:     secret,
+			SecretFile: secretFile,
+		},
+	}
+	return inbound
+}
+
+// LoadProviders reads the provider definitions from cfg.ConfigFile.
+// It is a no-op when no file is configured.
+func LoadProviders(cfg *ProvidersConfig) error {
+	if cfg.ConfigFile == "" {
+		return nil
+	}
+	return loadJSONFile(cfg.ConfigFile, cfg)
+}
+
+// LoadPricing reads the price table from cfg.ConfigFile.
+// It is a no-op when no file is configured.
+func LoadPricing(cfg *PricingConfig) error {
+	if cfg.ConfigFile == "" {
+		return nil
+	}
+	return loadJSONFile(cfg.ConfigFile, cfg)
+}
+
+// LoadQuietHours reads the quiet-hours rules from cfg.ConfigFile.
+// It is a no-op when no file is configured.
+func LoadQuietHours(cfg *QuietHoursConfig) error {
+	if cfg.ConfigFile == "" {
+		return nil
+	}
+	return loadJSONFile(cfg.ConfigFile, cfg)
+}
+
+func loadJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -120,6 +574,55 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+func getStringListEnv(key string, defaultValue []string) []string {
+	if items := getListEnv(key); len(items) > 0 {
+		return items
+	}
+	return defaultValue
+}
+
+func getIntListEnv(key string, defaultValue []int) []int {
+	items := getListEnv(key)
+	if len(items) == 0 {
+		return defaultValue
+	}
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		intValue, err := strconv.Atoi(item)
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -128,3 +631,38 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getDurationMapEnv parses "key=duration" pairs such as "otp=10m,alert=1h"
+func getDurationMapEnv(key string, defaultValue map[string]time.Duration) map[string]time.Duration {
+	items := getListEnv(key)
+	if len(items) == 0 {
+		return defaultValue
+	}
+	values := make(map[string]time.Duration, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return defaultValue
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return defaultValue
+		}
+		values[strings.TrimSpace(name)] = duration
+	}
+	return values
+}
+
+func getListEnv(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
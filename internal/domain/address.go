@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	// E.164 numbers have at most 15 digits including the calling code
+	maxPhoneDigits = 15
+	minPhoneDigits = 8
+
+	maxEmailLength      = 254
+	maxEmailLocalLength = 64
+
+	minPushTokenLength = 8
+	maxPushTokenLength = 255
+	// apnsTokenLength is the length of a hex APNs device token
+	apnsTokenLength = 64
+)
+
+// phoneRegion is the calling code of a region and the trunk prefix dialled
+// before national numbers, if any
+type phoneRegion struct {
+	code  string
+	trunk string
+}
+
+// phoneRegions maps ISO 3166-1 alpha-2 region codes to their dialling plan
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", "1"},
+	"CA": {"1", "1"},
+	"RU": {"7", "8"},
+	"EG": {"20", "0"},
+	"ZA": {"27", "0"},
+	"GR": {"30", ""},
+	"NL": {"31", "0"},
+	"BE": {"32", "0"},
+	"FR": {"33", "0"},
+	"ES": {"34", ""},
+	"HU": {"36", "06"},
+	"IT": {"39", ""},
+	"RO": {"40", "0"},
+	"CH": {"41", "0"},
+	"AT": {"43", "0"},
+	"GB": {"44", "0"},
+	"DK": {"45", ""},
+	"SE": {"46", "0"},
+	"NO": {"47", ""},
+	"PL": {"48", ""},
+	"DE": {"49", "0"},
+	"PE": {"51", "0"},
+	"MX": {"52", ""},
+	"AR": {"54", "0"},
+	"BR": {"55", "0"},
+	"CL": {"56", ""},
+	"CO": {"57", ""},
+	"MY": {"60", "0"},
+	"AU": {"61", "0"},
+	"ID": {"62", "0"},
+	"PH": {"63", "0"},
+	"NZ": {"64", "0"},
+	"SG": {"65", ""},
+	"TH": {"66", "0"},
+	"JP": {"81", "0"},
+	"KR": {"82", "0"},
+	"VN": {"84", "0"},
+	"CN": {"86", "0"},
+	"TR": {"90", "0"},
+	"IN": {"91", "0"},
+	"PK": {"92", "0"},
+	"NG": {"234", "0"},
+	"KE": {"254", "0"},
+	"PT": {"351", ""},
+	"IE": {"353", "0"},
+	"FI": {"358", "0"},
+	"BG": {"359", "0"},
+	"UA": {"380", "0"},
+	"CZ": {"420", ""},
+	"HK": {"852", ""},
+	"BD": {"880", "0"},
+	"TW": {"886", "0"},
+	"SA": {"966", "0"},
+	"AE": {"971", "0"},
+	"IL": {"972", "0"},
+	"QA": {"974", ""},
+	"AZ": {"994", "0"},
+}
+
+// ValidatePhoneRegion checks that region is a supported ISO 3166-1 alpha-2
+// region code. An empty region is valid and means phone numbers must carry
+// their calling code.
+func ValidatePhoneRegion(region string) error {
+	if region == "" {
+		return nil
+	}
+	if _, ok := phoneRegions[strings.ToUpper(region)]; !ok {
+		return fmt.Errorf("unsupported phone region %q", region)
+	}
+	return nil
+}
+
+// NormalizeRecipient validates recipient as an address on channel and
+// returns it in the form notifications store. Phone numbers given in
+// national format are read in region.
+func NormalizeRecipient(channel Channel, recipient, region string) (string, error) {
+	switch channel {
+	case ChannelSMS:
+		return NormalizePhone(recipient, region)
+	case ChannelEmail:
+		return NormalizeEmail(recipient)
+	case ChannelPush:
+		return NormalizePushToken(recipient)
+	}
+	return "", NewValidationError("channel", "invalid channel")
+}
+
+// NormalizePhone returns phone in E.164 format. Numbers starting with "+"
+// or "00" are international; any other number is national to region, whose
+// trunk prefix is dropped. Spaces, dashes, dots and parentheses are ignored.
+func NormalizePhone(phone, region string) (string, error) {
+	phone = strings.TrimSpace(phone)
+
+	var b strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return "", NewValidationError("recipient", "phone number may only contain digits, spaces, dashes, dots and parentheses")
+		}
+	}
+	digits := b.String()
+
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	default:
+		plan, ok := phoneRegions[strings.ToUpper(region)]
+		if !ok {
+			return "", NewValidationError("recipient", "phone number must be in international format, e.g. +905551234567")
+		}
+		digits = plan.code + strings.TrimPrefix(digits, plan.trunk)
+	}
+
+	if len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits {
+		return "", NewValidationError("recipient", fmt.Sprintf("phone number must have %d to %d digits including the calling code", minPhoneDigits, maxPhoneDigits))
+	}
+	if digits[0] == '0' {
+		return "", NewValidationError("recipient", "invalid calling code")
+	}
+
+	return "+" + digits, nil
+}
+
+// NormalizeEmail checks that email is a bare RFC 5322 address and returns
+// it with its domain in lower-case ASCII, converting internationalized
+// domain names to punycode. The local part is kept as given.
+func NormalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", NewValidationError("recipient", "invalid email address")
+	}
+	if address.Name != "" {
+		return "", NewValidationError("recipient", "email address must not include a display name")
+	}
+
+	at := strings.LastIndex(address.Address, "@")
+	local, domain := address.Address[:at], address.Address[at+1:]
+	// Quoted local parts are valid RFC 5322 but rarely accepted by providers
+	if len(local) > maxEmailLocalLength || strings.ContainsAny(local, " \"(),:;<>@[\\]") {
+		return "", NewValidationError("recipient", "invalid email address")
+	}
+
+	domain, err = idna.Lookup.ToASCII(domain)
+	if err != nil || !strings.Contains(domain, ".") {
+		return "", NewValidationError("recipient", "invalid email domain")
+	}
+
+	normalized := local + "@" + domain
+	if len(normalized) > maxEmailLength {
+		return "", NewValidationError("recipient", fmt.Sprintf("email address must be at most %d characters", maxEmailLength))
+	}
+	return normalized, nil
+}
+
+// NormalizePushToken checks that token looks like an FCM or APNs device
+// token. Hex APNs tokens are lower-cased.
+func NormalizePushToken(token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if len(token) < minPushTokenLength || len(token) > maxPushTokenLength {
+		return "", NewValidationError("recipient", fmt.Sprintf("push token must be %d to %d characters", minPushTokenLength, maxPushTokenLength))
+	}
+
+	hex := true
+	for _, r := range token {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+		case r >= 'g' && r <= 'z', r >= 'G' && r <= 'Z',
+			r == '-' || r == '_' || r == ':' || r == '.' || r == '+' || r == '/' || r == '=':
+			hex = false
+		default:
+			return "", NewValidationError("recipient", "push token may only contain letters, digits and -_:.+/=")
+		}
+	}
+
+	if hex && len(token) == apnsTokenLength {
+		return strings.ToLower(token), nil
+	}
+	return token, nil
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name    string
+		phone   string
+		region  string
+		want    string
+		wantErr bool
+	}{
+		{"e164", "+905551234567", "", "+905551234567", false},
+		{"formatted international", " +90 (555) 123-45.67 ", "", "+905551234567", false},
+		{"00 prefix", "00905551234567", "", "+905551234567", false},
+		{"national with trunk prefix", "0555 123 45 67", "TR", "+905551234567", false},
+		{"national without trunk prefix", "(212) 555-0100", "US", "+12125550100", false},
+		{"region is case-insensitive", "07700 900123", "gb", "+447700900123", false},
+		{"national without region", "05551234567", "", "", true},
+		{"letters", "+90555CALLME", "", "", true},
+		{"plus inside", "90+5551234567", "TR", "", true},
+		{"too short", "+90555", "", "", true},
+		{"too long", "+9055512345678901", "", "", true},
+		{"zero calling code", "+0905551234567", "", "", true},
+		{"empty", "", "TR", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.phone, tt.region)
+			if tt.wantErr {
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "recipient", validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "user@example.com", "user@example.com", false},
+		{"domain lower-cased, local part kept", " User@Example.COM ", "User@example.com", false},
+		{"angle brackets", "<user@example.com>", "user@example.com", false},
+		{"idn domain", "user@bücher.de", "user@xn--bcher-kva.de", false},
+		{"utf-8 local part", "müller@example.com", "müller@example.com", false},
+		{"display name", "User <user@example.com>", "", true},
+		{"missing at", "user.example.com", "", true},
+		{"consecutive dots", "us..er@example.com", "", true},
+		{"quoted local part", `"us er"@example.com`, "", true},
+		{"domain without dot", "user@localhost", "", true},
+		{"invalid domain label", "user@-example.com", "", true},
+		{"local part too long", strings.Repeat("a", 65) + "@example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeEmail(tt.email)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizePushToken(t *testing.T) {
+	apns := strings.Repeat("AB12", 16)
+	fcm := "cYx3Q_9kT0e:APA91bH-" + strings.Repeat("x", 120)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{"apns lower-cased", apns, strings.ToLower(apns), false},
+		{"fcm kept", " " + fcm + " ", fcm, false},
+		{"short hex kept", "DEADBEEF", "DEADBEEF", false},
+		{"too short", "abc", "", true},
+		{"too long", strings.Repeat("a", 256), "", true},
+		{"whitespace inside", "device token", "", true},
+		{"looks like email", "user@example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePushToken(tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidatePhoneRegion(t *testing.T) {
+	assert.NoError(t, ValidatePhoneRegion(""))
+	assert.NoError(t, ValidatePhoneRegion("TR"))
+	assert.NoError(t, ValidatePhoneRegion("us"))
+	assert.Error(t, ValidatePhoneRegion("XX"))
+}
+
+func TestSuppressionRecipient_Normalizes(t *testing.T) {
+	assert.Equal(t, "+905551234567", SuppressionRecipient(ChannelSMS, "+90 555 123 45 67", ""))
+	assert.Equal(t, "+905551234567", SuppressionRecipient(ChannelSMS, "0555 123 45 67", "TR"))
+	assert.Equal(t, "user@xn--bcher-kva.de", SuppressionRecipient(ChannelEmail, "User@Bücher.de", "TR"))
+	// National numbers cannot be normalized without a region
+	assert.Equal(t, "0555 123 45 67", SuppressionRecipient(ChannelSMS, " 0555 123 45 67", ""))
+}
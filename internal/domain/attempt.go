@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// AttemptOutcome is the result of a single provider request
+type AttemptOutcome string
+
+const (
+	AttemptSucceeded      AttemptOutcome = "succeeded"
+	AttemptRetryableError AttemptOutcome = "retryable_error"
+	AttemptFailed         AttemptOutcome = "failed"
+)
+
+// DeliveryAttempt records one request made to a provider for a notification
+type DeliveryAttempt struct {
+	ID             uuid.UUID `json:"id"`
+	NotificationID uuid.UUID `json:"notification_id"`
+	// AttemptNumber counts the notification's attempts from 1 and is
+	// assigned when the attempt is stored
+	AttemptNumber int            `json:"attempt_number"`
+	Provider      string         `json:"provider,omitempty"`
+	Channel       Channel        `json:"channel"`
+	Outcome       AttemptOutcome `json:"outcome"`
+	StartedAt     time.Time      `json:"started_at"`
+	CompletedAt   time.Time      `json:"completed_at"`
+	LatencyMs     int64          `json:"latency_ms"`
+	// HTTPStatus is nil when no response was received
+	HTTPStatus        *int    `json:"http_status,omitempty"`
+	ProviderMessageID *string `json:"provider_message_id,omitempty"`
+	// ResponseBody is redacted and truncated before it is stored
+	ResponseBody  *string `json:"response_body,omitempty"`
+	Error         *string `json:"error,omitempty"`
+	CorrelationID *string `json:"correlation_id,omitempty"`
+}
+
+// NewDeliveryAttempt starts an attempt to send a notification
+func NewDeliveryAttempt(n *Notification, startedAt time.Time) *DeliveryAttempt {
+	return &DeliveryAttempt{
+		ID:             uuid.New(),
+		NotificationID: n.ID,
+		Channel:        n.Channel,
+		StartedAt:      startedAt.UTC(),
+	}
+}
+
+// Finish records the provider's response to the attempt, or the error
+// returned instead
+func (a *DeliveryAttempt) Finish(resp *ProviderResponse, err error) {
+	a.CompletedAt = time.Now().UTC()
+	a.LatencyMs = a.CompletedAt.Sub(a.StartedAt).Milliseconds()
+
+	if err == nil {
+		a.Outcome = AttemptSucceeded
+		if resp != nil {
+			a.Provider = resp.Provider
+			a.setHTTPStatus(resp.StatusCode)
+			a.setResponseBody(resp.Body)
+			if resp.MessageID != "" {
+				a.ProviderMessageID = &resp.MessageID
+			}
+		}
+		return
+	}
+
+	a.Outcome = AttemptRetryableError
+	msg := err.Error()
+	a.Error = &msg
+
+	var providerErr ProviderError
+	if errors.As(err, &providerErr) {
+		a.Provider = providerErr.Provider
+		a.setHTTPStatus(providerErr.StatusCode)
+		a.setResponseBody(providerErr.Message)
+		if !providerErr.Retryable {
+			a.Outcome = AttemptFailed
+		}
+	}
+}
+
+// Redact masks personal data in the response body and error, then truncates
+// the body to maxBodyBytes. sensitive lists values such as the recipient
+// that must not be stored verbatim.
+func (a *DeliveryAttempt) Redact(maxBodyBytes int, sensitive ...string) {
+	if a.ResponseBody != nil {
+		body := truncate(RedactPII(*a.ResponseBody, sensitive...), maxBodyBytes)
+		a.ResponseBody = &body
+	}
+	if a.Error != nil {
+		msg := truncate(RedactPII(*a.Error, sensitive...), maxBodyBytes)
+		a.Error = &msg
+	}
+}
+
+func (a *DeliveryAttempt) setHTTPStatus(status int) {
+	if status > 0 {
+		a.HTTPStatus = &status
+	}
+}
+
+func (a *DeliveryAttempt) setResponseBody(body string) {
+	if body != "" {
+		a.ResponseBody = &body
+	}
+}
+
+// RedactedPlaceholder replaces personal data removed from stored text
+const RedactedPlaceholder = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// phonePattern errs on the side of redaction and also masks long
+	// numeric identifiers
+	phonePattern = regexp.MustCompile(`\+?\b\d{8,15}\b`)
+)
+
+// RedactPII masks the sensitive values, email addresses and phone numbers in s
+func RedactPII(s string, sensitive ...string) string {
+	for _, value := range sensitive {
+		if strings.TrimSpace(value) != "" {
+			s = strings.ReplaceAll(s, value, RedactedPlaceholder)
+		}
+	}
+	s = emailPattern.ReplaceAllString(s, RedactedPlaceholder)
+	return phonePattern.ReplaceAllString(s, RedactedPlaceholder)
+}
+
+// truncatedSuffix marks text cut to the stored size limit
+const truncatedSuffix = "...[truncated]"
+
+// truncate shortens s to at most maxBytes without splitting a character;
+// maxBytes <= 0 leaves s unchanged
+func truncate(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncatedSuffix
+}
+
+// DeliveryAttemptRepository stores the provider requests made for notifications
+type DeliveryAttemptRepository interface {
+	// Create stores an attempt and assigns its AttemptNumber
+	Create(ctx context.Context, attempt *DeliveryAttempt) error
+	// ListByNotification returns a notification's attempts, oldest first
+	ListByNotification(ctx context.Context, notificationID uuid.UUID) ([]*DeliveryAttempt, error)
+}
+
+// ProviderReportFilter selects the attempts included in a provider report
+type ProviderReportFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Channel   *Channel
+	Provider  *string
+}
+
+// ProviderReportRow summarises the attempts made through a provider on a channel
+type ProviderReportRow struct {
+	Provider     string  `json:"provider"`
+	Channel      Channel `json:"channel"`
+	Attempts     int64   `json:"attempts"`
+	Successes    int64   `json:"successes"`
+	Failures     int64   `json:"failures"`
+	ErrorRate    float64 `json:"error_rate"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+	// ErrorsByStatus counts failed attempts by HTTP status; "none" counts
+	// failures without a response
+	ErrorsByStatus map[string]int64 `json:"errors_by_status"`
+}
+
+// ProviderReporter aggregates delivery attempts per provider
+type ProviderReporter interface {
+	ProviderReport(ctx context.Context, filter ProviderReportFilter) ([]*ProviderReportRow, error)
+}
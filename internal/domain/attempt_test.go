@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryAttempt_Finish(t *testing.T) {
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+
+	t.Run("success", func(t *testing.T) {
+		a := NewDeliveryAttempt(n, time.Now().Add(-50*time.Millisecond))
+		a.Finish(&ProviderResponse{MessageID: "msg-1", Provider: "acme", StatusCode: 202, Body: `{"id":"msg-1"}`}, nil)
+
+		assert.Equal(t, AttemptSucceeded, a.Outcome)
+		assert.Equal(t, "acme", a.Provider)
+		assert.Equal(t, 202, *a.HTTPStatus)
+		assert.Equal(t, "msg-1", *a.ProviderMessageID)
+		assert.GreaterOrEqual(t, a.LatencyMs, int64(50))
+		assert.Nil(t, a.Error)
+	})
+
+	t.Run("retryable provider error", func(t *testing.T) {
+		a := NewDeliveryAttempt(n, time.Now())
+		err := NewProviderError(503, "unavailable", true)
+		err.Provider = "acme"
+		a.Finish(nil, err)
+
+		assert.Equal(t, AttemptRetryableError, a.Outcome)
+		assert.Equal(t, "acme", a.Provider)
+		assert.Equal(t, 503, *a.HTTPStatus)
+		assert.Equal(t, "unavailable", *a.ResponseBody)
+	})
+
+	t.Run("permanent provider error", func(t *testing.T) {
+		a := NewDeliveryAttempt(n, time.Now())
+		a.Finish(nil, NewProviderError(400, "bad number", false))
+		assert.Equal(t, AttemptFailed, a.Outcome)
+	})
+
+	t.Run("error without response", func(t *testing.T) {
+		a := NewDeliveryAttempt(n, time.Now())
+		a.Finish(nil, errors.New("failed to render template"))
+
+		assert.Equal(t, AttemptRetryableError, a.Outcome)
+		assert.Nil(t, a.HTTPStatus)
+		assert.Nil(t, a.ResponseBody)
+		assert.Equal(t, "failed to render template", *a.Error)
+	})
+}
+
+func TestDeliveryAttempt_Redact(t *testing.T) {
+	n := NewNotification("+905551234567", ChannelSMS, "Your code is 4821")
+	a := NewDeliveryAttempt(n, time.Now())
+	a.Finish(nil, NewProviderError(400,
+		`{"to":"+905551234567","text":"Your code is 4821","contact":"jane.doe@example.com","alt":"905559876543"}`, false))
+
+	a.Redact(0, n.Recipient, n.Content)
+
+	body := *a.ResponseBody
+	assert.NotContains(t, body, "905551234567")
+	assert.NotContains(t, body, "4821")
+	assert.NotContains(t, body, "jane.doe@example.com")
+	assert.NotContains(t, body, "905559876543")
+	assert.NotContains(t, *a.Error, "jane.doe@example.com")
+	assert.Contains(t, body, RedactedPlaceholder)
+}
+
+func TestDeliveryAttempt_RedactTruncates(t *testing.T) {
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+	a := NewDeliveryAttempt(n, time.Now())
+	a.Finish(&ProviderResponse{Body: strings.Repeat("ğ", 100)}, nil)
+
+	a.Redact(15)
+
+	require.NotNil(t, a.ResponseBody)
+	assert.Equal(t, strings.Repeat("ğ", 7)+truncatedSuffix, *a.ResponseBody)
+}
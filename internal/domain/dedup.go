@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DuplicateError reports the earlier notification a duplicate repeats
+type DuplicateError struct {
+	NotificationID uuid.UUID
+}
+
+func (e DuplicateError) Error() string {
+	return fmt.Sprintf("%s: same as notification %s", ErrDuplicateContent, e.NotificationID)
+}
+
+func (e DuplicateError) Unwrap() error {
+	return ErrDuplicateContent
+}
+
+// DedupMode is what creating a duplicate notification does
+type DedupMode string
+
+const (
+	// DedupReturnOriginal returns the earlier notification instead of
+	// creating a new one
+	DedupReturnOriginal DedupMode = "return"
+	// DedupReject fails the request with ErrDuplicateContent
+	DedupReject DedupMode = "reject"
+)
+
+func (m DedupMode) IsValid() bool {
+	switch m {
+	case DedupReturnOriginal, DedupReject:
+		return true
+	}
+	return false
+}
+
+// DedupPolicy holds the dedup window of each channel. Channels without a
+// positive window are not deduplicated.
+type DedupPolicy struct {
+	Windows map[Channel]time.Duration
+	Mode    DedupMode
+}
+
+// Window returns the dedup window of channel, and false when the channel
+// is not deduplicated
+func (p DedupPolicy) Window(channel Channel) (time.Duration, bool) {
+	window, ok := p.Windows[channel]
+	return window, ok && window > 0
+}
+
+// DedupKey returns the hash of a notification's recipient, channel and
+// rendered content. Email addresses are matched case-insensitively.
+func DedupKey(n *Notification) string {
+	h := sha256.New()
+	h.Write([]byte(n.Channel))
+	h.Write([]byte{0})
+	// Recipients are normalized with the phone region when notifications are created
+	h.Write([]byte(SuppressionRecipient(n.Channel, n.Recipient, "")))
+	h.Write([]byte{0})
+	h.Write([]byte(n.Content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DedupEntry records that the notification NotificationID was created
+// with the content hash Key for TTL
+type DedupEntry struct {
+	Key            string
+	NotificationID uuid.UUID
+	TTL            time.Duration
+}
+
+// DedupStore stores the content hashes of recently created notifications
+type DedupStore interface {
+	// Claim stores every entry whose key is not stored yet. It returns, in
+	// the order of entries, the notification ID already stored under each
+	// key, or uuid.Nil for the entries it stored.
+	Claim(ctx context.Context, entries []*DedupEntry) ([]uuid.UUID, error)
+	// Release removes the entries whose key still belongs to their
+	// notification
+	Release(ctx context.Context, entries []*DedupEntry) error
+}
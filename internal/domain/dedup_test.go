@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupKey(t *testing.T) {
+	key := DedupKey(NewNotification("User@Example.com", ChannelEmail, "Welcome"))
+
+	assert.Equal(t, key, DedupKey(NewNotification("user@example.com ", ChannelEmail, "Welcome")))
+	assert.NotEqual(t, key, DedupKey(NewNotification("user@example.com", ChannelEmail, "Welcome!")))
+	assert.NotEqual(t, key, DedupKey(NewNotification("other@example.com", ChannelEmail, "Welcome")))
+	assert.NotEqual(t,
+		DedupKey(NewNotification("+905551234567", ChannelSMS, "Hi")),
+		DedupKey(NewNotification("+905551234567", ChannelPush, "Hi")))
+}
+
+func TestDedupPolicy_Window(t *testing.T) {
+	p := DedupPolicy{Windows: map[Channel]time.Duration{ChannelSMS: time.Minute, ChannelEmail: 0}}
+
+	window, ok := p.Window(ChannelSMS)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, window)
+
+	_, ok = p.Window(ChannelEmail)
+	assert.False(t, ok)
+	_, ok = p.Window(ChannelPush)
+	assert.False(t, ok)
+}
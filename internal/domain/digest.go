@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest rule limits
+const (
+	MaxDigestWindow   = 24 * 60 * 60
+	MaxDigestMaxCount = 1000
+)
+
+// DigestCountMetadataKey is the metadata key set to the number of merged
+// notifications on a digest notification
+const DigestCountMetadataKey = "digest_count"
+
+// DigestVariables are the variables a digest template may use:
+//   - count: number of notifications merged into the digest
+//   - items: their contents, one per line, oldest first
+//   - first: content of the oldest notification
+//   - latest: content of the newest notification
+//   - category: category of the notifications
+var DigestVariables = []string{"count", "items", "first", "latest", "category"}
+
+// DigestRule holds the notifications of a category sent to a recipient on a
+// channel and merges them into a single digest rendered from TemplateName.
+// A digest is sent Window seconds after its oldest notification was held,
+// or as soon as MaxCount notifications are held.
+type DigestRule struct {
+	ID           uuid.UUID `json:"id"`
+	Category     string    `json:"category"`
+	Channel      Channel   `json:"channel"`
+	Window       int       `json:"window"`
+	MaxCount     int       `json:"max_count"`
+	TemplateName string    `json:"template_name"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewDigestRule creates a new digest rule
+func NewDigestRule(category string, channel Channel, window, maxCount int, templateName string) *DigestRule {
+	now := time.Now().UTC()
+	return &DigestRule{
+		ID:           uuid.New(),
+		Category:     category,
+		Channel:      channel,
+		Window:       window,
+		MaxCount:     maxCount,
+		TemplateName: templateName,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Validate checks the rule's fields, but not that its template exists
+func (r *DigestRule) Validate() error {
+	if r.Category == "" {
+		return NewValidationError("category", "category is required")
+	}
+	if err := ValidateCategory(r.Category); err != nil {
+		return err
+	}
+	if !r.Channel.IsValid() {
+		return NewValidationError("channel", "invalid channel")
+	}
+	if r.Window < 1 || r.Window > MaxDigestWindow {
+		return NewValidationError("window", fmt.Sprintf("window must be between 1 and %d seconds", MaxDigestWindow))
+	}
+	if r.MaxCount < 2 || r.MaxCount > MaxDigestMaxCount {
+		return NewValidationError("max_count", fmt.Sprintf("max_count must be between 2 and %d", MaxDigestMaxCount))
+	}
+	if r.TemplateName == "" {
+		return NewValidationError("template_name", "template_name is required")
+	}
+	return nil
+}
+
+// ValidateDigestTemplate checks that t only uses DigestVariables
+func ValidateDigestTemplate(t *Template) error {
+	unknown := make([]string, 0)
+	for _, v := range t.Variables {
+		if !isDigestVariable(v) {
+			unknown = append(unknown, v)
+		}
+	}
+	if len(unknown) > 0 {
+		return NewValidationError("template_name",
+			fmt.Sprintf("digest templates may only use %s, got %s",
+				strings.Join(DigestVariables, ", "), strings.Join(unknown, ", ")))
+	}
+	return nil
+}
+
+func isDigestVariable(name string) bool {
+	for _, v := range DigestVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestVars returns the template variables of a digest merging held,
+// which must be ordered oldest first
+func DigestVars(category string, held []*Notification) map[string]string {
+	items := make([]string, 0, len(held))
+	for _, n := range held {
+		items = append(items, n.Content)
+	}
+
+	vars := map[string]string{
+		"count":    strconv.Itoa(len(held)),
+		"items":    strings.Join(items, "\n"),
+		"category": category,
+	}
+	if len(items) > 0 {
+		vars["first"] = items[0]
+		vars["latest"] = items[len(items)-1]
+	}
+	return vars
+}
+
+// MarkAsHeld holds a pending notification until its digest is sent
+func (n *Notification) MarkAsHeld() error {
+	return n.transition(StatusHeld)
+}
+
+// MarkAsDigested records that a held notification was merged into the
+// digest notification digestID
+func (n *Notification) MarkAsDigested(digestID uuid.UUID) error {
+	if err := n.transition(StatusDigested); err != nil {
+		return err
+	}
+	n.DigestID = &digestID
+	return nil
+}
+
+// DigestGroup identifies the held notifications merged into one digest
+type DigestGroup struct {
+	Recipient string
+	Channel   Channel
+	Category  string
+}
+
+// DigestRuleRepository stores digest rules
+type DigestRuleRepository interface {
+	Create(ctx context.Context, rule *DigestRule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*DigestRule, error)
+	// GetFor returns the rule of a category and channel, or ErrNotFound
+	GetFor(ctx context.Context, category string, channel Channel) (*DigestRule, error)
+	List(ctx context.Context) ([]*DigestRule, error)
+	Update(ctx context.Context, rule *DigestRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDue returns the groups of held notifications whose digest is due
+	// at t: those whose window has passed, that reached the rule's max
+	// count, or whose rule was deleted. Oldest groups come first.
+	ListDue(ctx context.Context, t time.Time, limit int) ([]*DigestGroup, error)
+}
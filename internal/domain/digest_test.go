@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestRule_Validate(t *testing.T) {
+	assert.NoError(t, NewDigestRule("comment", ChannelPush, 300, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("", ChannelPush, 300, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule(strings.Repeat("c", MaxCategoryLength+1), ChannelPush, 300, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("comment", Channel("fax"), 300, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("comment", ChannelPush, 0, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("comment", ChannelPush, MaxDigestWindow+1, 20, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("comment", ChannelPush, 300, 1, "comment_digest").Validate())
+	assert.Error(t, NewDigestRule("comment", ChannelPush, 300, 20, "").Validate())
+}
+
+func TestValidateDigestTemplate(t *testing.T) {
+	assert.NoError(t, ValidateDigestTemplate(NewTemplate("d", ChannelPush, "{{count}} new {{category}}: {{latest}}")))
+	assert.Error(t, ValidateDigestTemplate(NewTemplate("d", ChannelPush, "{{count}} from {{author}}")))
+}
+
+func TestDigestVars(t *testing.T) {
+	held := []*Notification{
+		NewNotification("device-token-1", ChannelPush, "Ayse commented"),
+		NewNotification("device-token-1", ChannelPush, "Mehmet commented"),
+	}
+
+	assert.Equal(t, map[string]string{
+		"count":    "2",
+		"items":    "Ayse commented\nMehmet commented",
+		"first":    "Ayse commented",
+		"latest":   "Mehmet commented",
+		"category": "comment",
+	}, DigestVars("comment", held))
+}
+
+func TestNotification_MarkAsDigested(t *testing.T) {
+	n := NewNotification("device-token-1", ChannelPush, "Ayse commented")
+	digestID := uuid.New()
+
+	assert.ErrorIs(t, n.MarkAsDigested(digestID), ErrInvalidStatus)
+
+	require.NoError(t, n.MarkAsHeld())
+	require.NoError(t, n.MarkAsDigested(digestID))
+	assert.Equal(t, StatusDigested, n.Status)
+	assert.Equal(t, digestID, *n.DigestID)
+	assert.True(t, n.Status.IsFinal())
+}
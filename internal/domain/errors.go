@@ -18,6 +18,11 @@ var (
 	ErrBatchSizeExceeded   = errors.New("batch size exceeded maximum limit")
 	ErrIdempotencyConflict = errors.New("idempotency key conflict")
 	ErrProviderError       = errors.New("external provider error")
+	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrVersionConflict     = errors.New("resource was modified concurrently")
+	ErrRecipientNotFound   = errors.New("recipient not found")
+	ErrNoAddress           = errors.New("recipient has no address for channel")
+	ErrDuplicateContent    = errors.New("duplicate notification content")
 )
 
 type ValidationError struct {
@@ -48,6 +53,8 @@ type ProviderError struct {
 	StatusCode int
 	Message    string
 	Retryable  bool
+	// Provider is the name of the provider that returned the error
+	Provider string
 }
 
 func (e ProviderError) Error() string {
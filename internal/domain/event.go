@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusEvent records one status transition of a notification
+type StatusEvent struct {
+	ID             uuid.UUID `json:"id"`
+	NotificationID uuid.UUID `json:"notification_id"`
+	// FromStatus is empty for the event that records the notification's creation
+	FromStatus Status `json:"from_status,omitempty"`
+	ToStatus   Status `json:"to_status"`
+	// Actor is the component that made the transition, such as api or a worker
+	Actor string `json:"actor,omitempty"`
+	// Instance is the service instance the actor ran on
+	Instance string  `json:"instance,omitempty"`
+	Error    *string `json:"error,omitempty"`
+	// Reason explains a transition made on request, such as a manual retry
+	Reason        *string   `json:"reason,omitempty"`
+	CorrelationID *string   `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type eventContextKey string
+
+const (
+	actorKey         eventContextKey = "actor"
+	correlationIDKey eventContextKey = "correlation_id"
+)
+
+// WithActor returns a context whose status events are attributed to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor stored in ctx
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithCorrelationID returns a context whose status events carry correlationID
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, correlationID)
+}
+
+// CorrelationIDFrom returns the correlation ID stored in ctx
+func CorrelationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// recordEvent appends a status event for a transition from -> n.Status
+func (n *Notification) recordEvent(from Status) {
+	event := &StatusEvent{
+		ID:             uuid.New(),
+		NotificationID: n.ID,
+		FromStatus:     from,
+		ToStatus:       n.Status,
+		CreatedAt:      n.UpdatedAt,
+	}
+	n.events = append(n.events, event)
+	n.lastEvent = event
+}
+
+// recordError attaches msg to the most recent status event
+func (n *Notification) recordError(msg string) {
+	if n.lastEvent != nil {
+		n.lastEvent.Error = &msg
+	}
+}
+
+// recordReason attaches reason to the most recent status event
+func (n *Notification) recordReason(reason string) {
+	if n.lastEvent != nil {
+		n.lastEvent.Reason = &reason
+	}
+}
+
+// PendingEvents returns the status events recorded since the notification
+// was last saved
+func (n *Notification) PendingEvents() []*StatusEvent {
+	return n.events
+}
+
+// ClearPendingEvents is called by repositories once the pending events are stored
+func (n *Notification) ClearPendingEvents() {
+	n.events = nil
+}
+
+// LastEvent returns the most recent status event recorded in this process, or
+// nil if the status has not changed since the notification was loaded
+func (n *Notification) LastEvent() *StatusEvent {
+	return n.lastEvent
+}
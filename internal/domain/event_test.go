@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotification_StatusEvents(t *testing.T) {
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+	require.Len(t, n.PendingEvents(), 1)
+	assert.Equal(t, Status(""), n.PendingEvents()[0].FromStatus)
+	assert.Equal(t, StatusPending, n.PendingEvents()[0].ToStatus)
+
+	n.ClearPendingEvents()
+	require.NoError(t, n.MarkAsQueued())
+	require.NoError(t, n.MarkAsProcessing())
+	require.NoError(t, n.MarkForRetry("timeout"))
+
+	events := n.PendingEvents()
+	require.Len(t, events, 3)
+	assert.Equal(t, StatusQueued, events[1].FromStatus)
+	assert.Equal(t, StatusProcessing, events[1].ToStatus)
+	assert.Equal(t, n.ID, events[2].NotificationID)
+	assert.Equal(t, "timeout", *events[2].Error)
+	assert.Same(t, events[2], n.LastEvent())
+
+	// Rejected transitions are not recorded
+	assert.Error(t, n.MarkAsDelivered(n.UpdatedAt))
+	assert.Len(t, n.PendingEvents(), 3)
+
+	n.ClearPendingEvents()
+	assert.Empty(t, n.PendingEvents())
+	assert.NotNil(t, n.LastEvent())
+}
+
+func TestEventContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, ActorFrom(ctx))
+	assert.Empty(t, CorrelationIDFrom(ctx))
+
+	ctx = WithCorrelationID(WithActor(ctx, "worker sms-1"), "corr-1")
+	assert.Equal(t, "worker sms-1", ActorFrom(ctx))
+	assert.Equal(t, "corr-1", CorrelationIDFrom(ctx))
+}
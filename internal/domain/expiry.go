@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+)
+
+// ExpiryPolicy holds the default time to live of notifications that do not
+// set their own expiry. A category default takes precedence over the
+// channel default.
+type ExpiryPolicy struct {
+	Channels   map[Channel]time.Duration
+	Categories map[string]time.Duration
+}
+
+// DefaultTTL returns the time to live for a notification on channel with
+// category, and false when neither has a default
+func (p ExpiryPolicy) DefaultTTL(channel Channel, category string) (time.Duration, bool) {
+	if ttl, ok := p.Categories[category]; ok && category != "" && ttl > 0 {
+		return ttl, true
+	}
+	if ttl, ok := p.Channels[channel]; ok && ttl > 0 {
+		return ttl, true
+	}
+	return 0, false
+}
+
+// SetExpiry sets when the notification expires. An explicit expiresAt wins
+// over ttl, which wins over the policy default; ttl and policy defaults
+// count from the scheduled time, or from now for immediate notifications.
+func (n *Notification) SetExpiry(expiresAt *time.Time, ttl time.Duration, policy ExpiryPolicy) error {
+	if expiresAt != nil && ttl > 0 {
+		return NewValidationError("ttl", "set either ttl or expires_at, not both")
+	}
+	if ttl < 0 {
+		return NewValidationError("ttl", "ttl must be positive")
+	}
+
+	start := time.Now().UTC()
+	if n.ScheduledAt != nil {
+		start = n.ScheduledAt.UTC()
+	}
+
+	if expiresAt == nil {
+		if ttl == 0 {
+			var ok bool
+			if ttl, ok = policy.DefaultTTL(n.Channel, n.Category); !ok {
+				return nil
+			}
+		}
+		t := start.Add(ttl)
+		expiresAt = &t
+	}
+
+	if !expiresAt.After(start) {
+		if n.ScheduledAt != nil {
+			return NewValidationError("expires_at", "expiry must be after the scheduled time")
+		}
+		return NewValidationError("expires_at", "expiry must be in the future")
+	}
+
+	t := expiresAt.UTC()
+	n.ExpiresAt = &t
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotification_SetExpiry(t *testing.T) {
+	policy := ExpiryPolicy{
+		Channels:   map[Channel]time.Duration{ChannelSMS: time.Hour},
+		Categories: map[string]time.Duration{"otp": 10 * time.Minute},
+	}
+
+	t.Run("category default wins over channel default", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Your code is 4821")
+		n.Category = "otp"
+		require.NoError(t, n.SetExpiry(nil, 0, policy))
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *n.ExpiresAt, time.Second)
+	})
+
+	t.Run("channel default", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		require.NoError(t, n.SetExpiry(nil, 0, policy))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *n.ExpiresAt, time.Second)
+	})
+
+	t.Run("no default", func(t *testing.T) {
+		n := NewNotification("user@example.com", ChannelEmail, "Test")
+		require.NoError(t, n.SetExpiry(nil, 0, policy))
+		assert.Nil(t, n.ExpiresAt)
+	})
+
+	t.Run("ttl counts from the scheduled time", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		scheduledAt := time.Now().Add(2 * time.Hour).UTC()
+		require.NoError(t, n.MarkAsScheduled(scheduledAt))
+		require.NoError(t, n.SetExpiry(nil, 5*time.Minute, policy))
+		assert.Equal(t, scheduledAt.Add(5*time.Minute), *n.ExpiresAt)
+	})
+
+	t.Run("expiry before the scheduled time is rejected", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		require.NoError(t, n.MarkAsScheduled(time.Now().Add(2*time.Hour)))
+		expiresAt := time.Now().Add(time.Hour)
+		assert.Error(t, n.SetExpiry(&expiresAt, 0, policy))
+	})
+
+	t.Run("past expiry is rejected", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		expiresAt := time.Now().Add(-time.Minute)
+		assert.Error(t, n.SetExpiry(&expiresAt, 0, policy))
+	})
+}
+
+func TestNotification_MarkAsExpired(t *testing.T) {
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+	expiresAt := time.Now().Add(-time.Second)
+	n.ExpiresAt = &expiresAt
+	assert.True(t, n.IsExpired(time.Now()))
+
+	require.NoError(t, n.MarkAsQueued())
+	require.NoError(t, n.MarkAsExpired())
+	assert.Equal(t, StatusExpired, n.Status)
+	assert.True(t, n.Status.IsFinal())
+	assert.NotNil(t, n.ErrorMessage)
+
+	assert.ErrorIs(t, n.MarkAsProcessing(), ErrInvalidStatus)
+}
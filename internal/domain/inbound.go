@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// InboundAction is what an inbound message asks of the sender's subscription
+type InboundAction string
+
+const (
+	InboundActionNone   InboundAction = ""
+	InboundActionOptOut InboundAction = "opt_out"
+	InboundActionOptIn  InboundAction = "opt_in"
+)
+
+// InboundMessage is a message sent by a recipient to one of our numbers
+type InboundMessage struct {
+	ID         uuid.UUID     `json:"id"`
+	Provider   string        `json:"provider"`
+	ExternalID *string       `json:"external_id,omitempty"`
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	Body       string        `json:"body"`
+	Action     InboundAction `json:"action,omitempty"`
+	ReceivedAt time.Time     `json:"received_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// NewInboundMessage creates a new inbound message
+func NewInboundMessage(provider, from, to, body string) *InboundMessage {
+	now := time.Now().UTC()
+	return &InboundMessage{
+		ID:         uuid.New(),
+		Provider:   provider,
+		From:       from,
+		To:         to,
+		Body:       body,
+		ReceivedAt: now,
+		CreatedAt:  now,
+	}
+}
+
+// InboundKeywords recognises opt-out and opt-in replies such as STOP and START
+type InboundKeywords struct {
+	optOut map[string]bool
+	optIn  map[string]bool
+}
+
+// NewInboundKeywords creates a keyword matcher; keywords are case-insensitive
+func NewInboundKeywords(optOut, optIn []string) *InboundKeywords {
+	k := &InboundKeywords{
+		optOut: make(map[string]bool, len(optOut)),
+		optIn:  make(map[string]bool, len(optIn)),
+	}
+	for _, keyword := range optOut {
+		k.optOut[strings.ToUpper(keyword)] = true
+	}
+	for _, keyword := range optIn {
+		k.optIn[strings.ToUpper(keyword)] = true
+	}
+	return k
+}
+
+// Classify returns the action of a message whose whole body is a keyword,
+// ignoring case, surrounding whitespace and punctuation
+func (k *InboundKeywords) Classify(body string) InboundAction {
+	word := strings.ToUpper(strings.TrimFunc(body, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}))
+
+	switch {
+	case k.optOut[word]:
+		return InboundActionOptOut
+	case k.optIn[word]:
+		return InboundActionOptIn
+	}
+	return InboundActionNone
+}
+
+type InboundFilter struct {
+	From      *string
+	Provider  *string
+	Action    *InboundAction
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	PageSize  int
+}
+
+type InboundListResult struct {
+	Messages   []*InboundMessage `json:"messages"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+type InboundRepository interface {
+	Create(ctx context.Context, message *InboundMessage) error
+	GetByID(ctx context.Context, id uuid.UUID) (*InboundMessage, error)
+	List(ctx context.Context, filter InboundFilter) (*InboundListResult, error)
+}
+
+// InboundParser authenticates and decodes the inbound messages a provider posts
+type InboundParser interface {
+	Verify(header http.Header, body []byte) error
+	Parse(body []byte) ([]*InboundMessage, error)
+}
+
+// InboundForwarder delivers inbound messages to the customer callback
+type InboundForwarder interface {
+	Forward(ctx context.Context, message *InboundMessage) error
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboundKeywords_Classify(t *testing.T) {
+	keywords := NewInboundKeywords([]string{"STOP", "unsubscribe"}, []string{"START"})
+
+	assert.Equal(t, InboundActionOptOut, keywords.Classify("STOP"))
+	assert.Equal(t, InboundActionOptOut, keywords.Classify("  stop. "))
+	assert.Equal(t, InboundActionOptOut, keywords.Classify("Unsubscribe"))
+	assert.Equal(t, InboundActionOptIn, keywords.Classify("start!"))
+	assert.Equal(t, InboundActionNone, keywords.Classify("please stop texting me"))
+	assert.Equal(t, InboundActionNone, keywords.Classify(""))
+}
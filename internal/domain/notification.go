@@ -2,7 +2,10 @@ package domain
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -64,8 +67,81 @@ const (
 	StatusDelivered  Status = "delivered"
 	StatusFailed     Status = "failed"
 	StatusCancelled  Status = "cancelled"
+	// StatusUndeliverable means the provider accepted the message but reported it could not be delivered
+	StatusUndeliverable Status = "undeliverable"
+	// StatusSuppressed means the notification was not sent because its recipient is on the suppression list
+	StatusSuppressed Status = "suppressed"
+	// StatusExpired means the notification reached its expiry time before it could be sent
+	StatusExpired Status = "expired"
+	// StatusHeld means the notification is waiting for its digest window to close
+	StatusHeld Status = "held"
+	// StatusDigested means the notification was merged into the digest notification DigestID
+	StatusDigested Status = "digested"
 )
 
+// statusTransitions lists the statuses each status may move to. Statuses
+// without an entry are final.
+var statusTransitions = map[Status][]Status{
+	// A worker may dequeue a notification before its creator or the
+	// scheduler has recorded it as queued, so processing is reachable from
+	// pending and scheduled too
+	StatusPending:   {StatusScheduled, StatusQueued, StatusProcessing, StatusSuppressed, StatusCancelled, StatusExpired, StatusHeld},
+	StatusScheduled: {StatusQueued, StatusProcessing, StatusSuppressed, StatusCancelled, StatusExpired},
+	// queued -> queued and queued -> failed happen when an attempt fails
+	// before the notification reaches processing; queued -> scheduled when
+	// it is rescheduled before a worker picks it up
+	StatusQueued:     {StatusQueued, StatusScheduled, StatusProcessing, StatusSuppressed, StatusCancelled, StatusFailed, StatusExpired},
+	StatusProcessing: {StatusSent, StatusQueued, StatusFailed, StatusExpired},
+	StatusSent:       {StatusDelivered, StatusUndeliverable},
+	// A held notification is queued on its own when its digest would
+	// contain nothing else, and expires if its expiry passes while held
+	StatusHeld: {StatusDigested, StatusQueued, StatusCancelled, StatusExpired},
+}
+
+// CanTransitionTo reports whether a notification may move from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesBefore returns the statuses that may move to next
+func StatusesBefore(next Status) []Status {
+	statuses := make([]Status, 0)
+	for from := range statusTransitions {
+		if from.CanTransitionTo(next) {
+			statuses = append(statuses, from)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+	return statuses
+}
+
+// IsFinal reports whether no further transitions are allowed from s
+func (s Status) IsFinal() bool {
+	return len(statusTransitions[s]) == 0
+}
+
+// FinalStatuses returns the statuses no transition leaves, sorted
+func FinalStatuses() []Status {
+	all := []Status{
+		StatusPending, StatusScheduled, StatusQueued, StatusProcessing, StatusSent, StatusDelivered,
+		StatusFailed, StatusCancelled, StatusUndeliverable, StatusSuppressed, StatusExpired,
+		StatusHeld, StatusDigested,
+	}
+	statuses := make([]Status, 0)
+	for _, s := range all {
+		if s.IsFinal() {
+			statuses = append(statuses, s)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+	return statuses
+}
+
 // Notification represents a notification entity
 type Notification struct {
 	ID             uuid.UUID      `json:"id"`
@@ -77,64 +153,249 @@ type Notification struct {
 	Status         Status         `json:"status"`
 	ScheduledAt    *time.Time     `json:"scheduled_at,omitempty"`
 	SentAt         *time.Time     `json:"sent_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
 	ExternalID     *string        `json:"external_id,omitempty"`
 	RetryCount     int            `json:"retry_count"`
 	IdempotencyKey *string        `json:"idempotency_key,omitempty"`
 	Metadata       map[string]any `json:"metadata,omitempty"`
 	ErrorMessage   *string        `json:"error_message,omitempty"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+	Provider       *string        `json:"provider,omitempty"`
+	Segments       int            `json:"segments,omitempty"`
+	Cost           *float64       `json:"cost,omitempty"`
+	Currency       *string        `json:"currency,omitempty"`
+	Opens          int            `json:"opens"`
+	Clicks         int            `json:"clicks"`
+	// Category classifies the message, e.g. otp or marketing, and selects default settings
+	Category string `json:"category,omitempty"`
+	// ExpiresAt is the time after which the notification is no longer sent
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// TimeZone is the recipient's IANA time zone used for quiet hours
+	TimeZone string `json:"timezone,omitempty"`
+	// DigestID is the digest notification a digested notification was merged into
+	DigestID *uuid.UUID `json:"digest_id,omitempty"`
+	// Version is incremented on every update and used to detect concurrent writes
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	events    []*StatusEvent
+	lastEvent *StatusEvent
 }
 
 func NewNotification(recipient string, channel Channel, content string) *Notification {
 	now := time.Now().UTC()
-	return &Notification{
+	n := &Notification{
 		ID:        uuid.New(),
 		Recipient: recipient,
 		Channel:   channel,
 		Content:   content,
 		Priority:  PriorityNormal,
 		Status:    StatusPending,
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	n.recordEvent("")
+	return n
 }
 
 func (n *Notification) CanCancel() bool {
-	return n.Status == StatusPending || n.Status == StatusScheduled || n.Status == StatusQueued
+	return n.Status.CanTransitionTo(StatusCancelled)
 }
 
-// MarkAsQueued updates the notification status to queued
-func (n *Notification) MarkAsQueued() {
-	n.Status = StatusQueued
+// CanEdit reports whether the notification may still be changed, which is
+// the case until a worker starts processing it
+func (n *Notification) CanEdit() bool {
+	switch n.Status {
+	case StatusPending, StatusScheduled, StatusQueued:
+		return true
+	}
+	return false
+}
+
+// transition moves the notification to status, rejecting moves the
+// transition table does not allow
+func (n *Notification) transition(status Status) error {
+	if !n.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatus, n.Status, status)
+	}
+	from := n.Status
+	n.Status = status
 	n.UpdatedAt = time.Now().UTC()
+	n.recordEvent(from)
+	return nil
 }
 
-// MarkAsProcessing updates the notification status to processing
-func (n *Notification) MarkAsProcessing() {
-	n.Status = StatusProcessing
+// MarkAsScheduled updates the notification status to scheduled
+func (n *Notification) MarkAsScheduled(scheduledAt time.Time) error {
+	if err := n.transition(StatusScheduled); err != nil {
+		return err
+	}
+	n.ScheduledAt = &scheduledAt
+	return nil
+}
+
+// Reschedule moves a scheduled notification to a new send time
+func (n *Notification) Reschedule(scheduledAt time.Time) error {
+	if n.Status != StatusScheduled {
+		return fmt.Errorf("%w: cannot reschedule a %s notification", ErrInvalidStatus, n.Status)
+	}
+	n.ScheduledAt = &scheduledAt
 	n.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
-// MarkAsSent updates the notification status to sent
-func (n *Notification) MarkAsSent(externalID string) {
-	n.Status = StatusSent
-	n.ExternalID = &externalID
-	now := time.Now().UTC()
-	n.SentAt = &now
-	n.UpdatedAt = now
+// ScheduleFor sets the send time of a notification that has not been picked
+// up yet, scheduling it if it was waiting to be sent immediately
+func (n *Notification) ScheduleFor(scheduledAt time.Time) error {
+	if n.Status == StatusScheduled {
+		return n.Reschedule(scheduledAt)
+	}
+	return n.MarkAsScheduled(scheduledAt)
+}
+
+// MarkAsQueued updates the notification status to queued
+func (n *Notification) MarkAsQueued() error {
+	return n.transition(StatusQueued)
+}
+
+// MarkForRetry puts the notification back in the queue after a failed
+// attempt, recording why the attempt failed
+func (n *Notification) MarkForRetry(reason string) error {
+	if err := n.transition(StatusQueued); err != nil {
+		return err
+	}
+	n.recordError(reason)
+	return nil
+}
+
+// MarkAsProcessing updates the notification status to processing
+func (n *Notification) MarkAsProcessing() error {
+	return n.transition(StatusProcessing)
+}
+
+// MarkAsSent updates the notification status to sent. An empty externalID
+// leaves the external ID unset.
+func (n *Notification) MarkAsSent(externalID string) error {
+	if err := n.transition(StatusSent); err != nil {
+		return err
+	}
+	if externalID != "" {
+		n.ExternalID = &externalID
+	}
+	sentAt := n.UpdatedAt
+	n.SentAt = &sentAt
+	return nil
+}
+
+// MarkAsDelivered updates the notification status to delivered
+func (n *Notification) MarkAsDelivered(deliveredAt time.Time) error {
+	if err := n.transition(StatusDelivered); err != nil {
+		return err
+	}
+	deliveredAt = deliveredAt.UTC()
+	n.DeliveredAt = &deliveredAt
+	return nil
+}
+
+// MarkAsUndeliverable updates the notification status to undeliverable
+func (n *Notification) MarkAsUndeliverable(reason string) error {
+	if err := n.transition(StatusUndeliverable); err != nil {
+		return err
+	}
+	n.recordError(reason)
+	n.ErrorMessage = &reason
+	return nil
 }
 
 // MarkAsFailed updates the notification status to failed
-func (n *Notification) MarkAsFailed(errorMsg string) {
-	n.Status = StatusFailed
+func (n *Notification) MarkAsFailed(errorMsg string) error {
+	if err := n.transition(StatusFailed); err != nil {
+		return err
+	}
+	n.recordError(errorMsg)
 	n.ErrorMessage = &errorMsg
-	n.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
-func (n *Notification) MarkAsCancelled() {
-	n.Status = StatusCancelled
-	n.UpdatedAt = time.Now().UTC()
+// MarkAsExpired updates the notification status to expired
+func (n *Notification) MarkAsExpired() error {
+	if err := n.transition(StatusExpired); err != nil {
+		return err
+	}
+	reason := "expired before it could be sent"
+	n.recordError(reason)
+	n.ErrorMessage = &reason
+	return nil
+}
+
+// IsExpired reports whether the notification must no longer be sent at t
+func (n *Notification) IsExpired(t time.Time) bool {
+	return n.ExpiresAt != nil && !t.Before(*n.ExpiresAt)
+}
+
+// RemainingTTL returns how long after t the notification may still be
+// delivered, or zero when it has no expiry
+func (n *Notification) RemainingTTL(t time.Time) time.Duration {
+	if n.ExpiresAt == nil {
+		return 0
+	}
+	return n.ExpiresAt.Sub(t)
+}
+
+// MarkAsCancelled updates the notification status to cancelled. It returns
+// ErrCannotCancel once the notification has been picked up for sending.
+func (n *Notification) MarkAsCancelled() error {
+	if err := n.transition(StatusCancelled); err != nil {
+		return ErrCannotCancel
+	}
+	return nil
+}
+
+// MaxCategoryLength is the longest category a notification can have
+const MaxCategoryLength = 50
+
+// ValidateCategory checks that category fits a notification
+func ValidateCategory(category string) error {
+	if utf8.RuneCountInString(category) > MaxCategoryLength {
+		return NewValidationError("category", fmt.Sprintf("category must be at most %d characters", MaxCategoryLength))
+	}
+	return nil
+}
+
+// RetryReasonManual is the reason recorded on the failed -> queued event of
+// a retry requested through the API
+const RetryReasonManual = "manual retry"
+
+// Retry queues a failed notification again with its retry count reset, so
+// it gets the full number of automatic attempts. A non-nil priority
+// replaces the original one. Failed stays final in the transition table so
+// that only an explicit retry can revive a notification.
+func (n *Notification) Retry(priority *Priority, now time.Time) error {
+	if n.Status != StatusFailed {
+		return fmt.Errorf("%w: cannot retry a %s notification", ErrInvalidStatus, n.Status)
+	}
+	if n.IsExpired(now) {
+		return fmt.Errorf("%w: notification expired at %s", ErrInvalidStatus, n.ExpiresAt.Format(time.RFC3339))
+	}
+	n.Status = StatusQueued
+	n.UpdatedAt = now.UTC()
+	n.recordEvent(StatusFailed)
+	n.recordReason(RetryReasonManual)
+	n.RetryCount = 0
+	n.ErrorMessage = nil
+	if priority != nil {
+		n.Priority = *priority
+	}
+	return nil
+}
+
+// RecordCost records the provider that accepted the notification and what it cost
+func (n *Notification) RecordCost(provider string, quote Quote) {
+	n.Provider = &provider
+	n.Segments = quote.Units
+	n.Cost = &quote.Amount
+	n.Currency = &quote.Currency
 }
 
 func (n *Notification) IncrementRetry() {
@@ -148,8 +409,58 @@ type NotificationFilter struct {
 	BatchID   *uuid.UUID
 	StartDate *time.Time
 	EndDate   *time.Time
-	Page      int
-	PageSize  int
+	// Metadata matches notifications whose metadata contains every key and value
+	Metadata map[string]any
+	Page     int
+	PageSize int
+}
+
+// IsEmpty reports whether the filter matches every notification
+func (f NotificationFilter) IsEmpty() bool {
+	return f.Status == nil && f.Channel == nil && f.BatchID == nil &&
+		f.StartDate == nil && f.EndDate == nil && len(f.Metadata) == 0
+}
+
+// BulkCancelResult reports the outcome of cancelling notifications in bulk
+type BulkCancelResult struct {
+	Cancelled int64 `json:"cancelled"`
+	// InFlight counts matching notifications a worker was already processing
+	InFlight int64 `json:"in_flight"`
+	// Dequeued counts cancelled notifications taken out of the queue
+	Dequeued int64 `json:"dequeued"`
+	// QueueItems are the queue entries of cancelled notifications that were
+	// waiting in the queue
+	QueueItems []*QueueItem `json:"-"`
+}
+
+// RetryFilter selects failed notifications to retry
+type RetryFilter struct {
+	Channel *Channel
+	BatchID *uuid.UUID
+	// FailedAfter and FailedBefore bound the time the notification failed
+	FailedAfter  *time.Time
+	FailedBefore *time.Time
+	// Provider and ProviderStatus match the provider and HTTP status of the
+	// notification's last delivery attempt
+	Provider       *string
+	ProviderStatus *int
+	// Metadata matches notifications whose metadata contains every key and value
+	Metadata map[string]any
+}
+
+// IsEmpty reports whether the filter matches every failed notification
+func (f RetryFilter) IsEmpty() bool {
+	return f.Channel == nil && f.BatchID == nil && f.FailedAfter == nil && f.FailedBefore == nil &&
+		f.Provider == nil && f.ProviderStatus == nil && len(f.Metadata) == 0
+}
+
+// BulkRetryResult reports the outcome of retrying notifications in bulk
+type BulkRetryResult struct {
+	Retried int64 `json:"retried"`
+	// Expired counts matching notifications skipped because they expired
+	Expired int64 `json:"expired"`
+	// QueueItems are the queue entries of the retried notifications
+	QueueItems []*QueueItem `json:"-"`
 }
 
 type NotificationListResult struct {
@@ -166,9 +477,32 @@ type NotificationRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Notification, error)
 	GetByBatchID(ctx context.Context, batchID uuid.UUID) ([]*Notification, error)
 	GetByIdempotencyKey(ctx context.Context, key string) (*Notification, error)
+	// Update stores notification if its Version still matches the stored
+	// row, incrementing Version. It returns ErrVersionConflict otherwise.
 	Update(ctx context.Context, notification *Notification) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filter NotificationFilter) (*NotificationListResult, error)
 	GetScheduledNotifications(ctx context.Context, before time.Time, limit int) ([]*Notification, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status Status) error
+	GetByExternalID(ctx context.Context, provider, externalID string) (*Notification, error)
+	// ListStatusEvents returns the status history of a notification, oldest first
+	ListStatusEvents(ctx context.Context, notificationID uuid.UUID) ([]*StatusEvent, error)
+	ClaimStatusChecks(ctx context.Context, providers []string, sentAfter, checkedBefore time.Time, limit int) ([]*Notification, error)
+	// CancelMatching cancels every notification matching filter that can
+	// still be cancelled in a single statement, recording its status events
+	CancelMatching(ctx context.Context, filter NotificationFilter) (*BulkCancelResult, error)
+	// RetryMatching moves every failed notification matching filter that
+	// has not expired back to queued in a single statement, resetting its
+	// retry count and, when priority is non-nil, overriding its priority
+	RetryMatching(ctx context.Context, filter RetryFilter, priority *Priority) (*BulkRetryResult, error)
+	// RevertRetry moves the notifications among ids that are still queued
+	// back to failed with errorMsg, undoing a retry whose queue entries could
+	// not be added. It returns the number of notifications moved.
+	RevertRetry(ctx context.Context, ids []uuid.UUID, errorMsg string) (int64, error)
+	// ListHeld returns up to limit held notifications of a digest group,
+	// oldest first
+	ListHeld(ctx context.Context, group DigestGroup, limit int) ([]*Notification, error)
+	// Digest creates the digest notification and stores the digested
+	// originals in a single transaction. It returns ErrVersionConflict if
+	// any original changed since it was read.
+	Digest(ctx context.Context, digest *Notification, originals []*Notification) error
 }
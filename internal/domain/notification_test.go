@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChannel_IsValid(t *testing.T) {
@@ -116,34 +117,159 @@ func TestNotification_StatusTransitions(t *testing.T) {
 	time.Sleep(time.Millisecond)
 
 	// Test MarkAsQueued
-	n.MarkAsQueued()
+	require.NoError(t, n.MarkAsQueued())
 	assert.Equal(t, StatusQueued, n.Status)
 	assert.True(t, n.UpdatedAt.After(originalUpdatedAt))
 
 	// Test MarkAsProcessing
-	n.MarkAsProcessing()
+	require.NoError(t, n.MarkAsProcessing())
 	assert.Equal(t, StatusProcessing, n.Status)
 
 	// Test MarkAsSent
 	externalID := "ext-123"
-	n.MarkAsSent(externalID)
+	require.NoError(t, n.MarkAsSent(externalID))
 	assert.Equal(t, StatusSent, n.Status)
 	assert.Equal(t, &externalID, n.ExternalID)
 	assert.NotNil(t, n.SentAt)
+	// A provider that returns no message ID leaves the external ID unset
+	withoutID := NewNotification("+905551234567", ChannelSMS, "Hi")
+	require.NoError(t, withoutID.MarkAsQueued())
+	require.NoError(t, withoutID.MarkAsProcessing())
+	require.NoError(t, withoutID.MarkAsSent(""))
+	assert.Nil(t, withoutID.ExternalID)
+
+	// Test MarkAsDelivered
+	require.NoError(t, n.MarkAsDelivered(time.Now()))
+	assert.Equal(t, StatusDelivered, n.Status)
+	assert.NotNil(t, n.DeliveredAt)
 
 	// Test MarkAsFailed
 	n2 := NewNotification("+905551234567", ChannelSMS, "Test")
+	require.NoError(t, n2.MarkAsQueued())
+	require.NoError(t, n2.MarkAsProcessing())
 	errorMsg := "Provider error"
-	n2.MarkAsFailed(errorMsg)
+	require.NoError(t, n2.MarkAsFailed(errorMsg))
 	assert.Equal(t, StatusFailed, n2.Status)
 	assert.Equal(t, &errorMsg, n2.ErrorMessage)
 
 	// Test MarkAsCancelled
 	n3 := NewNotification("+905551234567", ChannelSMS, "Test")
-	n3.MarkAsCancelled()
+	require.NoError(t, n3.MarkAsCancelled())
 	assert.Equal(t, StatusCancelled, n3.Status)
 }
 
+func TestNotification_ScheduleFor(t *testing.T) {
+	at := time.Now().Add(time.Hour).UTC()
+
+	for _, status := range []Status{StatusPending, StatusScheduled, StatusQueued} {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		n.Status = status
+		require.NoError(t, n.ScheduleFor(at), status)
+		assert.Equal(t, StatusScheduled, n.Status)
+		assert.Equal(t, at, *n.ScheduledAt)
+		assert.True(t, n.CanEdit())
+	}
+
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+	n.Status = StatusProcessing
+	assert.False(t, n.CanEdit())
+	assert.ErrorIs(t, n.ScheduleFor(at), ErrInvalidStatus)
+}
+
+func TestNotification_Retry(t *testing.T) {
+	now := time.Now()
+
+	t.Run("requeues a failed notification", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		n.Status = StatusFailed
+		n.RetryCount = 3
+		errMsg := "provider error (status 503)"
+		n.ErrorMessage = &errMsg
+		n.ClearPendingEvents()
+
+		high := PriorityHigh
+		require.NoError(t, n.Retry(&high, now))
+
+		assert.Equal(t, StatusQueued, n.Status)
+		assert.Equal(t, 0, n.RetryCount)
+		assert.Nil(t, n.ErrorMessage)
+		assert.Equal(t, PriorityHigh, n.Priority)
+		require.Len(t, n.PendingEvents(), 1)
+		assert.Equal(t, StatusFailed, n.PendingEvents()[0].FromStatus)
+		assert.Equal(t, StatusQueued, n.PendingEvents()[0].ToStatus)
+	})
+
+	t.Run("keeps the priority without an override", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		n.Status = StatusFailed
+		n.Priority = PriorityLow
+
+		require.NoError(t, n.Retry(nil, now))
+		assert.Equal(t, PriorityLow, n.Priority)
+	})
+
+	t.Run("only failed notifications", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		n.Status = StatusSent
+		assert.ErrorIs(t, n.Retry(nil, now), ErrInvalidStatus)
+	})
+
+	t.Run("expired notifications are not retried", func(t *testing.T) {
+		n := NewNotification("+905551234567", ChannelSMS, "Test")
+		n.Status = StatusFailed
+		expiresAt := now.Add(-time.Minute)
+		n.ExpiresAt = &expiresAt
+
+		assert.ErrorIs(t, n.Retry(nil, now), ErrInvalidStatus)
+		assert.Equal(t, StatusFailed, n.Status)
+	})
+}
+
+func TestNotification_InvalidStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		from Status
+		mark func(n *Notification) error
+	}{
+		{"cancelled cannot be sent", StatusCancelled, func(n *Notification) error { return n.MarkAsSent("ext-1") }},
+		{"cancelled cannot be processed", StatusCancelled, func(n *Notification) error { return n.MarkAsProcessing() }},
+		{"pending cannot be sent", StatusPending, func(n *Notification) error { return n.MarkAsSent("ext-1") }},
+		{"pending cannot fail", StatusPending, func(n *Notification) error { return n.MarkAsFailed("error") }},
+		{"delivered cannot be undeliverable", StatusDelivered, func(n *Notification) error { return n.MarkAsUndeliverable("error") }},
+		{"failed cannot be requeued", StatusFailed, func(n *Notification) error { return n.MarkAsQueued() }},
+		{"processing cannot be suppressed", StatusProcessing, func(n *Notification) error { return n.MarkAsSuppressed(SuppressionManual) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNotification("+905551234567", ChannelSMS, "Test")
+			n.Status = tt.from
+
+			err := tt.mark(n)
+			assert.ErrorIs(t, err, ErrInvalidStatus)
+			assert.Equal(t, tt.from, n.Status)
+		})
+	}
+
+	n := NewNotification("+905551234567", ChannelSMS, "Test")
+	n.Status = StatusSent
+	assert.ErrorIs(t, n.MarkAsCancelled(), ErrCannotCancel)
+	assert.Equal(t, StatusSent, n.Status)
+}
+
+func TestStatusesBefore(t *testing.T) {
+	assert.Equal(t, []Status{StatusHeld, StatusPending, StatusQueued, StatusScheduled}, StatusesBefore(StatusCancelled))
+}
+
+func TestStatus_IsFinal(t *testing.T) {
+	for _, s := range []Status{StatusDelivered, StatusFailed, StatusCancelled, StatusUndeliverable, StatusSuppressed, StatusDigested} {
+		assert.True(t, s.IsFinal(), s)
+	}
+	for _, s := range []Status{StatusPending, StatusScheduled, StatusQueued, StatusProcessing, StatusSent, StatusHeld} {
+		assert.False(t, s.IsFinal(), s)
+	}
+}
+
 func TestNotification_IncrementRetry(t *testing.T) {
 	n := NewNotification("+905551234567", ChannelSMS, "Test")
 	assert.Equal(t, 0, n.RetryCount)
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlanStatus is the aggregated status of a fallback plan
+type PlanStatus string
+
+const (
+	// PlanActive means a step is in progress
+	PlanActive PlanStatus = "active"
+	// PlanDelivered means one of the steps was delivered
+	PlanDelivered PlanStatus = "delivered"
+	// PlanSent means no step was delivered, but at least one was sent and
+	// its delivery was not confirmed within the step's timeout
+	PlanSent PlanStatus = "sent"
+	// PlanFailed means every step failed or timed out before being sent, or
+	// a step's notification could not be created
+	PlanFailed    PlanStatus = "failed"
+	PlanCancelled PlanStatus = "cancelled"
+)
+
+// IsFinal reports whether the plan has stopped trying steps
+func (s PlanStatus) IsFinal() bool {
+	return s != PlanActive
+}
+
+// Plan metadata keys set on the notifications a plan creates
+const (
+	PlanMetadataKey     = "plan_id"
+	PlanStepMetadataKey = "plan_step"
+)
+
+// PlanStep is one channel and recipient tried by a plan. Content and
+// TemplateName override the plan's content for this step.
+type PlanStep struct {
+	Channel      Channel `json:"channel"`
+	Recipient    string  `json:"recipient"`
+	Content      *string `json:"content,omitempty"`
+	TemplateName *string `json:"template_name,omitempty"`
+	// Timeout is how long, in seconds, the step may take to be delivered
+	// before the next step is tried
+	Timeout int `json:"timeout"`
+	// NotificationID is the notification created for the step once it starts
+	NotificationID *uuid.UUID `json:"notification_id,omitempty"`
+	// Status is the last observed status of the step's notification
+	Status    Status     `json:"status,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// Plan sends a notification through an ordered list of steps, moving to
+// the next step when the current one fails or is not delivered within its
+// timeout, and stopping once a step is delivered
+type Plan struct {
+	ID           uuid.UUID         `json:"id"`
+	Content      string            `json:"content,omitempty"`
+	TemplateName *string           `json:"template_name,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     Priority          `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	Steps        []*PlanStep       `json:"steps"`
+	CurrentStep  int               `json:"current_step"`
+	Status       PlanStatus        `json:"status"`
+	// StepDeadline is when the current step times out, nil until it starts
+	// and once the plan is final
+	StepDeadline *time.Time `json:"step_deadline,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	// Error is why the plan failed when a step could not be started
+	Error *string `json:"error,omitempty"`
+	// Version is incremented on every update and used to detect concurrent writes
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewPlan creates a new active plan
+func NewPlan(steps []*PlanStep) *Plan {
+	now := time.Now().UTC()
+	return &Plan{
+		ID:        uuid.New(),
+		Priority:  PriorityNormal,
+		Steps:     steps,
+		Status:    PlanActive,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Step returns the current step
+func (p *Plan) Step() *PlanStep {
+	return p.Steps[p.CurrentStep]
+}
+
+// StepKey returns the idempotency key of the notification created for the
+// current step
+func (p *Plan) StepKey() string {
+	return fmt.Sprintf("plan:%s:%d", p.ID, p.CurrentStep)
+}
+
+// StartStep records the notification created for the current step and
+// starts its timeout
+func (p *Plan) StartStep(notificationID uuid.UUID, status Status, now time.Time) {
+	step := p.Step()
+	startedAt := now.UTC()
+	step.NotificationID = &notificationID
+	step.Status = status
+	step.StartedAt = &startedAt
+
+	deadline := startedAt.Add(time.Duration(step.Timeout) * time.Second)
+	p.StepDeadline = &deadline
+	p.UpdatedAt = startedAt
+}
+
+// Observe updates the plan with the statuses at now of its started steps'
+// notifications, indexed by step. A step that timed out may still be
+// delivered after a later one started, so any delivered step completes the
+// plan. Otherwise it reports true when the current step failed or timed
+// out and the plan must fall back to the next step, which the caller
+// starts after calling Advance. When no step is left the plan completes
+// instead.
+func (p *Plan) Observe(statuses []Status, now time.Time) bool {
+	if p.Status != PlanActive {
+		return false
+	}
+	for i, status := range statuses {
+		p.Steps[i].Status = status
+	}
+
+	sent := false
+	for _, step := range p.Steps[:p.CurrentStep+1] {
+		switch step.Status {
+		case StatusDelivered:
+			p.complete(PlanDelivered, now)
+			return false
+		case StatusSent:
+			sent = true
+		}
+	}
+
+	timedOut := p.StepDeadline != nil && !now.Before(*p.StepDeadline)
+	if !p.Step().Status.IsFinal() && !timedOut {
+		return false
+	}
+
+	if p.CurrentStep+1 < len(p.Steps) {
+		return true
+	}
+
+	if sent {
+		p.complete(PlanSent, now)
+	} else {
+		p.complete(PlanFailed, now)
+	}
+	return false
+}
+
+// Advance moves the plan to its next step
+func (p *Plan) Advance() error {
+	if p.Status != PlanActive || p.CurrentStep+1 >= len(p.Steps) {
+		return fmt.Errorf("%w: plan has no further step", ErrInvalidStatus)
+	}
+	p.CurrentStep++
+	p.StepDeadline = nil
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Fail stops an active plan whose current step can never be started
+func (p *Plan) Fail(reason string, now time.Time) error {
+	if p.Status != PlanActive {
+		return fmt.Errorf("%w: cannot fail a %s plan", ErrInvalidStatus, p.Status)
+	}
+	p.Error = &reason
+	p.complete(PlanFailed, now)
+	return nil
+}
+
+// Cancel stops an active plan
+func (p *Plan) Cancel() error {
+	if p.Status != PlanActive {
+		return fmt.Errorf("%w: cannot cancel a %s plan", ErrInvalidStatus, p.Status)
+	}
+	p.complete(PlanCancelled, time.Now())
+	return nil
+}
+
+func (p *Plan) complete(status PlanStatus, now time.Time) {
+	completedAt := now.UTC()
+	p.Status = status
+	p.StepDeadline = nil
+	p.CompletedAt = &completedAt
+	p.UpdatedAt = completedAt
+}
+
+// PlanRepository stores fallback plans
+type PlanRepository interface {
+	Create(ctx context.Context, plan *Plan) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Plan, error)
+	// Update stores plan if its Version still matches the stored row,
+	// incrementing Version. It returns ErrVersionConflict otherwise.
+	Update(ctx context.Context, plan *Plan) error
+	// ListDue returns active plans that need evaluating at t: those whose
+	// current step has not started, has timed out or whose notification
+	// reached a final status, and those with any delivered step
+	ListDue(ctx context.Context, t time.Time, limit int) ([]*Plan, error)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPlan(now time.Time) *Plan {
+	p := NewPlan([]*PlanStep{
+		{Channel: ChannelPush, Recipient: "device-token", Timeout: 300},
+		{Channel: ChannelSMS, Recipient: "+905551234567", Timeout: 600},
+	})
+	p.StartStep(uuid.New(), StatusQueued, now)
+	return p
+}
+
+func TestPlan_Observe(t *testing.T) {
+	now := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC)
+
+	t.Run("delivered step completes the plan", func(t *testing.T) {
+		p := newTestPlan(now)
+
+		assert.False(t, p.Observe([]Status{StatusDelivered}, now.Add(time.Minute)))
+		assert.Equal(t, PlanDelivered, p.Status)
+		assert.Nil(t, p.StepDeadline)
+		assert.NotNil(t, p.CompletedAt)
+	})
+
+	t.Run("waits while the step is in progress", func(t *testing.T) {
+		p := newTestPlan(now)
+
+		assert.False(t, p.Observe([]Status{StatusSent}, now.Add(time.Minute)))
+		assert.Equal(t, PlanActive, p.Status)
+		assert.Equal(t, StatusSent, p.Step().Status)
+	})
+
+	t.Run("falls back on failure", func(t *testing.T) {
+		p := newTestPlan(now)
+
+		assert.True(t, p.Observe([]Status{StatusFailed}, now.Add(time.Minute)))
+		require.NoError(t, p.Advance())
+		assert.Equal(t, 1, p.CurrentStep)
+		assert.Equal(t, ChannelSMS, p.Step().Channel)
+		assert.Nil(t, p.StepDeadline)
+	})
+
+	t.Run("falls back on timeout", func(t *testing.T) {
+		p := newTestPlan(now)
+
+		assert.True(t, p.Observe([]Status{StatusSent}, now.Add(5*time.Minute)))
+	})
+
+	t.Run("last step completes the plan", func(t *testing.T) {
+		p := newTestPlan(now)
+		require.NoError(t, p.Advance())
+		p.StartStep(uuid.New(), StatusQueued, now)
+
+		assert.False(t, p.Observe([]Status{StatusFailed, StatusSent}, now.Add(10*time.Minute)))
+		assert.Equal(t, PlanSent, p.Status)
+		assert.ErrorIs(t, p.Advance(), ErrInvalidStatus)
+
+		p = newTestPlan(now)
+		require.NoError(t, p.Advance())
+		p.StartStep(uuid.New(), StatusQueued, now)
+
+		assert.False(t, p.Observe([]Status{StatusFailed, StatusUndeliverable}, now.Add(time.Minute)))
+		assert.Equal(t, PlanFailed, p.Status)
+
+		// An earlier step that was sent makes the plan sent, not failed
+		p = newTestPlan(now)
+		require.NoError(t, p.Advance())
+		p.StartStep(uuid.New(), StatusQueued, now)
+
+		assert.False(t, p.Observe([]Status{StatusSent, StatusUndeliverable}, now.Add(time.Minute)))
+		assert.Equal(t, PlanSent, p.Status)
+	})
+
+	t.Run("an earlier step delivered late completes the plan", func(t *testing.T) {
+		p := newTestPlan(now)
+		require.NoError(t, p.Advance())
+		p.StartStep(uuid.New(), StatusQueued, now.Add(5*time.Minute))
+
+		assert.False(t, p.Observe([]Status{StatusDelivered, StatusQueued}, now.Add(6*time.Minute)))
+		assert.Equal(t, PlanDelivered, p.Status)
+		assert.Equal(t, StatusDelivered, p.Steps[0].Status)
+	})
+}
+
+func TestPlan_Cancel(t *testing.T) {
+	p := newTestPlan(time.Now())
+
+	require.NoError(t, p.Cancel())
+	assert.Equal(t, PlanCancelled, p.Status)
+	assert.ErrorIs(t, p.Cancel(), ErrInvalidStatus)
+	assert.False(t, p.Observe([]Status{StatusFailed}, time.Now()))
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/google/uuid"
+)
+
+const (
+	smsGSMSingleLength     = 160
+	smsGSMMultiLength      = 153
+	smsUnicodeSingleLength = 70
+	smsUnicodeMultiLength  = 67
+)
+
+// gsm7Basic and gsm7Extended are the GSM 03.38 character sets; extended
+// characters take two septets
+const (
+	gsm7Basic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// PriceRule is the unit price for sending through a provider on a channel.
+// Empty Provider or Channel match any value. Country is an E.164 calling
+// code prefix (e.g. "90", "1", "44") matched against phone recipients.
+type PriceRule struct {
+	Provider  string
+	Channel   Channel
+	Country   string
+	UnitPrice float64
+}
+
+// Quote is the computed cost of a single notification
+type Quote struct {
+	UnitPrice float64
+	Units     int
+	Amount    float64
+	Currency  string
+}
+
+// PriceTable computes per-message costs from a set of price rules
+type PriceTable struct {
+	currency string
+	rules    []PriceRule
+}
+
+// NewPriceTable creates a new PriceTable
+func NewPriceTable(currency string, rules []PriceRule) *PriceTable {
+	return &PriceTable{currency: currency, rules: rules}
+}
+
+// Quote returns the cost of sending content to recipient through provider.
+// SMS is charged per segment, other channels per message. The most specific
+// matching rule wins: provider, then channel, then longest country prefix.
+func (t *PriceTable) Quote(provider string, channel Channel, recipient, content string) (Quote, bool) {
+	if t == nil {
+		return Quote{}, false
+	}
+
+	digits := phoneDigits(recipient)
+	best := -1
+	bestScore := -1
+
+	for i, rule := range t.rules {
+		score := 0
+		if rule.Provider != "" {
+			if rule.Provider != provider {
+				continue
+			}
+			score += 1000
+		}
+		if rule.Channel != "" {
+			if rule.Channel != channel {
+				continue
+			}
+			score += 100
+		}
+		if rule.Country != "" {
+			if digits == "" || !strings.HasPrefix(digits, rule.Country) {
+				continue
+			}
+			score += len(rule.Country)
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 {
+		return Quote{}, false
+	}
+
+	units := 1
+	if channel == ChannelSMS {
+		units = SMSSegments(content)
+	}
+
+	unitPrice := t.rules[best].UnitPrice
+	return Quote{
+		UnitPrice: unitPrice,
+		Units:     units,
+		Amount:    unitPrice * float64(units),
+		Currency:  t.currency,
+	}, true
+}
+
+// SMSSegments returns the number of SMS segments needed for content, using
+// GSM-7 encoding when possible and UCS-2 otherwise
+func SMSSegments(content string) int {
+	if content == "" {
+		return 1
+	}
+
+	septets := 0
+	gsm := true
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			gsm = false
+		}
+		if !gsm {
+			break
+		}
+	}
+
+	if gsm {
+		if septets <= smsGSMSingleLength {
+			return 1
+		}
+		return (septets + smsGSMMultiLength - 1) / smsGSMMultiLength
+	}
+
+	units := len(utf16.Encode([]rune(content)))
+	if units <= smsUnicodeSingleLength {
+		return 1
+	}
+	return (units + smsUnicodeMultiLength - 1) / smsUnicodeMultiLength
+}
+
+// phoneDigits returns the digits of an international phone number, or an
+// empty string when recipient does not look like one
+func phoneDigits(recipient string) string {
+	if !strings.HasPrefix(recipient, "+") && !strings.HasPrefix(recipient, "00") {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, r := range recipient {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return ""
+		}
+	}
+
+	return strings.TrimPrefix(b.String(), "00")
+}
+
+// SpendGroupBy selects the extra dimension of a spend report
+type SpendGroupBy string
+
+const (
+	SpendGroupByNone     SpendGroupBy = ""
+	SpendGroupByBatch    SpendGroupBy = "batch"
+	SpendGroupByCampaign SpendGroupBy = "campaign"
+)
+
+// CampaignMetadataKey is the notification metadata key spend reports use
+// to group by campaign
+const CampaignMetadataKey = "campaign"
+
+// SpendReportFilter selects the notifications included in a spend report
+type SpendReportFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Channel   *Channel
+	GroupBy   SpendGroupBy
+}
+
+// SpendReportRow is the spend for one day, channel and optional batch or campaign
+type SpendReportRow struct {
+	Day      time.Time  `json:"day"`
+	Channel  Channel    `json:"channel"`
+	BatchID  *uuid.UUID `json:"batch_id,omitempty"`
+	Campaign *string    `json:"campaign,omitempty"`
+	Currency string     `json:"currency"`
+	Count    int64      `json:"count"`
+	Segments int64      `json:"segments"`
+	Cost     float64    `json:"cost"`
+}
+
+// SpendReporter aggregates recorded notification costs
+type SpendReporter interface {
+	SpendReport(ctx context.Context, filter SpendReportFilter) ([]*SpendReportRow, error)
+}
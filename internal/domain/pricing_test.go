@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMSSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"short gsm", "Hello", 1},
+		{"full gsm segment", strings.Repeat("a", 160), 1},
+		{"two gsm segments", strings.Repeat("a", 161), 2},
+		{"extended chars count double", strings.Repeat("€", 81), 2},
+		{"short unicode", "Merhaba ğüşıöç", 1},
+		{"full unicode segment", strings.Repeat("ş", 70), 1},
+		{"two unicode segments", strings.Repeat("ş", 71), 2},
+		{"three unicode segments", strings.Repeat("ş", 135), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SMSSegments(tt.content))
+		})
+	}
+}
+
+func TestPriceTable_Quote(t *testing.T) {
+	table := NewPriceTable("USD", []PriceRule{
+		{Channel: ChannelEmail, UnitPrice: 0.0001},
+		{Channel: ChannelSMS, UnitPrice: 0.05},
+		{Channel: ChannelSMS, Country: "90", UnitPrice: 0.02},
+		{Provider: "acme-sms", Channel: ChannelSMS, Country: "90", UnitPrice: 0.01},
+		{Provider: "acme-sms", Channel: ChannelSMS, Country: "1", UnitPrice: 0.008},
+	})
+
+	tests := []struct {
+		name      string
+		provider  string
+		channel   Channel
+		recipient string
+		content   string
+		wantOK    bool
+		wantPrice float64
+		wantUnits int
+	}{
+		{"provider and country", "acme-sms", ChannelSMS, "+905551234567", "Hi", true, 0.01, 1},
+		{"country without provider rule", "webhook", ChannelSMS, "+905551234567", "Hi", true, 0.02, 1},
+		{"channel fallback", "webhook", ChannelSMS, "+445551234567", "Hi", true, 0.05, 1},
+		{"provider rule for other country", "acme-sms", ChannelSMS, "+445551234567", "Hi", true, 0.05, 1},
+		{"segments multiply price", "acme-sms", ChannelSMS, "+15551234567", strings.Repeat("a", 200), true, 0.008, 2},
+		{"email per message", "webhook", ChannelEmail, "user@example.com", strings.Repeat("a", 1000), true, 0.0001, 1},
+		{"no matching rule", "webhook", ChannelPush, "device-token", "Hi", false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, ok := table.Quote(tt.provider, tt.channel, tt.recipient, tt.content)
+			assert.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantPrice, quote.UnitPrice)
+			assert.Equal(t, tt.wantUnits, quote.Units)
+			assert.InDelta(t, tt.wantPrice*float64(tt.wantUnits), quote.Amount, 1e-9)
+			assert.Equal(t, "USD", quote.Currency)
+		})
+	}
+
+	var nilTable *PriceTable
+	_, ok := nilTable.Quote("webhook", ChannelSMS, "+905551234567", "Hi")
+	assert.False(t, ok)
+}
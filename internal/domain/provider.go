@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // ProviderRequest represents a request to the external notification provider
@@ -10,6 +12,15 @@ type ProviderRequest struct {
 	To      string `json:"to"`
 	Channel string `json:"channel"`
 	Content string `json:"content"`
+	// TTL is how many seconds a push message may wait for delivery; zero
+	// means the provider default
+	TTL int `json:"ttl,omitempty"`
+
+	// Fields below are not part of the default webhook payload but are
+	// available to configurable providers when building their requests.
+	NotificationID uuid.UUID      `json:"-"`
+	Priority       Priority       `json:"-"`
+	Metadata       map[string]any `json:"-"`
 }
 
 // ProviderResponse represents a response from the external notification provider
@@ -17,6 +28,12 @@ type ProviderResponse struct {
 	MessageID string    `json:"messageId"`
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Provider is the name of the provider that handled the request
+	Provider string `json:"-"`
+	// StatusCode and Body are the raw HTTP response, kept for the delivery log
+	StatusCode int    `json:"-"`
+	Body       string `json:"-"`
 }
 
 // NotificationProvider defines the interface for sending notifications
@@ -24,3 +41,32 @@ type NotificationProvider interface {
 	// Send sends a notification to the external provider
 	Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error)
 }
+
+// RouteWeight is the share of a channel's traffic sent to a provider
+type RouteWeight struct {
+	Provider string `json:"provider"`
+	Weight   int    `json:"weight"`
+}
+
+// ProviderRouter selects providers per channel using adjustable weights
+type ProviderRouter interface {
+	SetRoutes(channel Channel, weights []RouteWeight) error
+	Routes() map[Channel][]RouteWeight
+	Providers() []string
+	StatusCheckers() map[string]StatusChecker
+}
+
+// StatusChecker is optionally implemented by providers that offer a message
+// status API instead of, or in addition to, delivery receipts
+type StatusChecker interface {
+	// StatusBatchSize returns the maximum message IDs per CheckStatus call;
+	// zero means status polling is not configured
+	StatusBatchSize() int
+	CheckStatus(ctx context.Context, externalIDs []string) ([]*DeliveryReceipt, error)
+}
+
+// RoutingStore persists route weights so every instance applies the same split
+type RoutingStore interface {
+	GetRoutes(ctx context.Context) (map[Channel][]RouteWeight, error)
+	SetRoutes(ctx context.Context, channel Channel, weights []RouteWeight) error
+}
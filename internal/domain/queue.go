@@ -25,6 +25,13 @@ type Queue interface {
 	// Dequeue removes and returns the next item from the queue for a channel
 	Dequeue(ctx context.Context, channel Channel) (*QueueItem, error)
 
+	// Remove takes an item out of the queue, reporting false when it was no
+	// longer queued because a worker had already dequeued it
+	Remove(ctx context.Context, item *QueueItem) (bool, error)
+
+	// RemoveBatch takes items out of the queue and returns how many were still queued
+	RemoveBatch(ctx context.Context, items []*QueueItem) (int64, error)
+
 	// GetQueueDepth returns the number of items in the queue for a channel
 	GetQueueDepth(ctx context.Context, channel Channel) (int64, error)
 
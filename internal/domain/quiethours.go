@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHoursRule forbids sending between Start and End, given as minutes
+// after midnight in the recipient's time zone. A window whose End is before
+// Start wraps past midnight; one whose End equals Start is empty and never
+// defers. Empty Channel or Category match any value.
+type QuietHoursRule struct {
+	Channel  Channel
+	Category string
+	Start    int
+	End      int
+}
+
+// minutesPerDay bounds the minutes after midnight of a quiet-hours window
+const minutesPerDay = 24 * 60
+
+// Validate checks that the rule is a non-empty window within one day
+func (r QuietHoursRule) Validate() error {
+	if r.Channel != "" && !r.Channel.IsValid() {
+		return NewValidationError("channel", fmt.Sprintf("invalid channel %q", r.Channel))
+	}
+	if r.Start < 0 || r.Start >= minutesPerDay {
+		return NewValidationError("start", "start must be a time of day")
+	}
+	if r.End < 0 || r.End >= minutesPerDay {
+		return NewValidationError("end", "end must be a time of day")
+	}
+	if r.Start == r.End {
+		return NewValidationError("end", "start and end must differ")
+	}
+	return nil
+}
+
+// QuietHours defers notifications that would reach recipients during their
+// quiet hours
+type QuietHours struct {
+	rules           []QuietHoursRule
+	defaultLocation *time.Location
+	exempt          map[string]bool
+}
+
+// NewQuietHours creates a new QuietHours. Recipients whose time zone is
+// neither set nor inferable from their phone number use defaultLocation.
+// High-priority notifications in exemptCategories are never deferred.
+func NewQuietHours(rules []QuietHoursRule, defaultLocation *time.Location, exemptCategories []string) *QuietHours {
+	if defaultLocation == nil {
+		defaultLocation = time.UTC
+	}
+	exempt := make(map[string]bool, len(exemptCategories))
+	for _, category := range exemptCategories {
+		exempt[category] = true
+	}
+	return &QuietHours{rules: rules, defaultLocation: defaultLocation, exempt: exempt}
+}
+
+// NextAllowed returns the first time at or after t the notification may be
+// sent, and true when that is later than t
+func (q *QuietHours) NextAllowed(n *Notification, t time.Time) (time.Time, bool) {
+	if q == nil || q.isExempt(n) {
+		return t, false
+	}
+
+	rule, ok := q.ruleFor(n.Channel, n.Category)
+	if !ok {
+		return t, false
+	}
+
+	local := t.In(q.Location(n))
+	minute := local.Hour()*60 + local.Minute()
+	year, month, day := local.Date()
+
+	switch {
+	case rule.Start == rule.End:
+		return t, false
+	case rule.Start < rule.End:
+		if minute < rule.Start || minute >= rule.End {
+			return t, false
+		}
+	case minute >= rule.Start:
+		// Inside a window that ends tomorrow
+		day++
+	case minute >= rule.End:
+		return t, false
+	}
+
+	next := time.Date(year, month, day, rule.End/60, rule.End%60, 0, 0, local.Location())
+	return next.UTC(), true
+}
+
+// Location returns the time zone quiet hours are evaluated in for n: its
+// explicit time zone, else one inferred from its phone number, else the default
+func (q *QuietHours) Location(n *Notification) *time.Location {
+	name := n.TimeZone
+	if name == "" {
+		name, _ = TimeZoneForPhone(n.Recipient)
+	}
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return q.defaultLocation
+}
+
+func (q *QuietHours) isExempt(n *Notification) bool {
+	return n.Priority == PriorityHigh && q.exempt[n.Category]
+}
+
+// ruleFor returns the most specific rule for a channel and category:
+// a category match outranks a channel match
+func (q *QuietHours) ruleFor(channel Channel, category string) (QuietHoursRule, bool) {
+	best := -1
+	bestScore := -1
+
+	for i, rule := range q.rules {
+		score := 0
+		if rule.Category != "" {
+			if rule.Category != category {
+				continue
+			}
+			score += 2
+		}
+		if rule.Channel != "" {
+			if rule.Channel != channel {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 {
+		return QuietHoursRule{}, false
+	}
+	return q.rules[best], true
+}
+
+// ParseClock parses a time of day such as "21:30" into minutes after midnight
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, use HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateTimeZone checks that name is a known IANA time zone
+func ValidateTimeZone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil || name == "" || strings.EqualFold(name, "local") {
+		return NewValidationError("timezone", fmt.Sprintf("unknown time zone %q", name))
+	}
+	return nil
+}
+
+// phoneTimeZones maps E.164 calling codes of countries that span a single
+// time zone to that zone. Countries with several zones, such as the US, are
+// left out so their recipients fall back to the default.
+var phoneTimeZones = map[string]string{
+	"30":  "Europe/Athens",
+	"31":  "Europe/Amsterdam",
+	"32":  "Europe/Brussels",
+	"33":  "Europe/Paris",
+	"34":  "Europe/Madrid",
+	"36":  "Europe/Budapest",
+	"39":  "Europe/Rome",
+	"40":  "Europe/Bucharest",
+	"41":  "Europe/Zurich",
+	"43":  "Europe/Vienna",
+	"44":  "Europe/London",
+	"45":  "Europe/Copenhagen",
+	"46":  "Europe/Stockholm",
+	"47":  "Europe/Oslo",
+	"48":  "Europe/Warsaw",
+	"49":  "Europe/Berlin",
+	"351": "Europe/Lisbon",
+	"353": "Europe/Dublin",
+	"358": "Europe/Helsinki",
+	"359": "Europe/Sofia",
+	"380": "Europe/Kyiv",
+	"420": "Europe/Prague",
+	"90":  "Europe/Istanbul",
+	"20":  "Africa/Cairo",
+	"27":  "Africa/Johannesburg",
+	"234": "Africa/Lagos",
+	"254": "Africa/Nairobi",
+	"966": "Asia/Riyadh",
+	"971": "Asia/Dubai",
+	"972": "Asia/Jerusalem",
+	"974": "Asia/Qatar",
+	"91":  "Asia/Kolkata",
+	"92":  "Asia/Karachi",
+	"880": "Asia/Dhaka",
+	"65":  "Asia/Singapore",
+	"60":  "Asia/Kuala_Lumpur",
+	"63":  "Asia/Manila",
+	"66":  "Asia/Bangkok",
+	"84":  "Asia/Ho_Chi_Minh",
+	"81":  "Asia/Tokyo",
+	"82":  "Asia/Seoul",
+	"86":  "Asia/Shanghai",
+	"852": "Asia/Hong_Kong",
+	"886": "Asia/Taipei",
+	"64":  "Pacific/Auckland",
+	"54":  "America/Argentina/Buenos_Aires",
+	"56":  "America/Santiago",
+	"57":  "America/Bogota",
+	"51":  "America/Lima",
+}
+
+// TimeZoneForPhone infers the IANA time zone of a phone recipient from its
+// calling code, using the longest matching prefix
+func TimeZoneForPhone(recipient string) (string, bool) {
+	digits := phoneDigits(recipient)
+	if digits == "" {
+		return "", false
+	}
+	for length := 3; length >= 1; length-- {
+		if len(digits) < length {
+			continue
+		}
+		if zone, ok := phoneTimeZones[digits[:length]]; ok {
+			return zone, true
+		}
+	}
+	return "", false
+}
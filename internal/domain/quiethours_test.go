@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHours_NextAllowed(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	require.NoError(t, err)
+
+	quiet := NewQuietHours([]QuietHoursRule{
+		{Start: 22 * 60, End: 8 * 60},
+		{Channel: ChannelPush, Category: "marketing", Start: 21 * 60, End: 9 * 60},
+		{Channel: ChannelEmail, Start: 13 * 60, End: 14 * 60},
+	}, time.UTC, []string{"transactional"})
+
+	// Turkish number, so quiet hours are evaluated in Europe/Istanbul
+	n := NewNotification("+905551234567", ChannelSMS, "Sale!")
+
+	t.Run("before midnight defers to the next morning", func(t *testing.T) {
+		at := time.Date(2024, 3, 1, 23, 30, 0, 0, istanbul)
+		next, deferred := quiet.NextAllowed(n, at)
+		assert.True(t, deferred)
+		assert.True(t, next.Equal(time.Date(2024, 3, 2, 8, 0, 0, 0, istanbul)))
+	})
+
+	t.Run("after midnight defers to the same morning", func(t *testing.T) {
+		at := time.Date(2024, 3, 2, 3, 0, 0, 0, istanbul)
+		next, deferred := quiet.NextAllowed(n, at)
+		assert.True(t, deferred)
+		assert.True(t, next.Equal(time.Date(2024, 3, 2, 8, 0, 0, 0, istanbul)))
+	})
+
+	t.Run("outside quiet hours", func(t *testing.T) {
+		at := time.Date(2024, 3, 2, 8, 0, 0, 0, istanbul)
+		next, deferred := quiet.NextAllowed(n, at)
+		assert.False(t, deferred)
+		assert.Equal(t, at, next)
+	})
+
+	t.Run("category and channel rule wins", func(t *testing.T) {
+		push := NewNotification("device-token", ChannelPush, "Sale!")
+		push.Category = "marketing"
+		push.TimeZone = "Europe/Istanbul"
+
+		next, deferred := quiet.NextAllowed(push, time.Date(2024, 3, 2, 8, 30, 0, 0, istanbul))
+		assert.True(t, deferred)
+		assert.True(t, next.Equal(time.Date(2024, 3, 2, 9, 0, 0, 0, istanbul)))
+	})
+
+	t.Run("same-day window", func(t *testing.T) {
+		email := NewNotification("user@example.com", ChannelEmail, "Hi")
+		next, deferred := quiet.NextAllowed(email, time.Date(2024, 3, 2, 13, 15, 0, 0, time.UTC))
+		assert.True(t, deferred)
+		assert.True(t, next.Equal(time.Date(2024, 3, 2, 14, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("high-priority transactional is exempt", func(t *testing.T) {
+		otp := NewNotification("+905551234567", ChannelSMS, "Your code is 4821")
+		otp.Category = "transactional"
+		otp.Priority = PriorityHigh
+		_, deferred := quiet.NextAllowed(otp, time.Date(2024, 3, 2, 3, 0, 0, 0, istanbul))
+		assert.False(t, deferred)
+
+		otp.Priority = PriorityNormal
+		_, deferred = quiet.NextAllowed(otp, time.Date(2024, 3, 2, 3, 0, 0, 0, istanbul))
+		assert.True(t, deferred)
+	})
+
+	t.Run("nil quiet hours never defer", func(t *testing.T) {
+		var none *QuietHours
+		_, deferred := none.NextAllowed(n, time.Date(2024, 3, 2, 3, 0, 0, 0, istanbul))
+		assert.False(t, deferred)
+	})
+
+	t.Run("empty window never defers", func(t *testing.T) {
+		empty := NewQuietHours([]QuietHoursRule{{Start: 22 * 60, End: 22 * 60}}, time.UTC, nil)
+		for _, at := range []time.Time{
+			time.Date(2024, 3, 2, 21, 59, 0, 0, istanbul),
+			time.Date(2024, 3, 2, 22, 0, 0, 0, istanbul),
+			time.Date(2024, 3, 2, 23, 0, 0, 0, istanbul),
+		} {
+			next, deferred := empty.NextAllowed(n, at)
+			assert.False(t, deferred)
+			assert.Equal(t, at, next)
+		}
+	})
+}
+
+func TestQuietHoursRule_Validate(t *testing.T) {
+	assert.NoError(t, QuietHoursRule{Start: 22 * 60, End: 8 * 60}.Validate())
+	assert.NoError(t, QuietHoursRule{Channel: ChannelEmail, Start: 13 * 60, End: 14 * 60}.Validate())
+	assert.Error(t, QuietHoursRule{Start: 22 * 60, End: 22 * 60}.Validate())
+	assert.Error(t, QuietHoursRule{Channel: Channel("fax"), Start: 22 * 60, End: 8 * 60}.Validate())
+	assert.Error(t, QuietHoursRule{Start: -1, End: 8 * 60}.Validate())
+	assert.Error(t, QuietHoursRule{Start: 22 * 60, End: 24 * 60}.Validate())
+}
+
+func TestTimeZoneForPhone(t *testing.T) {
+	tests := []struct {
+		recipient string
+		zone      string
+		ok        bool
+	}{
+		{"+905551234567", "Europe/Istanbul", true},
+		{"00447911123456", "Europe/London", true},
+		{"+351912345678", "Europe/Lisbon", true},
+		{"+12025550123", "", false},
+		{"user@example.com", "", false},
+	}
+
+	for _, tt := range tests {
+		zone, ok := TimeZoneForPhone(tt.recipient)
+		assert.Equal(t, tt.ok, ok, tt.recipient)
+		assert.Equal(t, tt.zone, zone, tt.recipient)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	minutes, err := ParseClock("21:30")
+	require.NoError(t, err)
+	assert.Equal(t, 21*60+30, minutes)
+
+	_, err = ParseClock("25:00")
+	assert.Error(t, err)
+}
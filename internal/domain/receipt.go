@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+// ReceiptStatus is the delivery outcome reported by a provider
+type ReceiptStatus string
+
+const (
+	ReceiptDelivered     ReceiptStatus = "delivered"
+	ReceiptUndeliverable ReceiptStatus = "undeliverable"
+	// ReceiptPending covers intermediate provider states that do not change the notification
+	ReceiptPending ReceiptStatus = "pending"
+)
+
+// DeliveryReceipt is a delivery report for a message previously accepted by a provider
+type DeliveryReceipt struct {
+	ExternalID string        `json:"external_id"`
+	Status     ReceiptStatus `json:"status"`
+	Reason     string        `json:"reason,omitempty"`
+	OccurredAt *time.Time    `json:"occurred_at,omitempty"`
+}
+
+// ReceiptParser authenticates and decodes the delivery receipts a provider posts back
+type ReceiptParser interface {
+	Verify(header http.Header, body []byte) error
+	Parse(body []byte) ([]*DeliveryReceipt, error)
+}
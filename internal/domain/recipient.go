@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Recipient is the contact profile of a user of the calling system, holding
+// the user's address on each channel
+type Recipient struct {
+	// UserID is the caller's identifier for the user
+	UserID string  `json:"user_id"`
+	Phone  *string `json:"phone,omitempty"`
+	Email  *string `json:"email,omitempty"`
+	// DeviceTokens are the user's push tokens, most recent first; push
+	// notifications are sent to the first one
+	DeviceTokens []string `json:"device_tokens"`
+	Locale       string   `json:"locale,omitempty"`
+	// TimeZone is the user's IANA time zone used for quiet hours
+	TimeZone  string    `json:"timezone,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewRecipient creates a new recipient profile without addresses
+func NewRecipient(userID string) *Recipient {
+	now := time.Now().UTC()
+	return &Recipient{
+		UserID:       userID,
+		DeviceTokens: []string{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Address returns the recipient's address on channel, or ErrNoAddress when
+// the profile has none
+func (r *Recipient) Address(channel Channel) (string, error) {
+	var address string
+	switch channel {
+	case ChannelSMS:
+		if r.Phone != nil {
+			address = *r.Phone
+		}
+	case ChannelEmail:
+		if r.Email != nil {
+			address = *r.Email
+		}
+	case ChannelPush:
+		if len(r.DeviceTokens) > 0 {
+			address = r.DeviceTokens[0]
+		}
+	}
+
+	if address == "" {
+		return "", fmt.Errorf("%w: user %s has no %s address", ErrNoAddress, r.UserID, channel)
+	}
+	return address, nil
+}
+
+type RecipientFilter struct {
+	Page     int
+	PageSize int
+}
+
+type RecipientListResult struct {
+	Recipients []*Recipient `json:"recipients"`
+	Total      int64        `json:"total"`
+	Page       int          `json:"page"`
+	PageSize   int          `json:"page_size"`
+	TotalPages int          `json:"total_pages"`
+}
+
+type RecipientRepository interface {
+	// Create stores a new recipient, returning ErrAlreadyExists when the
+	// user ID is taken
+	Create(ctx context.Context, recipient *Recipient) error
+	// Upsert creates a recipient or replaces the profile of the same user
+	Upsert(ctx context.Context, recipient *Recipient) error
+	UpsertBatch(ctx context.Context, recipients []*Recipient) error
+	GetByUserID(ctx context.Context, userID string) (*Recipient, error)
+	List(ctx context.Context, filter RecipientFilter) (*RecipientListResult, error)
+	Delete(ctx context.Context, userID string) error
+	// FindByUserIDs returns the recipients among userIDs, keyed by user ID
+	FindByUserIDs(ctx context.Context, userIDs []string) (map[string]*Recipient, error)
+}
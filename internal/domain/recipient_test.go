@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipient_Address(t *testing.T) {
+	phone := "+905551234567"
+	r := NewRecipient("user-42")
+	r.Phone = &phone
+	r.DeviceTokens = []string{"token-new", "token-old"}
+
+	address, err := r.Address(ChannelSMS)
+	require.NoError(t, err)
+	assert.Equal(t, phone, address)
+
+	address, err = r.Address(ChannelPush)
+	require.NoError(t, err)
+	assert.Equal(t, "token-new", address)
+
+	_, err = r.Address(ChannelEmail)
+	assert.ErrorIs(t, err, ErrNoAddress)
+	assert.Contains(t, err.Error(), "user user-42 has no email address")
+}
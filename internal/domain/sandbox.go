@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SandboxMessage is a message captured by the sandbox provider instead of
+// being delivered
+type SandboxMessage struct {
+	ID             string         `json:"id"`
+	NotificationID uuid.UUID      `json:"notification_id"`
+	To             string         `json:"to"`
+	Channel        Channel        `json:"channel"`
+	Content        string         `json:"content"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	// Status is "accepted", or "failed" when failure injection rejected it
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code"`
+	LatencyMs  int64     `json:"latency_ms"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// SandboxStore is a bounded store of captured sandbox messages; once full,
+// the oldest messages are discarded
+type SandboxStore interface {
+	Add(ctx context.Context, msg *SandboxMessage) error
+	// List returns the newest messages first, optionally filtered by recipient
+	List(ctx context.Context, recipient string, limit int) ([]*SandboxMessage, error)
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+)
+
+// ScheduleStatus is the state of a recurring schedule
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"
+	SchedulePaused    ScheduleStatus = "paused"
+	ScheduleCompleted ScheduleStatus = "completed"
+)
+
+func (s ScheduleStatus) IsValid() bool {
+	switch s {
+	case ScheduleActive, SchedulePaused, ScheduleCompleted:
+		return true
+	}
+	return false
+}
+
+// ScheduleMetadataKey is the notification metadata key holding the ID of
+// the schedule a notification was created by
+const ScheduleMetadataKey = "schedule_id"
+
+// Schedule sends a template to a recipient on a recurring basis, described
+// by either a cron expression or an RRULE evaluated in TimeZone
+type Schedule struct {
+	ID           uuid.UUID         `json:"id"`
+	Name         string            `json:"name"`
+	Recipient    string            `json:"recipient"`
+	Channel      Channel           `json:"channel"`
+	TemplateName string            `json:"template_name"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     Priority          `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	Cron         *string           `json:"cron,omitempty"`
+	RRule        *string           `json:"rrule,omitempty"`
+	TimeZone     string            `json:"timezone"`
+	// StartsAt is when the recurrence begins; it defaults to creation
+	StartsAt       time.Time      `json:"starts_at"`
+	EndsAt         *time.Time     `json:"ends_at,omitempty"`
+	MaxOccurrences *int           `json:"max_occurrences,omitempty"`
+	Occurrences    int            `json:"occurrences"`
+	Status         ScheduleStatus `json:"status"`
+	// NextRunAt is the next occurrence, nil once the schedule has completed
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// LastError is why the schedule was paused after an occurrence could not
+	// be created; it is cleared when the schedule is resumed
+	LastError *string `json:"last_error,omitempty"`
+	// Version is incremented on every update and used to detect concurrent writes
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewSchedule creates a new active schedule
+func NewSchedule(name, recipient string, channel Channel, templateName string) *Schedule {
+	now := time.Now().UTC()
+	return &Schedule{
+		ID:           uuid.New(),
+		Name:         name,
+		Recipient:    recipient,
+		Channel:      channel,
+		TemplateName: templateName,
+		Priority:     PriorityNormal,
+		TimeZone:     "UTC",
+		StartsAt:     now,
+		Status:       ScheduleActive,
+		Version:      1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Recurrence yields the occurrences of a schedule
+type Recurrence interface {
+	// Next returns the first occurrence strictly after t, or the zero time
+	// when there is none
+	Next(t time.Time) time.Time
+}
+
+type cronRecurrence struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+func (r cronRecurrence) Next(t time.Time) time.Time {
+	return r.schedule.Next(t.In(r.location))
+}
+
+type rruleRecurrence struct {
+	rule *rrule.RRule
+}
+
+func (r rruleRecurrence) Next(t time.Time) time.Time {
+	return r.rule.After(t, false)
+}
+
+// Recurrence parses the schedule's cron expression or RRULE
+func (s *Schedule) Recurrence() (Recurrence, error) {
+	if err := ValidateTimeZone(s.TimeZone); err != nil {
+		return nil, err
+	}
+	location, _ := time.LoadLocation(s.TimeZone)
+
+	switch {
+	case s.Cron != nil && s.RRule != nil:
+		return nil, NewValidationError("cron", "set either cron or rrule, not both")
+	case s.Cron != nil:
+		parsed, err := cron.ParseStandard(*s.Cron)
+		if err != nil || strings.Contains(*s.Cron, "TZ=") {
+			return nil, NewValidationError("cron", "invalid cron expression, use five fields or a descriptor such as @daily")
+		}
+		return cronRecurrence{schedule: parsed, location: location}, nil
+	case s.RRule != nil:
+		option, err := rrule.StrToROptionInLocation(strings.TrimPrefix(*s.RRule, "RRULE:"), location)
+		if err != nil {
+			return nil, NewValidationError("rrule", fmt.Sprintf("invalid rrule: %v", err))
+		}
+		if option.Dtstart.IsZero() {
+			option.Dtstart = s.StartsAt.In(location)
+		}
+		rule, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, NewValidationError("rrule", fmt.Sprintf("invalid rrule: %v", err))
+		}
+		return rruleRecurrence{rule: rule}, nil
+	}
+
+	return nil, NewValidationError("cron", "cron or rrule is required")
+}
+
+// Start computes the first run of the schedule at or after t and activates it
+func (s *Schedule) Start(t time.Time) error {
+	if s.StartsAt.After(t) {
+		t = s.StartsAt
+	}
+	s.Status = ScheduleActive
+	return s.planNext(t.Add(-time.Nanosecond))
+}
+
+// Advance records that the occurrence at NextRunAt was materialised at now
+// and moves to the next occurrence. Occurrences missed while no scheduler
+// was running are skipped rather than sent in a burst.
+func (s *Schedule) Advance(now time.Time) error {
+	if s.NextRunAt == nil {
+		return fmt.Errorf("%w: schedule has no pending occurrence", ErrInvalidStatus)
+	}
+	occurrence := *s.NextRunAt
+	s.Occurrences++
+	s.LastRunAt = &occurrence
+
+	after := occurrence
+	if now.After(after) {
+		after = now
+	}
+	return s.planNext(after)
+}
+
+// Pause stops the schedule from materialising occurrences
+func (s *Schedule) Pause() error {
+	if s.Status != ScheduleActive {
+		return fmt.Errorf("%w: cannot pause a %s schedule", ErrInvalidStatus, s.Status)
+	}
+	s.Status = SchedulePaused
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// PauseWithError pauses a schedule whose next occurrence cannot be created,
+// recording why so the schedule can be fixed and resumed
+func (s *Schedule) PauseWithError(msg string) error {
+	if err := s.Pause(); err != nil {
+		return err
+	}
+	s.LastError = &msg
+	return nil
+}
+
+// Resume reactivates a paused schedule from its next occurrence after now;
+// occurrences that fell in the pause are not sent
+func (s *Schedule) Resume(now time.Time) error {
+	if s.Status != SchedulePaused {
+		return fmt.Errorf("%w: cannot resume a %s schedule", ErrInvalidStatus, s.Status)
+	}
+	s.LastError = nil
+	return s.Start(now)
+}
+
+// OccurrenceKey returns the idempotency key of the notification for the
+// occurrence at t, so each occurrence is materialised at most once
+func (s *Schedule) OccurrenceKey(t time.Time) string {
+	return fmt.Sprintf("schedule:%s:%d", s.ID, t.Unix())
+}
+
+// planNext sets NextRunAt to the first occurrence after t, completing the
+// schedule when it has run out of occurrences
+func (s *Schedule) planNext(t time.Time) error {
+	recurrence, err := s.Recurrence()
+	if err != nil {
+		return err
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+
+	next := recurrence.Next(t)
+	if next.IsZero() ||
+		(s.EndsAt != nil && next.After(*s.EndsAt)) ||
+		(s.MaxOccurrences != nil && s.Occurrences >= *s.MaxOccurrences) {
+		s.NextRunAt = nil
+		s.Status = ScheduleCompleted
+		return nil
+	}
+
+	next = next.UTC()
+	s.NextRunAt = &next
+	return nil
+}
+
+type ScheduleFilter struct {
+	Status    *ScheduleStatus
+	Channel   *Channel
+	Recipient *string
+	Page      int
+	PageSize  int
+}
+
+type ScheduleListResult struct {
+	Schedules  []*Schedule `json:"schedules"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	TotalPages int         `json:"total_pages"`
+}
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *Schedule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Schedule, error)
+	List(ctx context.Context, filter ScheduleFilter) (*ScheduleListResult, error)
+	// Update stores schedule if its Version still matches the stored row,
+	// incrementing Version. It returns ErrVersionConflict otherwise.
+	Update(ctx context.Context, schedule *Schedule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDue returns active schedules whose next occurrence is at or before t
+	ListDue(ctx context.Context, t time.Time, limit int) ([]*Schedule, error)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Start(t *testing.T) {
+	t.Run("cron is evaluated in the schedule time zone", func(t *testing.T) {
+		s := NewSchedule("daily", "+905551234567", ChannelSMS, "reminder")
+		cron := "0 9 * * *"
+		s.Cron = &cron
+		s.TimeZone = "Europe/Istanbul"
+
+		now := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC) // 10:00 in Istanbul
+		s.StartsAt = now
+		require.NoError(t, s.Start(now))
+
+		require.NotNil(t, s.NextRunAt)
+		assert.Equal(t, time.Date(2026, 3, 11, 6, 0, 0, 0, time.UTC), *s.NextRunAt)
+		assert.Equal(t, ScheduleActive, s.Status)
+	})
+
+	t.Run("rrule starts at starts_at", func(t *testing.T) {
+		s := NewSchedule("weekly", "user@example.com", ChannelEmail, "digest")
+		rule := "FREQ=WEEKLY;BYDAY=MO;BYHOUR=9;BYMINUTE=0;BYSECOND=0"
+		s.RRule = &rule
+		s.StartsAt = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		require.NoError(t, s.Start(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+		require.NotNil(t, s.NextRunAt)
+		assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), *s.NextRunAt)
+	})
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		s := NewSchedule("bad", "+905551234567", ChannelSMS, "reminder")
+		cron := "every day"
+		s.Cron = &cron
+		assert.Error(t, s.Start(time.Now()))
+
+		s.Cron = nil
+		assert.Error(t, s.Start(time.Now()))
+
+		rule := "FREQ=DAILY"
+		s.Cron, s.RRule = &cron, &rule
+		assert.Error(t, s.Start(time.Now()))
+	})
+}
+
+func TestSchedule_Advance(t *testing.T) {
+	newHourly := func(start time.Time) *Schedule {
+		s := NewSchedule("hourly", "+905551234567", ChannelSMS, "reminder")
+		cron := "@hourly"
+		s.Cron = &cron
+		s.StartsAt = start
+		require.NoError(t, s.Start(start))
+		return s
+	}
+	start := time.Date(2026, 3, 10, 7, 30, 0, 0, time.UTC)
+
+	t.Run("moves to the next occurrence", func(t *testing.T) {
+		s := newHourly(start)
+		occurrence := *s.NextRunAt
+
+		require.NoError(t, s.Advance(occurrence))
+
+		assert.Equal(t, 1, s.Occurrences)
+		assert.Equal(t, occurrence, *s.LastRunAt)
+		assert.Equal(t, occurrence.Add(time.Hour), *s.NextRunAt)
+	})
+
+	t.Run("skips occurrences missed while not running", func(t *testing.T) {
+		s := newHourly(start)
+		occurrence := *s.NextRunAt
+
+		require.NoError(t, s.Advance(occurrence.Add(5*time.Hour+time.Minute)))
+
+		assert.Equal(t, 1, s.Occurrences)
+		assert.Equal(t, occurrence.Add(6*time.Hour), *s.NextRunAt)
+	})
+
+	t.Run("completes after max occurrences", func(t *testing.T) {
+		s := newHourly(start)
+		limit := 2
+		s.MaxOccurrences = &limit
+
+		require.NoError(t, s.Advance(*s.NextRunAt))
+		assert.Equal(t, ScheduleActive, s.Status)
+		require.NoError(t, s.Advance(*s.NextRunAt))
+
+		assert.Equal(t, ScheduleCompleted, s.Status)
+		assert.Nil(t, s.NextRunAt)
+	})
+
+	t.Run("completes at the end date", func(t *testing.T) {
+		s := newHourly(start)
+		endsAt := s.NextRunAt.Add(30 * time.Minute)
+		s.EndsAt = &endsAt
+
+		require.NoError(t, s.Advance(*s.NextRunAt))
+
+		assert.Equal(t, ScheduleCompleted, s.Status)
+		assert.Nil(t, s.NextRunAt)
+	})
+}
+
+func TestSchedule_PauseResume(t *testing.T) {
+	s := NewSchedule("hourly", "+905551234567", ChannelSMS, "reminder")
+	cron := "@hourly"
+	s.Cron = &cron
+	require.NoError(t, s.Start(time.Now()))
+
+	require.NoError(t, s.Pause())
+	assert.Equal(t, SchedulePaused, s.Status)
+	assert.ErrorIs(t, s.Pause(), ErrInvalidStatus)
+
+	later := time.Now().Add(3 * time.Hour)
+	require.NoError(t, s.Resume(later))
+	assert.Equal(t, ScheduleActive, s.Status)
+	assert.True(t, s.NextRunAt.After(later))
+	assert.ErrorIs(t, s.Resume(later), ErrInvalidStatus)
+}
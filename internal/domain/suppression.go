@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// SuppressionReason is why a recipient must not be contacted on a channel
+type SuppressionReason string
+
+const (
+	SuppressionBounce      SuppressionReason = "bounce"
+	SuppressionComplaint   SuppressionReason = "complaint"
+	SuppressionUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionManual      SuppressionReason = "manual"
+)
+
+func (r SuppressionReason) IsValid() bool {
+	switch r {
+	case SuppressionBounce, SuppressionComplaint, SuppressionUnsubscribe, SuppressionManual:
+		return true
+	}
+	return false
+}
+
+// Suppression blocks notifications to a recipient on a channel until ExpiresAt,
+// or indefinitely when ExpiresAt is nil
+type Suppression struct {
+	Recipient string            `json:"recipient"`
+	Channel   Channel           `json:"channel"`
+	Reason    SuppressionReason `json:"reason"`
+	Note      *string           `json:"note,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// NewSuppression creates a new suppression. recipient must already be in
+// the form returned by SuppressionRecipient.
+func NewSuppression(recipient string, channel Channel, reason SuppressionReason) *Suppression {
+	now := time.Now().UTC()
+	return &Suppression{
+		Recipient: recipient,
+		Channel:   channel,
+		Reason:    reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsActive reports whether the suppression applies at t
+func (s *Suppression) IsActive(t time.Time) bool {
+	return s.ExpiresAt == nil || s.ExpiresAt.After(t)
+}
+
+// SuppressionRecipient returns the form of recipient suppressions are keyed
+// by; email addresses are matched case-insensitively. Valid recipients are
+// normalized first, reading national phone numbers in region, so a
+// suppression matches however the address was formatted.
+func SuppressionRecipient(channel Channel, recipient, region string) string {
+	recipient = strings.TrimSpace(recipient)
+	if normalized, err := NormalizeRecipient(channel, recipient, region); err == nil {
+		recipient = normalized
+	}
+	if channel == ChannelEmail {
+		return strings.ToLower(recipient)
+	}
+	return recipient
+}
+
+// MarkAsSuppressed records that the notification was not sent because its
+// recipient is on the suppression list
+func (n *Notification) MarkAsSuppressed(reason SuppressionReason) error {
+	if err := n.transition(StatusSuppressed); err != nil {
+		return err
+	}
+	msg := "recipient suppressed: " + string(reason)
+	n.recordError(msg)
+	n.ErrorMessage = &msg
+	return nil
+}
+
+type SuppressionFilter struct {
+	Channel  *Channel
+	Reason   *SuppressionReason
+	Page     int
+	PageSize int
+}
+
+type SuppressionListResult struct {
+	Suppressions []*Suppression `json:"suppressions"`
+	Total        int64          `json:"total"`
+	Page         int            `json:"page"`
+	PageSize     int            `json:"page_size"`
+	TotalPages   int            `json:"total_pages"`
+}
+
+type SuppressionRepository interface {
+	// Upsert creates a suppression or replaces the one for the same recipient and channel
+	Upsert(ctx context.Context, suppression *Suppression) error
+	UpsertBatch(ctx context.Context, suppressions []*Suppression) error
+	Get(ctx context.Context, channel Channel, recipient string) (*Suppression, error)
+	List(ctx context.Context, filter SuppressionFilter) (*SuppressionListResult, error)
+	Delete(ctx context.Context, channel Channel, recipient string) error
+	// FindActive returns the unexpired suppressions among recipients, keyed by recipient
+	FindActive(ctx context.Context, channel Channel, recipients []string) (map[string]*Suppression, error)
+	// ListUnnormalizedPhones returns SMS suppressions not keyed in E.164
+	// format whose recipient sorts after after, in recipient order
+	ListUnnormalizedPhones(ctx context.Context, after string, limit int) ([]*Suppression, error)
+	// Rekey moves a suppression to a new recipient. If one already exists
+	// under the new recipient the two are merged into the stricter one.
+	Rekey(ctx context.Context, channel Channel, from, to string) error
+}
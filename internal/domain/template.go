@@ -16,8 +16,11 @@ type Template struct {
 	Channel   Channel   `json:"channel"`
 	Content   string    `json:"content"`
 	Variables []string  `json:"variables"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// TrackingDisabled opts notifications rendered from this template out of
+	// email open and click tracking
+	TrackingDisabled bool      `json:"tracking_disabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // variablePattern matches template variables like {{variable_name}}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TopicMetadataKey is the metadata key set to the topic on notifications
+// created by a publication
+const TopicMetadataKey = "topic"
+
+var topicNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,99}$`)
+
+// Topic is a named audience recipients subscribe to, such as
+// "price-drop.product-123"
+type Topic struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewTopic creates a new topic
+func NewTopic(name string) *Topic {
+	now := time.Now().UTC()
+	return &Topic{
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ValidateTopicName checks that name is 1 to 100 letters, digits, dots,
+// colons, dashes and underscores, starting with a letter or digit
+func ValidateTopicName(name string) error {
+	if !topicNamePattern.MatchString(name) {
+		return NewValidationError("name", "topic name must be 1-100 letters, digits, '.', ':', '-' or '_'")
+	}
+	return nil
+}
+
+// Subscription subscribes the recipient with UserID to a topic. Every
+// publication to the topic is sent to the recipient on each of Channels.
+type Subscription struct {
+	// ID orders subscriptions for publishing
+	ID        int64     `json:"-"`
+	Topic     string    `json:"topic"`
+	UserID    string    `json:"user_id"`
+	Channels  []Channel `json:"channels"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewSubscription creates a new subscription
+func NewSubscription(topic, userID string, channels []Channel) *Subscription {
+	now := time.Now().UTC()
+	return &Subscription{
+		Topic:     topic,
+		UserID:    userID,
+		Channels:  channels,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// PublicationStatus is the progress of a publication's fan-out
+type PublicationStatus string
+
+const (
+	PublicationPending   PublicationStatus = "pending"
+	PublicationRunning   PublicationStatus = "running"
+	PublicationCompleted PublicationStatus = "completed"
+	PublicationFailed    PublicationStatus = "failed"
+)
+
+// IsFinal reports whether the publication's fan-out has stopped
+func (s PublicationStatus) IsFinal() bool {
+	return s == PublicationCompleted || s == PublicationFailed
+}
+
+// Publication is a message published to a topic. It is fanned out to the
+// topic's subscribers in chunks, and its notifications share the
+// publication's ID as their batch ID.
+type Publication struct {
+	ID           uuid.UUID         `json:"id"`
+	Topic        string            `json:"topic"`
+	Content      string            `json:"content,omitempty"`
+	TemplateName *string           `json:"template_name,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     Priority          `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	ExpiresAt    *time.Time        `json:"expires_at,omitempty"`
+	Status       PublicationStatus `json:"status"`
+	// LastSubscriptionID is the ID of the last subscription fanned out to
+	LastSubscriptionID int64 `json:"-"`
+	// Subscribers is the number of subscriptions fanned out to so far
+	Subscribers int64 `json:"subscribers"`
+	// Notifications is the number of notifications created so far
+	Notifications int64 `json:"notifications"`
+	// Skipped is the number of subscribed channels on which the recipient
+	// had no address or no profile
+	Skipped     int64      `json:"skipped"`
+	Error       *string    `json:"error,omitempty"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// NewPublication creates a new pending publication to topic
+func NewPublication(topic string) *Publication {
+	now := time.Now().UTC()
+	return &Publication{
+		ID:        uuid.New(),
+		Topic:     topic,
+		Priority:  PriorityNormal,
+		Status:    PublicationPending,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Advance records a fanned-out chunk of subscriptions ending at
+// lastSubscriptionID
+func (p *Publication) Advance(lastSubscriptionID, subscribers, notifications, skipped int64) {
+	p.Status = PublicationRunning
+	p.LastSubscriptionID = lastSubscriptionID
+	p.Subscribers += subscribers
+	p.Notifications += notifications
+	p.Skipped += skipped
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// Complete marks the publication as fanned out to every subscriber
+func (p *Publication) Complete(now time.Time) {
+	p.finish(PublicationCompleted, now)
+}
+
+// Fail stops the publication's fan-out
+func (p *Publication) Fail(reason string, now time.Time) {
+	p.Error = &reason
+	p.finish(PublicationFailed, now)
+}
+
+func (p *Publication) finish(status PublicationStatus, now time.Time) {
+	completedAt := now.UTC()
+	p.Status = status
+	p.CompletedAt = &completedAt
+	p.UpdatedAt = completedAt
+}
+
+// SubscriptionKey returns the idempotency key of the notification created
+// for a subscription on channel
+func (p *Publication) SubscriptionKey(subscriptionID int64, channel Channel) string {
+	return fmt.Sprintf("topic:%s:%d:%s", p.ID, subscriptionID, channel)
+}
+
+type TopicFilter struct {
+	Page     int
+	PageSize int
+}
+
+type TopicListResult struct {
+	Topics     []*Topic `json:"topics"`
+	Total      int64    `json:"total"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	TotalPages int      `json:"total_pages"`
+}
+
+type SubscriptionFilter struct {
+	Page     int
+	PageSize int
+}
+
+type SubscriptionListResult struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+	Total         int64           `json:"total"`
+	Page          int             `json:"page"`
+	PageSize      int             `json:"page_size"`
+	TotalPages    int             `json:"total_pages"`
+}
+
+// TopicRepository stores topics and their subscriptions
+type TopicRepository interface {
+	// Create stores a new topic, returning ErrAlreadyExists when the name is taken
+	Create(ctx context.Context, topic *Topic) error
+	GetByName(ctx context.Context, name string) (*Topic, error)
+	List(ctx context.Context, filter TopicFilter) (*TopicListResult, error)
+	// Delete deletes a topic with its subscriptions and publications
+	Delete(ctx context.Context, name string) error
+	// Subscribe creates a subscription or replaces the channels of the same
+	// recipient's subscription to the topic. It returns ErrRecipientNotFound
+	// when the recipient has no profile.
+	Subscribe(ctx context.Context, subscription *Subscription) error
+	Unsubscribe(ctx context.Context, topic, userID string) error
+	ListSubscriptions(ctx context.Context, topic string, filter SubscriptionFilter) (*SubscriptionListResult, error)
+	// ListSubscriptionsAfter returns up to limit subscriptions to topic with
+	// an ID greater than afterID, in ID order
+	ListSubscriptionsAfter(ctx context.Context, topic string, afterID int64, limit int) ([]*Subscription, error)
+}
+
+// PublicationRepository stores publications
+type PublicationRepository interface {
+	Create(ctx context.Context, publication *Publication) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Publication, error)
+	// Update stores publication if its Version still matches the stored row,
+	// incrementing Version. It returns ErrVersionConflict otherwise.
+	Update(ctx context.Context, publication *Publication) error
+	// ListDue returns pending and running publications, oldest first
+	ListDue(ctx context.Context, limit int) ([]*Publication, error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTopicName(t *testing.T) {
+	assert.NoError(t, ValidateTopicName("price-drop.product-123"))
+	assert.NoError(t, ValidateTopicName("news:tr_TR"))
+	assert.Error(t, ValidateTopicName(""))
+	assert.Error(t, ValidateTopicName("-leading-dash"))
+	assert.Error(t, ValidateTopicName("has space"))
+}
+
+func TestPublication_Progress(t *testing.T) {
+	now := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC)
+	p := NewPublication("price-drop.product-123")
+
+	p.Advance(300, 300, 550, 50)
+	p.Advance(420, 120, 240, 0)
+	assert.Equal(t, PublicationRunning, p.Status)
+	assert.Equal(t, int64(420), p.LastSubscriptionID)
+	assert.Equal(t, int64(420), p.Subscribers)
+	assert.Equal(t, int64(790), p.Notifications)
+	assert.Equal(t, int64(50), p.Skipped)
+	assert.False(t, p.Status.IsFinal())
+
+	p.Complete(now)
+	assert.True(t, p.Status.IsFinal())
+	assert.Equal(t, now, *p.CompletedAt)
+
+	assert.Equal(t, "topic:"+p.ID.String()+":7:sms", p.SubscriptionKey(7, ChannelSMS))
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrackingMetadataKey is the notification metadata key that opts a single
+// notification out of open and click tracking when set to false
+const TrackingMetadataKey = "tracking"
+
+// TrackingEventType is the kind of engagement recorded for a notification
+type TrackingEventType string
+
+const (
+	TrackingEventOpen  TrackingEventType = "open"
+	TrackingEventClick TrackingEventType = "click"
+)
+
+// TrackingEvent is a single open or click of an email notification
+type TrackingEvent struct {
+	ID             uuid.UUID         `json:"id"`
+	NotificationID uuid.UUID         `json:"notification_id"`
+	Type           TrackingEventType `json:"type"`
+	URL            *string           `json:"url,omitempty"`
+	UserAgent      string            `json:"user_agent,omitempty"`
+	IPAddress      string            `json:"ip_address,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// NewTrackingEvent creates a new tracking event
+func NewTrackingEvent(notificationID uuid.UUID, eventType TrackingEventType) *TrackingEvent {
+	return &TrackingEvent{
+		ID:             uuid.New(),
+		NotificationID: notificationID,
+		Type:           eventType,
+		CreatedAt:      time.Now().UTC(),
+	}
+}
+
+// BatchEngagement aggregates the opens and clicks of the emails in a batch.
+// Opened and Clicked count notifications with at least one event, Opens and
+// Clicks count all events.
+type BatchEngagement struct {
+	BatchID uuid.UUID `json:"batch_id"`
+	Emails  int64     `json:"emails"`
+	Opened  int64     `json:"opened"`
+	Clicked int64     `json:"clicked"`
+	Opens   int64     `json:"opens"`
+	Clicks  int64     `json:"clicks"`
+}
+
+// TrackingRepository stores tracking events
+type TrackingRepository interface {
+	// RecordEvent stores event and increments the matching counter on its notification
+	RecordEvent(ctx context.Context, event *TrackingEvent) error
+	GetBatchEngagement(ctx context.Context, batchID uuid.UUID) (*BatchEngagement, error)
+}
+
+// ContentRewriter rewrites notification content right before it is sent
+type ContentRewriter interface {
+	Rewrite(notification *Notification) string
+}
+
+// TrackingEnabled reports whether the notification has not opted out of tracking
+func (n *Notification) TrackingEnabled() bool {
+	switch v := n.Metadata[TrackingMetadataKey].(type) {
+	case bool:
+		return v
+	case string:
+		return v != "false"
+	}
+	return true
+}
+
+// DisableTracking opts the notification out of open and click tracking
+func (n *Notification) DisableTracking() {
+	metadata := make(map[string]any, len(n.Metadata)+1)
+	for k, v := range n.Metadata {
+		metadata[k] = v
+	}
+	metadata[TrackingMetadataKey] = false
+	n.Metadata = metadata
+}
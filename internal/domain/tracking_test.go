@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_TrackingEnabled(t *testing.T) {
+	n := NewNotification("user@example.com", ChannelEmail, "Hi")
+	assert.True(t, n.TrackingEnabled())
+
+	n.Metadata = map[string]any{TrackingMetadataKey: "false"}
+	assert.False(t, n.TrackingEnabled())
+
+	metadata := map[string]any{"campaign": "spring"}
+	n.Metadata = metadata
+	n.DisableTracking()
+	assert.False(t, n.TrackingEnabled())
+	assert.Equal(t, "spring", n.Metadata["campaign"])
+	assert.NotContains(t, metadata, TrackingMetadataKey, "caller metadata must not be modified")
+}
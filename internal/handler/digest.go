@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// DigestHandler handles digest rule HTTP requests
+type DigestHandler struct {
+	service  *service.DigestService
+	validate *validator.Validate
+}
+
+// NewDigestHandler creates a new DigestHandler
+func NewDigestHandler(service *service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers digest rule routes
+func (h *DigestHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// CreateDigestRuleRequest represents a request to create a digest rule
+type CreateDigestRuleRequest struct {
+	Category string         `json:"category" validate:"required,max=50" example:"comment"`
+	Channel  domain.Channel `json:"channel" validate:"required,oneof=sms email push" example:"push"`
+	// Window is how long, in seconds, notifications are held after the
+	// first one before the digest is sent
+	Window int `json:"window" validate:"required,min=1" example:"300"`
+	// MaxCount sends the digest early once this many notifications are held
+	MaxCount     int    `json:"max_count" validate:"required,min=2" example:"20"`
+	TemplateName string `json:"template_name" validate:"required" example:"comment_digest"`
+}
+
+// UpdateDigestRuleRequest represents a request to update a digest rule
+type UpdateDigestRuleRequest struct {
+	Window       *int    `json:"window,omitempty" validate:"omitempty,min=1" example:"600"`
+	MaxCount     *int    `json:"max_count,omitempty" validate:"omitempty,min=2" example:"50"`
+	TemplateName *string `json:"template_name,omitempty" example:"comment_digest"`
+}
+
+// Create creates a digest rule
+// @Summary Create digest rule
+// @Description Hold notifications of a category sent to a recipient on a channel and merge them into a single digest once the window passes or max_count notifications are held
+// @Tags digests
+// @Accept json
+// @Produce json
+// @Param rule body CreateDigestRuleRequest true "Digest rule"
+// @Success 201 {object} Response{data=domain.DigestRule}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/digest-rules [post]
+func (h *DigestHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateDigestRuleRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.service.Create(r.Context(), service.CreateDigestRuleRequest{
+		Category:     req.Category,
+		Channel:      req.Channel,
+		Window:       req.Window,
+		MaxCount:     req.MaxCount,
+		TemplateName: req.TemplateName,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, rule)
+}
+
+// List lists digest rules
+// @Summary List digest rules
+// @Description List all digest rules
+// @Tags digests
+// @Produce json
+// @Success 200 {object} Response{data=[]domain.DigestRule}
+// @Failure 500 {object} Response
+// @Router /api/v1/digest-rules [get]
+func (h *DigestHandler) List(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.List(r.Context())
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, rules)
+}
+
+// GetByID retrieves a digest rule by ID
+// @Summary Get digest rule
+// @Description Get a digest rule by ID
+// @Tags digests
+// @Produce json
+// @Param id path string true "Digest rule ID"
+// @Success 200 {object} Response{data=domain.DigestRule}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/digest-rules/{id} [get]
+func (h *DigestHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDigestRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, rule)
+}
+
+// Update updates a digest rule
+// @Summary Update digest rule
+// @Description Change the window, max count or template of a digest rule; notifications already held follow the new settings
+// @Tags digests
+// @Accept json
+// @Produce json
+// @Param id path string true "Digest rule ID"
+// @Param rule body UpdateDigestRuleRequest true "Digest rule changes"
+// @Success 200 {object} Response{data=domain.DigestRule}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/digest-rules/{id} [put]
+func (h *DigestHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDigestRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateDigestRuleRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.service.Update(r.Context(), id, service.UpdateDigestRuleRequest{
+		Window:       req.Window,
+		MaxCount:     req.MaxCount,
+		TemplateName: req.TemplateName,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, rule)
+}
+
+// Delete deletes a digest rule
+// @Summary Delete digest rule
+// @Description Delete a digest rule; notifications it holds are sent on their own on the next scheduler tick
+// @Tags digests
+// @Produce json
+// @Param id path string true "Digest rule ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/digest-rules/{id} [delete]
+func (h *DigestHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDigestRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Digest rule deleted successfully",
+	})
+}
+
+func parseDigestRuleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid digest rule ID", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// maxInboundBodySize bounds the size of an inbound message payload
+const maxInboundBodySize = 1 << 20
+
+// InboundHandler handles inbound messages posted by providers
+type InboundHandler struct {
+	service *service.InboundService
+	parsers map[string]domain.InboundParser
+}
+
+// NewInboundHandler creates a new InboundHandler
+func NewInboundHandler(service *service.InboundService, parsers map[string]domain.InboundParser) *InboundHandler {
+	return &InboundHandler{
+		service: service,
+		parsers: parsers,
+	}
+}
+
+// RegisterRoutes registers inbound routes
+func (h *InboundHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/{provider}", h.Receive)
+	r.Get("/messages", h.List)
+	r.Get("/messages/{id}", h.GetByID)
+}
+
+// Receive stores signed inbound messages from a provider
+// @Summary Receive inbound messages
+// @Description Accept signed inbound SMS from a provider. Opt-out keywords such as STOP suppress SMS to the sender and opt-in keywords such as START lift the unsubscribe. Messages are forwarded to the configured callback URL.
+// @Tags inbound
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param X-Signature header string true "Hex HMAC-SHA256 of '<timestamp>.<body>'"
+// @Param X-Timestamp header string true "Unix timestamp used in the signature"
+// @Success 200 {object} Response{data=service.InboundResult}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/inbound/{provider} [post]
+func (h *InboundHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	parser, ok := h.parsers[provider]
+	if !ok {
+		JSONError(w, http.StatusNotFound, "UNKNOWN_PROVIDER", "Inbound messages are not enabled for this provider", nil)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			JSONError(w, http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "Inbound body is too large", nil)
+			return
+		}
+		JSONError(w, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body", nil)
+		return
+	}
+
+	if err := parser.Verify(r.Header, body); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	messages, err := parser.Parse(body)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	result, err := h.service.Receive(r.Context(), provider, messages)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// List lists inbound messages
+// @Summary List inbound messages
+// @Description List inbound messages with optional filters and pagination
+// @Tags inbound
+// @Produce json
+// @Param from query string false "Filter by sender"
+// @Param provider query string false "Filter by provider"
+// @Param action query string false "Filter by action (opt_out, opt_in)"
+// @Param start_date query string false "Filter by start date (RFC3339)"
+// @Param end_date query string false "Filter by end date (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.InboundListResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/inbound/messages [get]
+func (h *InboundHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := domain.InboundFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if from := r.URL.Query().Get("from"); from != "" {
+		filter.From = &from
+	}
+
+	if provider := r.URL.Query().Get("provider"); provider != "" {
+		filter.Provider = &provider
+	}
+
+	if action := r.URL.Query().Get("action"); action != "" {
+		a := domain.InboundAction(action)
+		if a != domain.InboundActionOptOut && a != domain.InboundActionOptIn {
+			JSONError(w, http.StatusBadRequest, "INVALID_ACTION", "Action must be opt_out or opt_in", nil)
+			return
+		}
+		filter.Action = &a
+	}
+
+	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
+		startDate, err := time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_START_DATE", "Invalid start date format (use RFC3339)", nil)
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
+		endDate, err := time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_END_DATE", "Invalid end date format (use RFC3339)", nil)
+			return
+		}
+		filter.EndDate = &endDate
+	}
+
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE", "Invalid page number", nil)
+			return
+		}
+		filter.Page = page
+	}
+
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", "Page size must be between 1 and 100", nil)
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	result, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetByID retrieves an inbound message by ID
+// @Summary Get inbound message
+// @Description Get an inbound message by its ID
+// @Tags inbound
+// @Produce json
+// @Param id path string true "Inbound message ID"
+// @Success 200 {object} Response{data=domain.InboundMessage}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/inbound/messages/{id} [get]
+func (h *InboundHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid inbound message ID", nil)
+		return
+	}
+
+	message, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, message)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,6 +21,11 @@ type Metrics struct {
 	notificationsFailed *prometheus.CounterVec
 	queueDepth          *prometheus.GaugeVec
 	processingLatency   *prometheus.HistogramVec
+	providerRequests    *prometheus.CounterVec
+	providerLatency     *prometheus.HistogramVec
+	providerErrors      *prometheus.CounterVec
+	notificationCost    *prometheus.CounterVec
+	deliveryLatency     *prometheus.HistogramVec
 }
 
 // NewMetrics creates new Prometheus metrics
@@ -69,6 +75,43 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"channel"},
 		),
+		providerRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_requests_total",
+				Help: "Total number of requests sent to each provider",
+			},
+			[]string{"provider", "channel", "result"},
+		),
+		providerLatency: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "provider_request_duration_seconds",
+				Help:    "Provider request duration in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"provider", "channel"},
+		),
+		providerErrors: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_errors_total",
+				Help: "Total number of failed provider requests by HTTP status",
+			},
+			[]string{"provider", "channel", "status", "outcome"},
+		),
+		notificationCost: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "notification_cost_total",
+				Help: "Total cost of sent notifications in the configured currency",
+			},
+			[]string{"provider", "channel", "currency"},
+		),
+		deliveryLatency: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "notification_delivery_latency_seconds",
+				Help:    "Time from send to delivery reported by the provider",
+				Buckets: []float64{1, 5, 10, 30, 60, 300, 900, 3600},
+			},
+			[]string{"provider", "channel"},
+		),
 	}
 }
 
@@ -98,6 +141,37 @@ func (m *Metrics) RecordProcessingLatency(channel string, latency time.Duration)
 	m.processingLatency.WithLabelValues(channel).Observe(latency.Seconds())
 }
 
+// RecordDeliveryAttempt records the outcome, latency and error status of a provider request
+func (m *Metrics) RecordDeliveryAttempt(attempt *domain.DeliveryAttempt) {
+	provider := attempt.Provider
+	if provider == "" {
+		provider = "unknown"
+	}
+	channel := string(attempt.Channel)
+
+	result := "success"
+	if attempt.Outcome != domain.AttemptSucceeded {
+		result = "failure"
+		status := "none"
+		if attempt.HTTPStatus != nil {
+			status = strconv.Itoa(*attempt.HTTPStatus)
+		}
+		m.providerErrors.WithLabelValues(provider, channel, status, string(attempt.Outcome)).Inc()
+	}
+	m.providerRequests.WithLabelValues(provider, channel, result).Inc()
+	m.providerLatency.WithLabelValues(provider, channel).Observe(float64(attempt.LatencyMs) / 1000)
+}
+
+// RecordNotificationCost records the cost of a sent notification
+func (m *Metrics) RecordNotificationCost(provider, channel, currency string, cost float64) {
+	m.notificationCost.WithLabelValues(provider, channel, currency).Add(cost)
+}
+
+// RecordDeliveryLatency records the time from send to confirmed delivery
+func (m *Metrics) RecordDeliveryLatency(provider, channel string, latency time.Duration) {
+	m.deliveryLatency.WithLabelValues(provider, channel).Observe(latency.Seconds())
+}
+
 // MetricsHandler handles metrics endpoints
 type MetricsHandler struct {
 	metrics *Metrics
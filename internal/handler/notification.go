@@ -33,14 +33,23 @@ func (h *NotificationHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/batch", h.CreateBatch)
 	r.Get("/", h.List)
 	r.Get("/{id}", h.GetByID)
+	r.Get("/{id}/events", h.GetEvents)
+	r.Get("/{id}/attempts", h.GetAttempts)
 	r.Get("/batch/{batchId}", h.GetByBatchID)
+	r.Post("/batch/{batchId}/cancel", h.CancelBatch)
+	r.Post("/cancel", h.CancelMatching)
+	r.Post("/{id}/retry", h.Retry)
+	r.Post("/retry", h.RetryMatching)
+	r.Patch("/{id}", h.Patch)
 	r.Delete("/{id}", h.Cancel)
 }
 
 // CreateNotificationRequest represents a request to create a notification
 // @Description Request to create a notification
 type CreateNotificationRequest struct {
-	Recipient      string            `json:"recipient" validate:"required" example:"+905551234567"`
+	Recipient string `json:"recipient" validate:"required_without=UserID" example:"+905551234567"`
+	// UserID addresses the notification to a recipient profile instead of Recipient
+	UserID         *string           `json:"user_id,omitempty" validate:"omitempty,max=255" example:"user-42"`
 	Channel        domain.Channel    `json:"channel" validate:"required,oneof=sms email push" example:"sms"`
 	Content        string            `json:"content" example:"Your verification code is 123456"`
 	Priority       domain.Priority   `json:"priority" validate:"omitempty,oneof=high normal low" example:"normal"`
@@ -49,6 +58,10 @@ type CreateNotificationRequest struct {
 	Metadata       map[string]any    `json:"metadata,omitempty"`
 	TemplateName   *string           `json:"template_name,omitempty" example:"welcome_sms"`
 	TemplateVars   map[string]string `json:"template_vars,omitempty"`
+	Category       string            `json:"category,omitempty" validate:"omitempty,max=50" example:"otp"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	TTL            *int              `json:"ttl,omitempty" validate:"omitempty,min=1" example:"600"`
+	TimeZone       string            `json:"timezone,omitempty" example:"Europe/Istanbul"`
 }
 
 // Create creates a single notification
@@ -76,6 +89,7 @@ func (h *NotificationHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	notification, err := h.service.Create(r.Context(), service.CreateRequest{
 		Recipient:      req.Recipient,
+		UserID:         req.UserID,
 		Channel:        req.Channel,
 		Content:        req.Content,
 		Priority:       req.Priority,
@@ -84,6 +98,10 @@ func (h *NotificationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Metadata:       req.Metadata,
 		TemplateName:   req.TemplateName,
 		TemplateVars:   req.TemplateVars,
+		Category:       req.Category,
+		ExpiresAt:      req.ExpiresAt,
+		TTL:            req.TTL,
+		TimeZone:       req.TimeZone,
 	})
 	if err != nil {
 		HandleError(w, err)
@@ -125,6 +143,7 @@ func (h *NotificationHandler) CreateBatch(w http.ResponseWriter, r *http.Request
 	for i, n := range req.Notifications {
 		createRequests[i] = service.CreateRequest{
 			Recipient:      n.Recipient,
+			UserID:         n.UserID,
 			Channel:        n.Channel,
 			Content:        n.Content,
 			Priority:       n.Priority,
@@ -133,6 +152,10 @@ func (h *NotificationHandler) CreateBatch(w http.ResponseWriter, r *http.Request
 			Metadata:       n.Metadata,
 			TemplateName:   n.TemplateName,
 			TemplateVars:   n.TemplateVars,
+			Category:       n.Category,
+			ExpiresAt:      n.ExpiresAt,
+			TTL:            n.TTL,
+			TimeZone:       n.TimeZone,
 		}
 	}
 
@@ -178,6 +201,66 @@ func (h *NotificationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, notification)
 }
 
+// GetEvents retrieves the status history of a notification
+// @Summary Get notification status history
+// @Description Get every status transition of a notification, oldest first
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} Response{data=[]domain.StatusEvent}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/{id}/events [get]
+func (h *NotificationHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid notification ID", nil)
+		return
+	}
+
+	events, err := h.service.GetEvents(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]any{
+		"notification_id": id,
+		"events":          events,
+	})
+}
+
+// GetAttempts retrieves the provider requests made for a notification
+// @Summary Get notification delivery attempts
+// @Description Get every provider request made for a notification with its latency, HTTP status and redacted response
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} Response{data=[]domain.DeliveryAttempt}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/{id}/attempts [get]
+func (h *NotificationHandler) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid notification ID", nil)
+		return
+	}
+
+	attempts, err := h.service.GetAttempts(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]any{
+		"notification_id": id,
+		"attempts":        attempts,
+	})
+}
+
 // GetByBatchID retrieves all notifications in a batch
 // @Summary Get notifications by batch ID
 // @Description Get all notifications in a batch
@@ -209,6 +292,252 @@ func (h *NotificationHandler) GetByBatchID(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// CancelBatch cancels every notification of a batch that has not started processing
+// @Summary Cancel batch
+// @Description Cancel every pending, scheduled or queued notification of a batch
+// @Tags notifications
+// @Produce json
+// @Param batchId path string true "Batch ID"
+// @Success 200 {object} Response{data=domain.BulkCancelResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/batch/{batchId}/cancel [post]
+func (h *NotificationHandler) CancelBatch(w http.ResponseWriter, r *http.Request) {
+	batchIDStr := chi.URLParam(r, "batchId")
+	batchID, err := uuid.Parse(batchIDStr)
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid batch ID", nil)
+		return
+	}
+
+	result, err := h.service.CancelBatch(r.Context(), batchID)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// BulkCancelRequest selects the notifications to cancel; at least one
+// field is required
+type BulkCancelRequest struct {
+	Status    *domain.Status  `json:"status,omitempty" validate:"omitempty,oneof=pending scheduled queued" example:"scheduled"`
+	Channel   *domain.Channel `json:"channel,omitempty" validate:"omitempty,oneof=sms email push" example:"sms"`
+	BatchID   *uuid.UUID      `json:"batch_id,omitempty"`
+	StartDate *time.Time      `json:"start_date,omitempty"`
+	EndDate   *time.Time      `json:"end_date,omitempty"`
+	// Metadata matches notifications whose metadata contains every key and value
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// CancelMatching cancels every notification matching a filter
+// @Summary Bulk cancel notifications
+// @Description Cancel every pending, scheduled or queued notification matching the filter
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param filter body BulkCancelRequest true "Notifications to cancel"
+// @Success 200 {object} Response{data=domain.BulkCancelResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/cancel [post]
+func (h *NotificationHandler) CancelMatching(w http.ResponseWriter, r *http.Request) {
+	var req BulkCancelRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.service.CancelMatching(r.Context(), domain.NotificationFilter{
+		Status:    req.Status,
+		Channel:   req.Channel,
+		BatchID:   req.BatchID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Metadata:  req.Metadata,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// RetryNotificationRequest optionally overrides the priority of a retried
+// notification
+type RetryNotificationRequest struct {
+	Priority *domain.Priority `json:"priority,omitempty" validate:"omitempty,oneof=high normal low" example:"high"`
+}
+
+// Retry queues a failed notification again
+// @Summary Retry notification
+// @Description Queue a failed notification again with its retry count reset, optionally at a different priority. The body is optional.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Param retry body RetryNotificationRequest false "Priority override"
+// @Success 200 {object} Response{data=domain.Notification}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/{id}/retry [post]
+func (h *NotificationHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid notification ID", nil)
+		return
+	}
+
+	var req RetryNotificationRequest
+	if r.ContentLength != 0 {
+		if err := DecodeJSON(r, &req); err != nil {
+			HandleError(w, err)
+			return
+		}
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	notification, err := h.service.Retry(r.Context(), id, req.Priority)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, notification)
+}
+
+// BulkRetryRequest selects the failed notifications to retry; at least one
+// filter field is required
+type BulkRetryRequest struct {
+	Channel *domain.Channel `json:"channel,omitempty" validate:"omitempty,oneof=sms email push" example:"sms"`
+	BatchID *uuid.UUID      `json:"batch_id,omitempty"`
+	// FailedAfter and FailedBefore bound the time the notification failed
+	FailedAfter  *time.Time `json:"failed_after,omitempty"`
+	FailedBefore *time.Time `json:"failed_before,omitempty"`
+	// Provider and ProviderStatus match the last delivery attempt
+	Provider       *string `json:"provider,omitempty" example:"twilio"`
+	ProviderStatus *int    `json:"provider_status,omitempty" validate:"omitempty,min=100,max=599" example:"503"`
+	// Metadata matches notifications whose metadata contains every key and value
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// Priority overrides the priority of every retried notification
+	Priority *domain.Priority `json:"priority,omitempty" validate:"omitempty,oneof=high normal low" example:"high"`
+}
+
+// RetryMatching queues every failed notification matching a filter again
+// @Summary Bulk retry notifications
+// @Description Queue every failed, unexpired notification matching the filter again
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param filter body BulkRetryRequest true "Notifications to retry"
+// @Success 200 {object} Response{data=domain.BulkRetryResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/retry [post]
+func (h *NotificationHandler) RetryMatching(w http.ResponseWriter, r *http.Request) {
+	var req BulkRetryRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.service.RetryMatching(r.Context(), domain.RetryFilter{
+		Channel:        req.Channel,
+		BatchID:        req.BatchID,
+		FailedAfter:    req.FailedAfter,
+		FailedBefore:   req.FailedBefore,
+		Provider:       req.Provider,
+		ProviderStatus: req.ProviderStatus,
+		Metadata:       req.Metadata,
+	}, req.Priority)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// PatchNotificationRequest represents a change to a notification that has
+// not started processing
+type PatchNotificationRequest struct {
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	// SendNow queues a scheduled notification for immediate delivery
+	SendNow      bool              `json:"send_now,omitempty" example:"false"`
+	Priority     *domain.Priority  `json:"priority,omitempty" validate:"omitempty,oneof=high normal low" example:"high"`
+	Content      *string           `json:"content,omitempty" example:"Your appointment moved to 15:00"`
+	TemplateName *string           `json:"template_name,omitempty" example:"appointment_reminder"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+}
+
+// Patch changes a notification that has not started processing
+// @Summary Edit notification
+// @Description Change the send time, priority or content of a pending, scheduled or queued notification
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Param notification body PatchNotificationRequest true "Changes"
+// @Success 200 {object} Response{data=domain.Notification}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/{id} [patch]
+func (h *NotificationHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid notification ID", nil)
+		return
+	}
+
+	var req PatchNotificationRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	notification, err := h.service.Patch(r.Context(), id, service.PatchRequest{
+		ScheduledAt:  req.ScheduledAt,
+		SendNow:      req.SendNow,
+		Priority:     req.Priority,
+		Content:      req.Content,
+		TemplateName: req.TemplateName,
+		TemplateVars: req.TemplateVars,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, notification)
+}
+
 // Cancel cancels a pending notification
 // @Summary Cancel notification
 // @Description Cancel a pending notification
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// PlanHandler handles multi-channel fallback plan HTTP requests
+type PlanHandler struct {
+	service  *service.PlanService
+	validate *validator.Validate
+}
+
+// NewPlanHandler creates a new PlanHandler
+func NewPlanHandler(service *service.PlanService) *PlanHandler {
+	return &PlanHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers plan routes
+func (h *PlanHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/cancel", h.Cancel)
+}
+
+// PlanStepRequest represents one channel and recipient of a fallback plan
+type PlanStepRequest struct {
+	Channel   domain.Channel `json:"channel" validate:"required,oneof=sms email push" example:"push"`
+	Recipient string         `json:"recipient" validate:"required" example:"device-token-123"`
+	// Content and TemplateName override the plan's content for this step
+	Content      *string `json:"content,omitempty" example:"Server db-1 is down"`
+	TemplateName *string `json:"template_name,omitempty" example:"incident_email"`
+	// Timeout is how long, in seconds, the step may take to be delivered
+	// before the next step is tried
+	Timeout int `json:"timeout" validate:"required,min=1" example:"300"`
+}
+
+// CreatePlanRequest represents a request to create a fallback plan
+type CreatePlanRequest struct {
+	Content      string            `json:"content,omitempty" example:"Server db-1 is down"`
+	TemplateName *string           `json:"template_name,omitempty" example:"incident_alert"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority" validate:"omitempty,oneof=high normal low" example:"high"`
+	Category     string            `json:"category,omitempty" example:"alert"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	Steps        []PlanStepRequest `json:"steps" validate:"required,min=1,max=5,dive"`
+}
+
+// Create creates a fallback plan
+// @Summary Create plan
+// @Description Send a notification through an ordered list of channels, falling back to the next step when a step fails or is not delivered within its timeout
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param plan body CreatePlanRequest true "Plan request"
+// @Success 201 {object} Response{data=domain.Plan}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/plans [post]
+func (h *PlanHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreatePlanRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	steps := make([]service.PlanStepRequest, 0, len(req.Steps))
+	for _, step := range req.Steps {
+		steps = append(steps, service.PlanStepRequest{
+			Channel:      step.Channel,
+			Recipient:    step.Recipient,
+			Content:      step.Content,
+			TemplateName: step.TemplateName,
+			Timeout:      step.Timeout,
+		})
+	}
+
+	plan, err := h.service.Create(r.Context(), service.CreatePlanRequest{
+		Content:      req.Content,
+		TemplateName: req.TemplateName,
+		TemplateVars: req.TemplateVars,
+		Priority:     req.Priority,
+		Category:     req.Category,
+		Metadata:     req.Metadata,
+		Steps:        steps,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, plan)
+}
+
+// GetByID retrieves a plan by ID
+// @Summary Get plan
+// @Description Get a fallback plan with its aggregated status and the notification of each started step
+// @Tags plans
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} Response{data=domain.Plan}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/plans/{id} [get]
+func (h *PlanHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePlanID(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, plan)
+}
+
+// Cancel cancels a plan
+// @Summary Cancel plan
+// @Description Stop an active plan; its current notification is cancelled unless a worker already picked it up
+// @Tags plans
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} Response{data=domain.Plan}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/plans/{id}/cancel [post]
+func (h *PlanHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePlanID(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.service.Cancel(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, plan)
+}
+
+func parsePlanID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid plan ID", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
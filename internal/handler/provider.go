@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// ProviderHandler handles provider administration HTTP requests
+type ProviderHandler struct {
+	service  *service.RoutingService
+	validate *validator.Validate
+}
+
+// NewProviderHandler creates a new ProviderHandler
+func NewProviderHandler(service *service.RoutingService) *ProviderHandler {
+	return &ProviderHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers provider admin routes
+func (h *ProviderHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.Overview)
+	r.Put("/routes/{channel}", h.SetRoutes)
+}
+
+// Overview returns provider routes and statistics
+// @Summary Provider routing overview
+// @Description Get per-channel provider weights and each provider's attempts, error rate and latency over the last hour, from the delivery log
+// @Tags admin
+// @Produce json
+// @Success 200 {object} Response{data=service.RoutingOverview}
+// @Failure 500 {object} Response
+// @Router /api/v1/admin/providers [get]
+func (h *ProviderHandler) Overview(w http.ResponseWriter, r *http.Request) {
+	overview, err := h.service.Overview(r.Context())
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, overview)
+}
+
+// SetRoutesRequest represents a request to change provider weights
+type SetRoutesRequest struct {
+	Weights []domain.RouteWeight `json:"weights" validate:"required,min=1,dive"`
+}
+
+// SetRoutes changes the provider weights for a channel
+// @Summary Set provider weights
+// @Description Set the weighted provider split for a channel. Recipients stay on the same provider while weights are unchanged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param channel path string true "Channel"
+// @Param request body SetRoutesRequest true "Weights"
+// @Success 200 {object} Response{data=service.RoutingOverview}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/admin/providers/routes/{channel} [put]
+func (h *ProviderHandler) SetRoutes(w http.ResponseWriter, r *http.Request) {
+	channel := domain.Channel(chi.URLParam(r, "channel"))
+	if !channel.IsValid() {
+		JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+		return
+	}
+
+	var req SetRoutesRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	if err := h.service.SetRoutes(r.Context(), channel, req.Weights); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	overview, err := h.service.Overview(r.Context())
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, overview)
+}
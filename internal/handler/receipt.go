@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// maxReceiptBodySize bounds the size of a receipt payload
+const maxReceiptBodySize = 1 << 20
+
+// ReceiptHandler handles delivery receipts posted by providers
+type ReceiptHandler struct {
+	service *service.ReceiptService
+	parsers map[string]domain.ReceiptParser
+}
+
+// NewReceiptHandler creates a new ReceiptHandler
+func NewReceiptHandler(service *service.ReceiptService, parsers map[string]domain.ReceiptParser) *ReceiptHandler {
+	return &ReceiptHandler{
+		service: service,
+		parsers: parsers,
+	}
+}
+
+// RegisterRoutes registers receipt routes
+func (h *ReceiptHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/{provider}", h.Receive)
+}
+
+// Receive applies signed delivery receipts from a provider
+// @Summary Receive delivery receipts
+// @Description Accept signed delivery receipts from a provider and move the matching sent notifications to delivered or undeliverable
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param X-Signature header string true "Hex HMAC-SHA256 of '<timestamp>.<body>'"
+// @Param X-Timestamp header string true "Unix timestamp used in the signature"
+// @Success 200 {object} Response{data=service.ReceiptResult}
+// @Failure 400 {object} Response
+// @Failure 401 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/receipts/{provider} [post]
+func (h *ReceiptHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	parser, ok := h.parsers[provider]
+	if !ok {
+		JSONError(w, http.StatusNotFound, "UNKNOWN_PROVIDER", "Receipts are not enabled for this provider", nil)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxReceiptBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			JSONError(w, http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "Receipt body is too large", nil)
+			return
+		}
+		JSONError(w, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body", nil)
+		return
+	}
+
+	if err := parser.Verify(r.Header, body); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	receipts, err := parser.Parse(body)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	result, err := h.service.Process(r.Context(), provider, receipts)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
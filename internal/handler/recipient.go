@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// RecipientHandler handles recipient profile HTTP requests
+type RecipientHandler struct {
+	service  *service.RecipientService
+	validate *validator.Validate
+}
+
+// NewRecipientHandler creates a new RecipientHandler
+func NewRecipientHandler(service *service.RecipientService) *RecipientHandler {
+	return &RecipientHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers recipient routes
+func (h *RecipientHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Post("/import", h.Import)
+	r.Get("/", h.List)
+	r.Get("/{userId}", h.Get)
+	r.Put("/{userId}", h.Update)
+	r.Delete("/{userId}", h.Delete)
+}
+
+// RecipientProfile represents the addresses of a user
+type RecipientProfile struct {
+	Phone        *string  `json:"phone,omitempty" validate:"omitempty,max=50" example:"+905551234567"`
+	Email        *string  `json:"email,omitempty" validate:"omitempty,max=255" example:"user@example.com"`
+	DeviceTokens []string `json:"device_tokens,omitempty" validate:"omitempty,max=20"`
+	Locale       string   `json:"locale,omitempty" validate:"omitempty,max=35" example:"tr-TR"`
+	TimeZone     string   `json:"timezone,omitempty" example:"Europe/Istanbul"`
+}
+
+// CreateRecipientRequest represents a request to create a recipient profile
+type CreateRecipientRequest struct {
+	UserID string `json:"user_id" validate:"required,max=255" example:"user-42"`
+	RecipientProfile
+}
+
+// ImportRecipientsRequest represents a bulk upsert of recipient profiles
+type ImportRecipientsRequest struct {
+	Recipients []CreateRecipientRequest `json:"recipients" validate:"required,min=1,max=10000"`
+}
+
+// Create creates a recipient profile
+// @Summary Create recipient
+// @Description Create the profile of a user with the user's address on each channel
+// @Tags recipients
+// @Accept json
+// @Produce json
+// @Param recipient body CreateRecipientRequest true "Recipient request"
+// @Success 201 {object} Response{data=domain.Recipient}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients [post]
+func (h *RecipientHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRecipientRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	recipient, err := h.service.Create(r.Context(), toRecipientRequest(req.UserID, req.RecipientProfile))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, recipient)
+}
+
+// Import creates or replaces many recipient profiles
+// @Summary Import recipients
+// @Description Create or replace up to 10000 recipient profiles at once. Nothing is imported if any entry is invalid.
+// @Tags recipients
+// @Accept json
+// @Produce json
+// @Param recipients body ImportRecipientsRequest true "Import request"
+// @Success 200 {object} Response{data=service.ImportResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients/import [post]
+func (h *RecipientHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req ImportRecipientsRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	importReq := service.ImportRecipientsRequest{
+		Recipients: make([]service.RecipientRequest, 0, len(req.Recipients)),
+	}
+	for _, item := range req.Recipients {
+		importReq.Recipients = append(importReq.Recipients, toRecipientRequest(item.UserID, item.RecipientProfile))
+	}
+
+	result, err := h.service.Import(r.Context(), importReq)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// List lists recipient profiles
+// @Summary List recipients
+// @Description List recipient profiles with pagination
+// @Tags recipients
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.RecipientListResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients [get]
+func (h *RecipientHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := domain.RecipientFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE", "Invalid page number", nil)
+			return
+		}
+		filter.Page = page
+	}
+
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", "Page size must be between 1 and 100", nil)
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	result, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// Get retrieves a recipient profile
+// @Summary Get recipient
+// @Description Get the profile of a user
+// @Tags recipients
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} Response{data=domain.Recipient}
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients/{userId} [get]
+func (h *RecipientHandler) Get(w http.ResponseWriter, r *http.Request) {
+	recipient, err := h.service.Get(r.Context(), chi.URLParam(r, "userId"))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, recipient)
+}
+
+// Update replaces a recipient profile
+// @Summary Update recipient
+// @Description Replace the addresses, locale and time zone of a user
+// @Tags recipients
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param recipient body RecipientProfile true "Recipient profile"
+// @Success 200 {object} Response{data=domain.Recipient}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients/{userId} [put]
+func (h *RecipientHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req RecipientProfile
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	recipient, err := h.service.Update(r.Context(), toRecipientRequest(chi.URLParam(r, "userId"), req))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, recipient)
+}
+
+// Delete deletes a recipient profile
+// @Summary Delete recipient
+// @Description Delete the profile of a user
+// @Tags recipients
+// @Param userId path string true "User ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/recipients/{userId} [delete]
+func (h *RecipientHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), chi.URLParam(r, "userId")); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Recipient deleted successfully",
+	})
+}
+
+func toRecipientRequest(userID string, profile RecipientProfile) service.RecipientRequest {
+	return service.RecipientRequest{
+		UserID:       userID,
+		Phone:        profile.Phone,
+		Email:        profile.Email,
+		DeviceTokens: profile.DeviceTokens,
+		Locale:       profile.Locale,
+		TimeZone:     profile.TimeZone,
+	}
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// ReportHandler handles reporting HTTP requests
+type ReportHandler struct {
+	service *service.ReportService
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(service *service.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// RegisterRoutes registers report routes
+func (h *ReportHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/spend", h.Spend)
+	r.Get("/providers", h.Providers)
+}
+
+// Spend returns recorded notification costs
+// @Summary Spend report
+// @Description Get the cost of sent notifications aggregated by day and channel, optionally split by batch or campaign (metadata.campaign)
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Filter by sent date from (RFC3339)"
+// @Param end_date query string false "Filter by sent date until (RFC3339)"
+// @Param channel query string false "Filter by channel"
+// @Param group_by query string false "Additional grouping (batch, campaign)"
+// @Success 200 {object} Response{data=service.SpendReport}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/reports/spend [get]
+func (h *ReportHandler) Spend(w http.ResponseWriter, r *http.Request) {
+	filter := domain.SpendReportFilter{
+		GroupBy: domain.SpendGroupBy(r.URL.Query().Get("group_by")),
+	}
+
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		c := domain.Channel(channel)
+		if !c.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+			return
+		}
+		filter.Channel = &c
+	}
+
+	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
+		startDate, err := time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_START_DATE", "Invalid start date format (use RFC3339)", nil)
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
+		endDate, err := time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_END_DATE", "Invalid end date format (use RFC3339)", nil)
+			return
+		}
+		filter.EndDate = &endDate
+	}
+
+	report, err := h.service.Spend(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
+
+// Providers returns provider performance computed from delivery attempts
+// @Summary Provider report
+// @Description Get attempt counts, error rates, latency and errors by HTTP status per provider and channel
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Filter by attempt start from (RFC3339)"
+// @Param end_date query string false "Filter by attempt start until (RFC3339)"
+// @Param channel query string false "Filter by channel"
+// @Param provider query string false "Filter by provider"
+// @Success 200 {object} Response{data=service.ProviderReport}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/reports/providers [get]
+func (h *ReportHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	filter := domain.ProviderReportFilter{}
+
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		c := domain.Channel(channel)
+		if !c.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+			return
+		}
+		filter.Channel = &c
+	}
+
+	if provider := r.URL.Query().Get("provider"); provider != "" {
+		filter.Provider = &provider
+	}
+
+	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
+		startDate, err := time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_START_DATE", "Invalid start date format (use RFC3339)", nil)
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
+		endDate, err := time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "INVALID_END_DATE", "Invalid end date format (use RFC3339)", nil)
+			return
+		}
+		filter.EndDate = &endDate
+	}
+
+	report, err := h.service.Providers(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
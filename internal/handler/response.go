@@ -70,12 +70,37 @@ func HandleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrTemplateNotFound):
 		JSONError(w, http.StatusBadRequest, "TEMPLATE_NOT_FOUND", "Template not found", nil)
 
+	case errors.Is(err, domain.ErrRecipientNotFound):
+		JSONError(w, http.StatusBadRequest, "RECIPIENT_NOT_FOUND", err.Error(), nil)
+
+	case errors.Is(err, domain.ErrNoAddress):
+		JSONError(w, http.StatusBadRequest, "NO_ADDRESS", err.Error(), nil)
+
 	case errors.Is(err, domain.ErrMissingVariables):
 		JSONError(w, http.StatusBadRequest, "MISSING_VARIABLES", err.Error(), nil)
 
+	case errors.Is(err, domain.ErrInvalidSignature):
+		JSONError(w, http.StatusUnauthorized, "INVALID_SIGNATURE", "Invalid or missing signature", nil)
+
+	case errors.Is(err, domain.ErrDuplicateContent):
+		var duplicateErr domain.DuplicateError
+		if errors.As(err, &duplicateErr) {
+			JSONError(w, http.StatusConflict, "DUPLICATE_CONTENT", err.Error(), map[string]string{
+				"notification_id": duplicateErr.NotificationID.String(),
+			})
+			return
+		}
+		JSONError(w, http.StatusConflict, "DUPLICATE_CONTENT", err.Error(), nil)
+
 	case errors.Is(err, domain.ErrIdempotencyConflict):
 		JSONError(w, http.StatusConflict, "IDEMPOTENCY_CONFLICT", "Idempotency key already used", nil)
 
+	case errors.Is(err, domain.ErrInvalidStatus):
+		JSONError(w, http.StatusConflict, "INVALID_STATUS", err.Error(), nil)
+
+	case errors.Is(err, domain.ErrVersionConflict):
+		JSONError(w, http.StatusConflict, "VERSION_CONFLICT", "Resource was modified concurrently, retry the request", nil)
+
 	default:
 		var validationErr domain.ValidationError
 		if errors.As(err, &validationErr) {
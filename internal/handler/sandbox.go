@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const defaultSandboxListLimit = 100
+
+// SandboxHandler exposes messages captured by the sandbox provider
+type SandboxHandler struct {
+	store domain.SandboxStore
+}
+
+// NewSandboxHandler creates a new SandboxHandler
+func NewSandboxHandler(store domain.SandboxStore) *SandboxHandler {
+	return &SandboxHandler{store: store}
+}
+
+// RegisterRoutes registers sandbox routes
+func (h *SandboxHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/messages", h.ListMessages)
+	r.Delete("/messages", h.ClearMessages)
+}
+
+// ListMessages lists captured sandbox messages
+// @Summary List sandbox messages
+// @Description List messages captured by the sandbox provider, newest first
+// @Tags sandbox
+// @Produce json
+// @Param recipient query string false "Filter by recipient"
+// @Param limit query int false "Maximum number of messages" default(100)
+// @Success 200 {object} Response{data=[]domain.SandboxMessage}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/sandbox/messages [get]
+func (h *SandboxHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	limit := defaultSandboxListLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_LIMIT", "Limit must be a positive integer", nil)
+			return
+		}
+		limit = parsed
+	}
+
+	messages, err := h.store.List(r.Context(), r.URL.Query().Get("recipient"), limit)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]any{
+		"count":    len(messages),
+		"messages": messages,
+	})
+}
+
+// ClearMessages removes all captured sandbox messages
+// @Summary Clear sandbox messages
+// @Description Remove all messages captured by the sandbox provider
+// @Tags sandbox
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/sandbox/messages [delete]
+func (h *SandboxHandler) ClearMessages(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.Clear(r.Context()); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Sandbox messages cleared",
+	})
+}
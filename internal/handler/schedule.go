@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// ScheduleHandler handles recurring schedule HTTP requests
+type ScheduleHandler struct {
+	service  *service.ScheduleService
+	validate *validator.Validate
+}
+
+// NewScheduleHandler creates a new ScheduleHandler
+func NewScheduleHandler(service *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers schedule routes
+func (h *ScheduleHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	r.Post("/{id}/pause", h.Pause)
+	r.Post("/{id}/resume", h.Resume)
+}
+
+// CreateScheduleRequest represents a request to create a recurring schedule
+type CreateScheduleRequest struct {
+	Name         string            `json:"name" validate:"required,max=255" example:"weekly_digest"`
+	Recipient    string            `json:"recipient" validate:"required" example:"user@example.com"`
+	Channel      domain.Channel    `json:"channel" validate:"required,oneof=sms email push" example:"email"`
+	TemplateName string            `json:"template_name" validate:"required" example:"weekly_summary"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority" validate:"omitempty,oneof=high normal low" example:"normal"`
+	Category     string            `json:"category,omitempty" example:"marketing"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	// Exactly one of Cron and RRule is required
+	Cron     *string `json:"cron,omitempty" example:"0 9 * * MON"`
+	RRule    *string `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO;BYHOUR=9;BYMINUTE=0;BYSECOND=0"`
+	TimeZone string  `json:"timezone,omitempty" example:"Europe/Istanbul"`
+	// StartsAt defaults to now
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	MaxOccurrences *int       `json:"max_occurrences,omitempty" validate:"omitempty,min=1" example:"10"`
+}
+
+// Create creates a new recurring schedule
+// @Summary Create schedule
+// @Description Create a schedule that sends a template on a cron or RRULE recurrence
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param schedule body CreateScheduleRequest true "Schedule request"
+// @Success 201 {object} Response{data=domain.Schedule}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules [post]
+func (h *ScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateScheduleRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	schedule, err := h.service.Create(r.Context(), service.CreateScheduleRequest{
+		Name:           req.Name,
+		Recipient:      req.Recipient,
+		Channel:        req.Channel,
+		TemplateName:   req.TemplateName,
+		TemplateVars:   req.TemplateVars,
+		Priority:       req.Priority,
+		Category:       req.Category,
+		Metadata:       req.Metadata,
+		Cron:           req.Cron,
+		RRule:          req.RRule,
+		TimeZone:       req.TimeZone,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		MaxOccurrences: req.MaxOccurrences,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, schedule)
+}
+
+// List retrieves schedules with filtering
+// @Summary List schedules
+// @Description List recurring schedules with optional filters and pagination
+// @Tags schedules
+// @Produce json
+// @Param status query string false "Filter by status" Enums(active, paused, completed)
+// @Param channel query string false "Filter by channel" Enums(sms, email, push)
+// @Param recipient query string false "Filter by recipient"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.ScheduleListResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules [get]
+func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := domain.ScheduleFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := domain.ScheduleStatus(status)
+		if !s.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_STATUS", "Invalid schedule status", nil)
+			return
+		}
+		filter.Status = &s
+	}
+
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		c := domain.Channel(channel)
+		if !c.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+			return
+		}
+		filter.Channel = &c
+	}
+
+	if recipient := r.URL.Query().Get("recipient"); recipient != "" {
+		filter.Recipient = &recipient
+	}
+
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE", "Invalid page number", nil)
+			return
+		}
+		filter.Page = page
+	}
+
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", "Page size must be between 1 and 100", nil)
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	result, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetByID retrieves a schedule by ID
+// @Summary Get schedule by ID
+// @Description Get a recurring schedule by its ID
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} Response{data=domain.Schedule}
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules/{id} [get]
+func (h *ScheduleHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, schedule)
+}
+
+// UpdateScheduleRequest represents a request to update a recurring schedule.
+// Setting cron clears rrule and vice versa.
+type UpdateScheduleRequest struct {
+	Name           *string           `json:"name,omitempty" validate:"omitempty,max=255"`
+	Recipient      *string           `json:"recipient,omitempty"`
+	Channel        *domain.Channel   `json:"channel,omitempty" validate:"omitempty,oneof=sms email push"`
+	TemplateName   *string           `json:"template_name,omitempty"`
+	TemplateVars   map[string]string `json:"template_vars,omitempty"`
+	Priority       *domain.Priority  `json:"priority,omitempty" validate:"omitempty,oneof=high normal low"`
+	Category       *string           `json:"category,omitempty"`
+	Metadata       map[string]any    `json:"metadata,omitempty"`
+	Cron           *string           `json:"cron,omitempty"`
+	RRule          *string           `json:"rrule,omitempty"`
+	TimeZone       *string           `json:"timezone,omitempty"`
+	StartsAt       *time.Time        `json:"starts_at,omitempty"`
+	EndsAt         *time.Time        `json:"ends_at,omitempty"`
+	MaxOccurrences *int              `json:"max_occurrences,omitempty" validate:"omitempty,min=1"`
+}
+
+// Update updates a schedule
+// @Summary Update schedule
+// @Description Update a recurring schedule; a new recurrence takes effect from now
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param schedule body UpdateScheduleRequest true "Update request"
+// @Success 200 {object} Response{data=domain.Schedule}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules/{id} [put]
+func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateScheduleRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	if req.Cron != nil && req.RRule != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Set either cron or rrule, not both", nil)
+		return
+	}
+
+	schedule, err := h.service.Update(r.Context(), id, service.UpdateScheduleRequest{
+		Name:           req.Name,
+		Recipient:      req.Recipient,
+		Channel:        req.Channel,
+		TemplateName:   req.TemplateName,
+		TemplateVars:   req.TemplateVars,
+		Priority:       req.Priority,
+		Category:       req.Category,
+		Metadata:       req.Metadata,
+		Cron:           req.Cron,
+		RRule:          req.RRule,
+		TimeZone:       req.TimeZone,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		MaxOccurrences: req.MaxOccurrences,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, schedule)
+}
+
+// Delete deletes a schedule
+// @Summary Delete schedule
+// @Description Delete a recurring schedule; notifications it already created are kept
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules/{id} [delete]
+func (h *ScheduleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Schedule deleted successfully",
+	})
+}
+
+// Pause pauses a schedule
+// @Summary Pause schedule
+// @Description Stop an active schedule from creating notifications
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} Response{data=domain.Schedule}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules/{id}/pause [post]
+func (h *ScheduleHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.Pause(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, schedule)
+}
+
+// Resume resumes a paused schedule
+// @Summary Resume schedule
+// @Description Reactivate a paused schedule; occurrences missed while paused are skipped
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} Response{data=domain.Schedule}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/schedules/{id}/resume [post]
+func (h *ScheduleHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.Resume(r.Context(), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, schedule)
+}
+
+func parseScheduleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid schedule ID", nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
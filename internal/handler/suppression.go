@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// SuppressionHandler handles suppression list HTTP requests
+type SuppressionHandler struct {
+	service  *service.SuppressionService
+	validate *validator.Validate
+}
+
+// NewSuppressionHandler creates a new SuppressionHandler
+func NewSuppressionHandler(service *service.SuppressionService) *SuppressionHandler {
+	return &SuppressionHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers suppression routes
+func (h *SuppressionHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Post("/import", h.Import)
+	r.Get("/", h.List)
+	r.Get("/{channel}/{recipient}", h.Get)
+	r.Delete("/{channel}/{recipient}", h.Delete)
+}
+
+// RegisterAdminRoutes registers suppression admin routes
+func (h *SuppressionHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Post("/rekey", h.Rekey)
+}
+
+// Rekey moves SMS suppressions to the E.164 numbers they are looked up by
+// @Summary Rekey SMS suppressions
+// @Description Move SMS suppressions stored before numbers were normalized to their E.164 form, merging each with any suppression already stored under that number. Run once after upgrading or changing PHONE_DEFAULT_REGION.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} Response{data=service.RekeyResult}
+// @Failure 500 {object} Response
+// @Router /api/v1/admin/suppressions/rekey [post]
+func (h *SuppressionHandler) Rekey(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.RekeyPhones(r.Context())
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// CreateSuppressionRequest represents a request to suppress a recipient
+type CreateSuppressionRequest struct {
+	Recipient string                   `json:"recipient" validate:"required,max=255" example:"user@example.com"`
+	Channel   domain.Channel           `json:"channel" validate:"required,oneof=sms email push" example:"email"`
+	Reason    domain.SuppressionReason `json:"reason" validate:"required,oneof=bounce complaint unsubscribe manual" example:"bounce"`
+	Note      *string                  `json:"note,omitempty" example:"550 mailbox unavailable"`
+	ExpiresAt *time.Time               `json:"expires_at,omitempty"`
+}
+
+// ImportSuppressionsRequest represents a bulk import of suppressions
+type ImportSuppressionsRequest struct {
+	Suppressions []CreateSuppressionRequest `json:"suppressions" validate:"required,min=1,max=10000"`
+}
+
+// Create adds a recipient to the suppression list
+// @Summary Suppress recipient
+// @Description Add a recipient to the suppression list for a channel, replacing any existing entry
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param suppression body CreateSuppressionRequest true "Suppression request"
+// @Success 201 {object} Response{data=domain.Suppression}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/suppressions [post]
+func (h *SuppressionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateSuppressionRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	suppression, err := h.service.Suppress(r.Context(), toSuppressRequest(req))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, suppression)
+}
+
+// Import adds many recipients to the suppression list
+// @Summary Import suppressions
+// @Description Add up to 10000 suppressions at once. Nothing is imported if any entry is invalid.
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param suppressions body ImportSuppressionsRequest true "Import request"
+// @Success 200 {object} Response{data=service.ImportResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/suppressions/import [post]
+func (h *SuppressionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req ImportSuppressionsRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	importReq := service.ImportSuppressionsRequest{
+		Suppressions: make([]service.SuppressRequest, 0, len(req.Suppressions)),
+	}
+	for _, item := range req.Suppressions {
+		importReq.Suppressions = append(importReq.Suppressions, toSuppressRequest(item))
+	}
+
+	result, err := h.service.Import(r.Context(), importReq)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// List lists suppressions
+// @Summary List suppressions
+// @Description List suppressions with optional filters and pagination
+// @Tags suppressions
+// @Produce json
+// @Param channel query string false "Filter by channel"
+// @Param reason query string false "Filter by reason"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.SuppressionListResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/suppressions [get]
+func (h *SuppressionHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := domain.SuppressionFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		c := domain.Channel(channel)
+		if !c.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+			return
+		}
+		filter.Channel = &c
+	}
+
+	if reason := r.URL.Query().Get("reason"); reason != "" {
+		rs := domain.SuppressionReason(reason)
+		if !rs.IsValid() {
+			JSONError(w, http.StatusBadRequest, "INVALID_REASON", "Invalid suppression reason", nil)
+			return
+		}
+		filter.Reason = &rs
+	}
+
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE", "Invalid page number", nil)
+			return
+		}
+		filter.Page = page
+	}
+
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", "Page size must be between 1 and 100", nil)
+			return
+		}
+		filter.PageSize = pageSize
+	}
+
+	result, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// Get retrieves the suppression for a recipient
+// @Summary Get suppression
+// @Description Get the suppression for a recipient on a channel
+// @Tags suppressions
+// @Produce json
+// @Param channel path string true "Channel"
+// @Param recipient path string true "Recipient"
+// @Success 200 {object} Response{data=domain.Suppression}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/suppressions/{channel}/{recipient} [get]
+func (h *SuppressionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	channel := domain.Channel(chi.URLParam(r, "channel"))
+	if !channel.IsValid() {
+		JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+		return
+	}
+
+	suppression, err := h.service.Get(r.Context(), channel, chi.URLParam(r, "recipient"))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, suppression)
+}
+
+// Delete removes a recipient from the suppression list
+// @Summary Delete suppression
+// @Description Remove a recipient from the suppression list for a channel
+// @Tags suppressions
+// @Param channel path string true "Channel"
+// @Param recipient path string true "Recipient"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/suppressions/{channel}/{recipient} [delete]
+func (h *SuppressionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	channel := domain.Channel(chi.URLParam(r, "channel"))
+	if !channel.IsValid() {
+		JSONError(w, http.StatusBadRequest, "INVALID_CHANNEL", "Invalid channel", nil)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), channel, chi.URLParam(r, "recipient")); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Suppression deleted successfully",
+	})
+}
+
+func toSuppressRequest(req CreateSuppressionRequest) service.SuppressRequest {
+	return service.SuppressRequest{
+		Recipient: req.Recipient,
+		Channel:   req.Channel,
+		Reason:    req.Reason,
+		Note:      req.Note,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
@@ -41,6 +41,8 @@ type CreateTemplateRequest struct {
 	Name    string         `json:"name" validate:"required,min=1,max=100" example:"welcome_sms"`
 	Channel domain.Channel `json:"channel" validate:"required,oneof=sms email push" example:"sms"`
 	Content string         `json:"content" validate:"required" example:"Hello {{name}}, welcome to our service!"`
+	// TrackingDisabled opts emails rendered from the template out of open and click tracking
+	TrackingDisabled bool `json:"tracking_disabled" example:"false"`
 }
 
 // Create creates a new template
@@ -68,9 +70,10 @@ func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	template, err := h.service.Create(r.Context(), service.CreateTemplateRequest{
-		Name:    req.Name,
-		Channel: req.Channel,
-		Content: req.Content,
+		Name:             req.Name,
+		Channel:          req.Channel,
+		Content:          req.Content,
+		TrackingDisabled: req.TrackingDisabled,
 	})
 	if err != nil {
 		HandleError(w, err)
@@ -152,6 +155,8 @@ type UpdateTemplateRequest struct {
 	Name    *string         `json:"name,omitempty"`
 	Channel *domain.Channel `json:"channel,omitempty"`
 	Content *string         `json:"content,omitempty"`
+	// TrackingDisabled opts emails rendered from the template out of open and click tracking
+	TrackingDisabled *bool `json:"tracking_disabled,omitempty"`
 }
 
 // Update updates a template
@@ -182,9 +187,10 @@ func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	template, err := h.service.Update(r.Context(), id, service.UpdateTemplateRequest{
-		Name:    req.Name,
-		Channel: req.Channel,
-		Content: req.Content,
+		Name:             req.Name,
+		Channel:          req.Channel,
+		Content:          req.Content,
+		TrackingDisabled: req.TrackingDisabled,
 	})
 	if err != nil {
 		HandleError(w, err)
@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// TopicHandler handles topic, subscription and publish HTTP requests
+type TopicHandler struct {
+	service  *service.TopicService
+	validate *validator.Validate
+}
+
+// NewTopicHandler creates a new TopicHandler
+func NewTopicHandler(service *service.TopicService) *TopicHandler {
+	return &TopicHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// RegisterRoutes registers topic routes
+func (h *TopicHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{topic}", h.Get)
+	r.Delete("/{topic}", h.Delete)
+	r.Get("/{topic}/subscriptions", h.ListSubscriptions)
+	r.Put("/{topic}/subscriptions/{userId}", h.Subscribe)
+	r.Delete("/{topic}/subscriptions/{userId}", h.Unsubscribe)
+	r.Post("/{topic}/publish", h.Publish)
+	r.Get("/{topic}/publications/{id}", h.GetPublication)
+}
+
+// CreateTopicRequest represents a request to create a topic
+type CreateTopicRequest struct {
+	Name        string `json:"name" validate:"required,max=100" example:"price-drop.product-123"`
+	Description string `json:"description,omitempty" example:"Price drops on product 123"`
+}
+
+// SubscribeRequest represents the channels a recipient subscribes to a topic on
+type SubscribeRequest struct {
+	Channels []domain.Channel `json:"channels" validate:"required,min=1,max=3,dive,oneof=sms email push"`
+}
+
+// PublishRequest represents a message published to a topic
+type PublishRequest struct {
+	Content      string            `json:"content,omitempty" example:"Product 123 is now 20% off"`
+	TemplateName *string           `json:"template_name,omitempty" example:"price_drop"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority" validate:"omitempty,oneof=high normal low" example:"normal"`
+	Category     string            `json:"category,omitempty" validate:"omitempty,max=50" example:"marketing"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	ExpiresAt    *time.Time        `json:"expires_at,omitempty"`
+	TTL          *int              `json:"ttl,omitempty" validate:"omitempty,min=1" example:"86400"`
+}
+
+// Create creates a topic
+// @Summary Create topic
+// @Description Create a topic recipients can subscribe to
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Param topic body CreateTopicRequest true "Topic request"
+// @Success 201 {object} Response{data=domain.Topic}
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics [post]
+func (h *TopicHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateTopicRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	topic, err := h.service.CreateTopic(r.Context(), service.CreateTopicRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, topic)
+}
+
+// List lists topics
+// @Summary List topics
+// @Description List topics with pagination
+// @Tags topics
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.TopicListResult}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics [get]
+func (h *TopicHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ListTopics(r.Context(), domain.TopicFilter{Page: page, PageSize: pageSize})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// Get retrieves a topic
+// @Summary Get topic
+// @Description Get a topic by name
+// @Tags topics
+// @Produce json
+// @Param topic path string true "Topic name"
+// @Success 200 {object} Response{data=domain.Topic}
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic} [get]
+func (h *TopicHandler) Get(w http.ResponseWriter, r *http.Request) {
+	topic, err := h.service.GetTopic(r.Context(), chi.URLParam(r, "topic"))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, topic)
+}
+
+// Delete deletes a topic
+// @Summary Delete topic
+// @Description Delete a topic with its subscriptions and publications
+// @Tags topics
+// @Param topic path string true "Topic name"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic} [delete]
+func (h *TopicHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteTopic(r.Context(), chi.URLParam(r, "topic")); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Topic deleted successfully",
+	})
+}
+
+// ListSubscriptions lists the subscriptions to a topic
+// @Summary List subscriptions
+// @Description List the recipients subscribed to a topic with pagination
+// @Tags topics
+// @Produce json
+// @Param topic path string true "Topic name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} Response{data=domain.SubscriptionListResult}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic}/subscriptions [get]
+func (h *TopicHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ListSubscriptions(r.Context(), chi.URLParam(r, "topic"), domain.SubscriptionFilter{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// Subscribe subscribes a recipient to a topic
+// @Summary Subscribe recipient
+// @Description Subscribe a recipient profile to a topic on the given channels, replacing the channels of an existing subscription
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Param topic path string true "Topic name"
+// @Param userId path string true "User ID"
+// @Param subscription body SubscribeRequest true "Subscription request"
+// @Success 200 {object} Response{data=domain.Subscription}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic}/subscriptions/{userId} [put]
+func (h *TopicHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req SubscribeRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	subscription, err := h.service.Subscribe(r.Context(), chi.URLParam(r, "topic"), chi.URLParam(r, "userId"), req.Channels)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, subscription)
+}
+
+// Unsubscribe unsubscribes a recipient from a topic
+// @Summary Unsubscribe recipient
+// @Description Remove a recipient's subscription to a topic
+// @Tags topics
+// @Param topic path string true "Topic name"
+// @Param userId path string true "User ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic}/subscriptions/{userId} [delete]
+func (h *TopicHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Unsubscribe(r.Context(), chi.URLParam(r, "topic"), chi.URLParam(r, "userId")); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{
+		"message": "Unsubscribed successfully",
+	})
+}
+
+// Publish publishes a message to a topic
+// @Summary Publish to topic
+// @Description Send a message to every subscriber of a topic. The fan-out runs in the background in chunks; the returned publication reports its progress and its ID is the batch ID of the notifications.
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Param topic path string true "Topic name"
+// @Param publication body PublishRequest true "Publish request"
+// @Success 202 {object} Response{data=domain.Publication}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic}/publish [post]
+func (h *TopicHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	var req PublishRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		JSONError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+		return
+	}
+
+	publication, err := h.service.Publish(r.Context(), chi.URLParam(r, "topic"), service.PublishRequest{
+		Content:      req.Content,
+		TemplateName: req.TemplateName,
+		TemplateVars: req.TemplateVars,
+		Priority:     req.Priority,
+		Category:     req.Category,
+		Metadata:     req.Metadata,
+		ExpiresAt:    req.ExpiresAt,
+		TTL:          req.TTL,
+	})
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, publication)
+}
+
+// GetPublication retrieves a publication
+// @Summary Get publication
+// @Description Get the progress of a message published to a topic
+// @Tags topics
+// @Produce json
+// @Param topic path string true "Topic name"
+// @Param id path string true "Publication ID"
+// @Success 200 {object} Response{data=domain.Publication}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/topics/{topic}/publications/{id} [get]
+func (h *TopicHandler) GetPublication(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid publication ID", nil)
+		return
+	}
+
+	publication, err := h.service.GetPublication(r.Context(), chi.URLParam(r, "topic"), id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, publication)
+}
+
+func parsePage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	page, pageSize := 1, 20
+
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		p, err := strconv.Atoi(pageStr)
+		if err != nil || p < 1 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE", "Invalid page number", nil)
+			return 0, 0, false
+		}
+		page = p
+	}
+
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		ps, err := strconv.Atoi(pageSizeStr)
+		if err != nil || ps < 1 || ps > 100 {
+			JSONError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", "Page size must be between 1 and 100", nil)
+			return 0, 0, false
+		}
+		pageSize = ps
+	}
+
+	return page, pageSize, true
+}
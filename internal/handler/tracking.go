@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/service"
+)
+
+// trackingPixel is a transparent 1x1 GIF
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// TrackingHandler handles email open and click tracking requests
+type TrackingHandler struct {
+	service *service.TrackingService
+}
+
+// NewTrackingHandler creates a new TrackingHandler
+func NewTrackingHandler(service *service.TrackingService) *TrackingHandler {
+	return &TrackingHandler{service: service}
+}
+
+// RegisterRoutes registers the public tracking routes
+func (h *TrackingHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/open/{id}", h.Open)
+	r.Get("/click/{id}", h.Click)
+}
+
+// RegisterNotificationRoutes registers engagement routes under /notifications
+func (h *TrackingHandler) RegisterNotificationRoutes(r chi.Router) {
+	r.Get("/batch/{batchId}/engagement", h.BatchEngagement)
+}
+
+// Open records an email open and serves the tracking pixel
+// @Summary Track email open
+// @Description Record an open of an email notification. Always responds with a 1x1 GIF; requests with an invalid signature are not recorded.
+// @Tags tracking
+// @Produce image/gif
+// @Param id path string true "Notification ID"
+// @Param s query string true "Link signature"
+// @Success 200 {file} binary
+// @Router /api/v1/track/open/{id} [get]
+func (h *TrackingHandler) Open(w http.ResponseWriter, r *http.Request) {
+	if id, err := uuid.Parse(chi.URLParam(r, "id")); err == nil {
+		h.service.RecordOpen(r.Context(), id, r.URL.Query().Get("s"), r.UserAgent(), clientIP(r))
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(trackingPixel)
+}
+
+// Click records a link click and redirects to the original URL
+// @Summary Track email click
+// @Description Record a click of a link in an email notification and redirect to the original URL. Only links signed by this service are redirected.
+// @Tags tracking
+// @Param id path string true "Notification ID"
+// @Param u query string true "Original URL"
+// @Param s query string true "Link signature"
+// @Success 302
+// @Failure 400 {object} Response
+// @Router /api/v1/track/click/{id} [get]
+func (h *TrackingHandler) Click(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid notification ID", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	target, err := h.service.RecordClick(r.Context(), id, query.Get("u"), query.Get("s"), r.UserAgent(), clientIP(r))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_LINK", "Invalid or tampered tracking link", nil)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// BatchEngagement returns the aggregated opens and clicks of a batch
+// @Summary Get batch engagement
+// @Description Get the number of emails in a batch that were opened and clicked, and the total opens and clicks
+// @Tags tracking
+// @Produce json
+// @Param batchId path string true "Batch ID"
+// @Success 200 {object} Response{data=domain.BatchEngagement}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/v1/notifications/batch/{batchId}/engagement [get]
+func (h *TrackingHandler) BatchEngagement(w http.ResponseWriter, r *http.Request) {
+	batchID, err := uuid.Parse(chi.URLParam(r, "batchId"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "INVALID_ID", "Invalid batch ID", nil)
+		return
+	}
+
+	engagement, err := h.service.BatchEngagement(r.Context(), batchID)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, engagement)
+}
+
+// clientIP returns the originating client address, preferring the first
+// X-Forwarded-For entry set by a reverse proxy
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -65,7 +65,9 @@ type ClientFilter struct {
 
 // StatusUpdate represents a notification status update
 type StatusUpdate struct {
-	Type         string               `json:"type"`
+	Type string `json:"type"`
+	// EventID is the ID of the status event in the notification's history
+	EventID      *uuid.UUID           `json:"event_id,omitempty"`
 	Notification *domain.Notification `json:"notification"`
 	Timestamp    time.Time            `json:"timestamp"`
 }
@@ -135,6 +137,9 @@ func (h *WebSocketHub) BroadcastStatus(notification *domain.Notification) {
 		Notification: notification,
 		Timestamp:    time.Now().UTC(),
 	}
+	if event := notification.LastEvent(); event != nil {
+		update.EventID = &event.ID
+	}
 
 	select {
 	case h.broadcast <- update:
@@ -5,11 +5,9 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
-)
-
-type contextKey string
 
-const correlationIDKey contextKey = "correlation_id"
+	"github.com/insider-one/notification-service/internal/domain"
+)
 
 // CorrelationIDHeader is the HTTP header for correlation ID
 const CorrelationIDHeader = "X-Correlation-ID"
@@ -26,15 +24,22 @@ func Correlation(next http.Handler) http.Handler {
 		w.Header().Set(CorrelationIDHeader, correlationID)
 
 		// Add to context
-		ctx := context.WithValue(r.Context(), correlationIDKey, correlationID)
+		ctx := domain.WithCorrelationID(r.Context(), correlationID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
 // GetCorrelationID retrieves the correlation ID from context
 func GetCorrelationID(ctx context.Context) string {
-	if id, ok := ctx.Value(correlationIDKey).(string); ok {
-		return id
+	return domain.CorrelationIDFrom(ctx)
+}
+
+// Actor returns a middleware that attributes status changes made while
+// handling requests to actor
+func Actor(actor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(domain.WithActor(r.Context(), actor)))
+		})
 	}
-	return ""
 }
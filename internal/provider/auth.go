@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/config"
+)
+
+const (
+	authBearer = "bearer"
+	authBasic  = "basic"
+	authAPIKey = "api_key"
+	authHMAC   = "hmac"
+	authOAuth2 = "oauth2"
+
+	defaultAPIKeyHeader        = "X-API-Key"
+	defaultSignatureHeader     = "X-Signature"
+	defaultTimestampHeader     = "X-Timestamp"
+	oauth2RefreshMargin        = 30 * time.Second
+	oauth2DefaultTokenLifetime = time.Hour
+)
+
+// Authenticator adds credentials to an outbound provider request.
+// body is the exact payload that will be sent, for schemes that sign it.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request, body []byte) error
+}
+
+// tokenInvalidator is implemented by authenticators that cache credentials
+// which the vendor may reject before they expire
+type tokenInvalidator interface {
+	Invalidate()
+}
+
+// NewAuthenticator creates the Authenticator described by cfg. It returns
+// nil when cfg.Type is empty.
+func NewAuthenticator(cfg config.AuthConfig, client *http.Client) (Authenticator, error) {
+	if cfg.Type == "" {
+		return nil, nil
+	}
+
+	secret, err := cfg.ResolveSecret()
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("%s auth: secret is required", cfg.Type)
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case authBearer:
+		return &bearerAuth{token: secret}, nil
+	case authBasic:
+		return &basicAuth{username: cfg.Username, password: secret}, nil
+	case authAPIKey:
+		return &apiKeyAuth{header: headerOrDefault(cfg.Header, defaultAPIKeyHeader), key: secret}, nil
+	case authHMAC:
+		return &hmacAuth{
+			header:          headerOrDefault(cfg.Header, defaultSignatureHeader),
+			timestampHeader: headerOrDefault(cfg.TimestampHeader, defaultTimestampHeader),
+			key:             []byte(secret),
+			now:             time.Now,
+		}, nil
+	case authOAuth2:
+		if cfg.TokenURL == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("oauth2 auth: token_url and client_id are required")
+		}
+		return &oauth2Auth{
+			client:       client,
+			tokenURL:     cfg.TokenURL,
+			clientID:     cfg.ClientID,
+			clientSecret: secret,
+			scopes:       cfg.Scopes,
+			now:          time.Now,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported auth type %q", cfg.Type)
+}
+
+// bearerAuth sends a static bearer token
+type bearerAuth struct {
+	token string
+}
+
+func (a *bearerAuth) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// basicAuth sends HTTP basic credentials
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (a *basicAuth) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+// apiKeyAuth sends a static key in a header
+type apiKeyAuth struct {
+	header string
+	key    string
+}
+
+func (a *apiKeyAuth) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set(a.header, a.key)
+	return nil
+}
+
+// hmacAuth signs "<unix timestamp>.<body>" with HMAC-SHA256 and sends the
+// hex-encoded signature alongside the timestamp
+type hmacAuth struct {
+	header          string
+	timestampHeader string
+	key             []byte
+	now             func() time.Time
+}
+
+func (a *hmacAuth) Authenticate(_ context.Context, req *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(a.now().Unix(), 10)
+	req.Header.Set(a.timestampHeader, timestamp)
+	req.Header.Set(a.header, SignHMAC(a.key, timestamp, body))
+	return nil
+}
+
+// SignHMAC returns the hex HMAC-SHA256 of "<timestamp>.<body>" under key
+func SignHMAC(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// oauth2Auth fetches access tokens with the client credentials grant and
+// caches them until shortly before they expire
+type oauth2Auth struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	now          func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (a *oauth2Auth) Authenticate(ctx context.Context, req *http.Request, _ []byte) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one
+func (a *oauth2Auth) Invalidate() {
+	a.mu.Lock()
+	a.token = ""
+	a.mu.Unlock()
+}
+
+func (a *oauth2Auth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && a.now().Before(a.expiresAt.Add(-oauth2RefreshMargin)) {
+		return a.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(a.clientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token response missing access_token")
+	}
+
+	lifetime := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = oauth2DefaultTokenLifetime
+	}
+
+	a.token = tokenResp.AccessToken
+	a.expiresAt = a.now().Add(lifetime)
+
+	return a.token, nil
+}
+
+// authenticate applies auth to req. Failures are returned as plain errors,
+// which the processor retries since they are usually transient (e.g. the
+// token endpoint being briefly unavailable).
+func authenticate(ctx context.Context, auth Authenticator, req *http.Request, body []byte) error {
+	if auth == nil {
+		return nil
+	}
+	if err := auth.Authenticate(ctx, req, body); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	return nil
+}
+
+// invalidateOnUnauthorized drops cached credentials after a 401 so the
+// retry fetches fresh ones. It reports whether credentials were dropped, in
+// which case the failure should be treated as retryable.
+func invalidateOnUnauthorized(auth Authenticator, status int) bool {
+	if status != http.StatusUnauthorized {
+		return false
+	}
+	inv, ok := auth.(tokenInvalidator)
+	if !ok {
+		return false
+	}
+	inv.Invalidate()
+	return true
+}
+
+func headerOrDefault(header, fallback string) string {
+	if header == "" {
+		return fallback
+	}
+	return header
+}
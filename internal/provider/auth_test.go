@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+)
+
+func TestNewAuthenticator_HMAC(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cret\n"), 0o600))
+
+	auth, err := NewAuthenticator(config.AuthConfig{Type: "hmac", SecretFile: secretFile}, http.DefaultClient)
+	require.NoError(t, err)
+	auth.(*hmacAuth).now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	body := []byte(`{"to":"+905551234567"}`)
+	require.NoError(t, auth.Authenticate(context.Background(), req, body))
+
+	assert.Equal(t, "1700000000", req.Header.Get(defaultTimestampHeader))
+	assert.Equal(t, SignHMAC([]byte("s3cret"), "1700000000", body), req.Header.Get(defaultSignatureHeader))
+}
+
+func TestNewAuthenticator_OAuth2(t *testing.T) {
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", user)
+		assert.Equal(t, "secret", pass)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	auth, err := NewAuthenticator(config.AuthConfig{
+		Type:     "oauth2",
+		TokenURL: server.URL,
+		ClientID: "client",
+		Secret:   "secret",
+	}, server.Client())
+	require.NoError(t, err)
+
+	now := time.Now()
+	oauth := auth.(*oauth2Auth)
+	oauth.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, auth.Authenticate(context.Background(), req, nil))
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	}
+	assert.Equal(t, 1, tokenRequests, "token should be cached")
+
+	// Refresh shortly before expiry
+	now = now.Add(time.Hour - 10*time.Second)
+	require.NoError(t, auth.Authenticate(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), nil))
+	assert.Equal(t, 2, tokenRequests)
+
+	// A 401 from the vendor drops the cached token
+	assert.True(t, invalidateOnUnauthorized(auth, http.StatusUnauthorized))
+	require.NoError(t, auth.Authenticate(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), nil))
+	assert.Equal(t, 3, tokenRequests)
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	_, err := NewAuthenticator(config.AuthConfig{Type: "bearer"}, http.DefaultClient)
+	assert.Error(t, err)
+
+	_, err = NewAuthenticator(config.AuthConfig{Type: "digest", Secret: "x"}, http.DefaultClient)
+	assert.Error(t, err)
+
+	auth, err := NewAuthenticator(config.AuthConfig{}, http.DefaultClient)
+	assert.NoError(t, err)
+	assert.Nil(t, auth)
+}
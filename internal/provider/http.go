@@ -0,0 +1,441 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const defaultHTTPProviderTimeout = 10 * time.Second
+
+// maxErrorBodyBytes bounds the vendor response body kept in a ProviderError,
+// which ends up in the notification's error message
+const maxErrorBodyBytes = 1024
+
+// HTTPProvider implements domain.NotificationProvider for arbitrary HTTP
+// vendors described entirely by configuration
+type HTTPProvider struct {
+	name            string
+	client          *http.Client
+	auth            Authenticator
+	method          string
+	url             *template.Template
+	headers         map[string]*template.Template
+	body            *template.Template
+	successStatuses map[int]bool
+	response        config.HTTPResponseMapping
+	errorRules      []config.HTTPErrorRule
+	status          *httpStatusRequest
+}
+
+// httpStatusRequest is the rendered form of config.HTTPStatusConfig
+type httpStatusRequest struct {
+	method    string
+	url       *template.Template
+	headers   map[string]*template.Template
+	body      *template.Template
+	batchSize int
+	mapping   *receiptMapping
+}
+
+// templateData is the value exposed to request templates
+type templateData struct {
+	ID       string
+	To       string
+	Channel  string
+	Content  string
+	Priority string
+	Metadata map[string]any
+	// TTL is the remaining time to live in seconds, zero when unset
+	TTL int
+	// IDs holds the message IDs of a status request
+	IDs []string
+}
+
+var templateFuncs = template.FuncMap{
+	// json encodes a value so it can be embedded safely in a JSON body
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// join concatenates values with sep, e.g. to put message IDs in a query string
+	"join": func(sep string, values []string) string {
+		return strings.Join(values, sep)
+	},
+	// default returns fallback when value is empty
+	"default": func(fallback, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// NewHTTPProvider creates a new HTTPProvider from configuration
+func NewHTTPProvider(cfg config.HTTPProviderConfig) (*HTTPProvider, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("http provider: name is required")
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("http provider %s: url is required", cfg.Name)
+	}
+
+	method := strings.ToUpper(cfg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = defaultHTTPProviderTimeout
+	}
+
+	client := &http.Client{Timeout: timeout}
+	auth, err := NewAuthenticator(cfg.Auth, client)
+	if err != nil {
+		return nil, fmt.Errorf("http provider %s: %w", cfg.Name, err)
+	}
+
+	p := &HTTPProvider{
+		name:            cfg.Name,
+		client:          client,
+		auth:            auth,
+		method:          method,
+		headers:         make(map[string]*template.Template, len(cfg.Headers)),
+		successStatuses: make(map[int]bool, len(cfg.SuccessStatuses)),
+		response:        cfg.Response,
+		errorRules:      cfg.ErrorRules,
+	}
+
+	if p.url, err = parseTemplate(cfg.Name+".url", cfg.URL); err != nil {
+		return nil, err
+	}
+	if p.body, err = parseTemplate(cfg.Name+".body", cfg.Body); err != nil {
+		return nil, err
+	}
+	for key, value := range cfg.Headers {
+		if p.headers[key], err = parseTemplate(cfg.Name+".header."+key, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, status := range cfg.SuccessStatuses {
+		p.successStatuses[status] = true
+	}
+
+	if cfg.Status != nil {
+		if p.status, err = newHTTPStatusRequest(cfg.Name, *cfg.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Name returns the configured provider name
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+// Send renders the configured request and sends it to the vendor
+func (p *HTTPProvider) Send(ctx context.Context, req *domain.ProviderRequest) (*domain.ProviderResponse, error) {
+	data := templateData{
+		ID:       req.NotificationID.String(),
+		To:       req.To,
+		Channel:  req.Channel,
+		Content:  req.Content,
+		Priority: string(req.Priority),
+		Metadata: req.Metadata,
+		TTL:      req.TTL,
+	}
+
+	url, err := p.render(p.url, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.render(p.body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, p.method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("failed to create request: %v", err), false)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	for key, tmpl := range p.headers {
+		value, err := p.render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set(key, value)
+	}
+
+	if err := authenticate(ctx, p.auth, httpReq, []byte(body)); err != nil {
+		return nil, p.providerError(0, err.Error(), true)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("request failed: %v", err), true)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("failed to read response body: %v", err), true)
+	}
+
+	if !p.isSuccess(resp.StatusCode) {
+		retryable := invalidateOnUnauthorized(p.auth, resp.StatusCode) || p.isRetryable(resp.StatusCode, respBody)
+		return nil, p.providerError(resp.StatusCode, errorBody(respBody), retryable)
+	}
+
+	providerResp := p.parseResponse(respBody)
+	providerResp.StatusCode = resp.StatusCode
+	providerResp.Body = string(respBody)
+
+	return providerResp, nil
+}
+
+// StatusBatchSize returns how many message IDs one status request can carry,
+// or zero when status polling is not configured
+func (p *HTTPProvider) StatusBatchSize() int {
+	if p.status == nil {
+		return 0
+	}
+	return p.status.batchSize
+}
+
+// CheckStatus queries the vendor status API for a batch of message IDs
+func (p *HTTPProvider) CheckStatus(ctx context.Context, externalIDs []string) ([]*domain.DeliveryReceipt, error) {
+	if p.status == nil {
+		return nil, fmt.Errorf("http provider %s: status polling is not configured", p.name)
+	}
+
+	data := templateData{IDs: externalIDs}
+	if len(externalIDs) > 0 {
+		data.ID = externalIDs[0]
+	}
+
+	url, err := p.render(p.status.url, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.render(p.status.body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, p.status.method, url, reqBody)
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("failed to create request: %v", err), false)
+	}
+
+	if body != "" {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	for key, tmpl := range p.status.headers {
+		value, err := p.render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set(key, value)
+	}
+
+	if err := authenticate(ctx, p.auth, httpReq, []byte(body)); err != nil {
+		return nil, p.providerError(0, err.Error(), true)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("request failed: %v", err), true)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, p.providerError(0, fmt.Sprintf("failed to read response body: %v", err), true)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := invalidateOnUnauthorized(p.auth, resp.StatusCode) ||
+			resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, p.providerError(resp.StatusCode, errorBody(respBody), retryable)
+	}
+
+	decoded, err := decodeJSON(respBody)
+	if err != nil {
+		return nil, err
+	}
+	return p.status.mapping.parse(decoded)
+}
+
+// isSuccess reports whether status is a successful response
+func (p *HTTPProvider) isSuccess(status int) bool {
+	if len(p.successStatuses) > 0 {
+		return p.successStatuses[status]
+	}
+	return status >= 200 && status < 300
+}
+
+// isRetryable classifies a failed response using the configured error rules,
+// falling back to retrying 5xx and 429 responses
+func (p *HTTPProvider) isRetryable(status int, body []byte) bool {
+	var decoded any
+	decodedOK := false
+
+	for _, rule := range p.errorRules {
+		if len(rule.StatusCodes) > 0 && !containsInt(rule.StatusCodes, status) {
+			continue
+		}
+		if rule.BodyContains != "" && !bytes.Contains(body, []byte(rule.BodyContains)) {
+			continue
+		}
+		if rule.BodyPath != "" {
+			if !decodedOK {
+				var err error
+				if decoded, err = decodeJSON(body); err != nil {
+					continue
+				}
+				decodedOK = true
+			}
+			value, ok := extractString(decoded, rule.BodyPath)
+			if !ok || (rule.BodyEquals != "" && value != rule.BodyEquals) {
+				continue
+			}
+		}
+		return rule.Retryable
+	}
+
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// parseResponse extracts the message ID and status from a successful
+// response. The message ID is left empty when the response does not carry
+// one, since receipts could never be matched to a made-up ID.
+func (p *HTTPProvider) parseResponse(body []byte) *domain.ProviderResponse {
+	resp := &domain.ProviderResponse{
+		Status:    "accepted",
+		Timestamp: time.Now().UTC(),
+	}
+
+	if decoded, err := decodeJSON(body); err == nil {
+		if id, ok := extractString(decoded, p.response.MessageIDPath); ok && p.response.MessageIDPath != "" {
+			resp.MessageID = id
+		}
+		if status, ok := extractString(decoded, p.response.StatusPath); ok && p.response.StatusPath != "" {
+			resp.Status = status
+		}
+	}
+
+	return resp
+}
+
+func newHTTPStatusRequest(name string, cfg config.HTTPStatusConfig) (*httpStatusRequest, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("http provider %s: status url is required", name)
+	}
+
+	method := strings.ToUpper(cfg.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	s := &httpStatusRequest{
+		method:    method,
+		headers:   make(map[string]*template.Template, len(cfg.Headers)),
+		batchSize: batchSize,
+		mapping:   newReceiptMapping(cfg.ReceiptMapping),
+	}
+
+	var err error
+	if s.url, err = parseTemplate(name+".status.url", cfg.URL); err != nil {
+		return nil, err
+	}
+	if s.body, err = parseTemplate(name+".status.body", cfg.Body); err != nil {
+		return nil, err
+	}
+	for key, value := range cfg.Headers {
+		if s.headers[key], err = parseTemplate(name+".status.header."+key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// render executes a request template. A failure depends only on the
+// configuration and the notification, so it is reported as a permanent
+// provider error rather than retried.
+func (p *HTTPProvider) render(tmpl *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", p.providerError(0, fmt.Sprintf("failed to render template %s: %v", tmpl.Name(), err), false)
+	}
+	return buf.String(), nil
+}
+
+// providerError returns a ProviderError naming this provider
+func (p *HTTPProvider) providerError(statusCode int, message string, retryable bool) error {
+	return newProviderError(p.name, statusCode, message, retryable)
+}
+
+// newProviderError returns a ProviderError naming the provider it came from,
+// so the delivery log attributes the failure without the router rewriting it
+func newProviderError(provider string, statusCode int, message string, retryable bool) error {
+	err := domain.NewProviderError(statusCode, message, retryable)
+	err.Provider = provider
+	return err
+}
+
+// errorBody returns a failed response body cut to maxErrorBodyBytes without
+// splitting a character
+func errorBody(body []byte) string {
+	if len(body) <= maxErrorBodyBytes {
+		return string(body)
+	}
+	cut := maxErrorBodyBytes
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "...[truncated]"
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+func TestHTTPProvider_Send(t *testing.T) {
+	var gotBody, gotPath, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Campaign")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"messages":[{"id":"vendor-1","state":"queued"}]}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewHTTPProvider(config.HTTPProviderConfig{
+		Name:    "vendor",
+		URL:     server.URL + "/send/{{.Channel}}",
+		Headers: map[string]string{"X-Campaign": `{{index .Metadata "campaign"}}`},
+		Body:    `{"msisdn":{{json .To}},"text":{{json .Content}}}`,
+		Response: config.HTTPResponseMapping{
+			MessageIDPath: "$.data.messages[0].id",
+			StatusPath:    "data.messages[0].state",
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := p.Send(context.Background(), &domain.ProviderRequest{
+		To:             "+905551234567",
+		Channel:        "sms",
+		Content:        `Say "hi"`,
+		NotificationID: uuid.New(),
+		Metadata:       map[string]any{"campaign": "spring"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "vendor-1", resp.MessageID)
+	assert.Equal(t, "queued", resp.Status)
+	assert.Equal(t, "/send/sms", gotPath)
+	assert.Equal(t, "spring", gotHeader)
+	assert.JSONEq(t, `{"msisdn":"+905551234567","text":"Say \"hi\""}`, gotBody)
+}
+
+func TestHTTPProvider_SendWithoutMessageID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accepted":true}`))
+	}))
+	defer server.Close()
+
+	p, err := NewHTTPProvider(config.HTTPProviderConfig{
+		Name:     "vendor",
+		URL:      server.URL,
+		Response: config.HTTPResponseMapping{MessageIDPath: "$.id"},
+	})
+	require.NoError(t, err)
+
+	resp, err := p.Send(context.Background(), &domain.ProviderRequest{To: "x", Channel: "sms", Content: "y"})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.MessageID)
+	assert.Equal(t, "accepted", resp.Status)
+}
+
+func TestHTTPProvider_ErrorRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantRetryable bool
+	}{
+		{"rule marks 400 with throttle code retryable", http.StatusBadRequest, `{"error":{"code":"THROTTLED"}}`, true},
+		{"rule marks 503 with invalid number permanent", http.StatusServiceUnavailable, `invalid number`, false},
+		{"unmatched 400 falls back to permanent", http.StatusBadRequest, `{"error":{"code":"BAD"}}`, false},
+		{"unmatched 502 falls back to retryable", http.StatusBadGateway, `oops`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			p, err := NewHTTPProvider(config.HTTPProviderConfig{
+				Name: "vendor",
+				URL:  server.URL,
+				ErrorRules: []config.HTTPErrorRule{
+					{StatusCodes: []int{400}, BodyPath: "error.code", BodyEquals: "THROTTLED", Retryable: true},
+					{BodyContains: "invalid number", Retryable: false},
+				},
+			})
+			require.NoError(t, err)
+
+			_, err = p.Send(context.Background(), &domain.ProviderRequest{To: "x", Channel: "sms", Content: "y"})
+
+			var providerErr domain.ProviderError
+			require.True(t, errors.As(err, &providerErr))
+			assert.Equal(t, tt.status, providerErr.StatusCode)
+			assert.Equal(t, tt.wantRetryable, providerErr.Retryable)
+			assert.Equal(t, "vendor", providerErr.Provider)
+		})
+	}
+}
+
+func TestHTTPProvider_RenderErrorIsPermanent(t *testing.T) {
+	p, err := NewHTTPProvider(config.HTTPProviderConfig{
+		Name: "vendor",
+		URL:  "http://vendor.invalid",
+		Body: `{"code":{{index .Metadata "codes" 0}}}`,
+	})
+	require.NoError(t, err)
+
+	_, err = p.Send(context.Background(), &domain.ProviderRequest{To: "x", Channel: "sms", Content: "y"})
+
+	var providerErr domain.ProviderError
+	require.True(t, errors.As(err, &providerErr))
+	assert.False(t, providerErr.Retryable)
+	assert.Equal(t, "vendor", providerErr.Provider)
+	assert.Contains(t, providerErr.Message, "failed to render template")
+}
+
+func TestHTTPProvider_TruncatesErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(strings.Repeat("ğ", maxErrorBodyBytes)))
+	}))
+	defer server.Close()
+
+	p, err := NewHTTPProvider(config.HTTPProviderConfig{Name: "vendor", URL: server.URL})
+	require.NoError(t, err)
+
+	_, err = p.Send(context.Background(), &domain.ProviderRequest{To: "x", Channel: "sms", Content: "y"})
+
+	var providerErr domain.ProviderError
+	require.True(t, errors.As(err, &providerErr))
+	assert.LessOrEqual(t, len(providerErr.Message), maxErrorBodyBytes+len("...[truncated]"))
+	assert.True(t, strings.HasSuffix(providerErr.Message, "...[truncated]"))
+	assert.True(t, utf8.ValidString(providerErr.Message))
+}
+
+func TestHTTPProvider_CheckStatus(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("ids")
+		w.Write([]byte(`{"results":[{"id":"a","state":"DELIVERED"},{"id":"b","state":"FAILED","error":"blocked"}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewHTTPProvider(config.HTTPProviderConfig{
+		Name: "vendor",
+		URL:  server.URL + "/send",
+		Status: &config.HTTPStatusConfig{
+			URL:       server.URL + `/status?ids={{join "," .IDs}}`,
+			BatchSize: 50,
+			ReceiptMapping: config.ReceiptMapping{
+				ItemsPath:       "$.results",
+				MessageIDPath:   "$.id",
+				StatusPath:      "$.state",
+				ReasonPath:      "$.error",
+				DeliveredValues: []string{"DELIVERED"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 50, p.StatusBatchSize())
+
+	receipts, err := p.CheckStatus(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "a,b", gotQuery)
+	require.Len(t, receipts, 2)
+	assert.Equal(t, domain.ReceiptDelivered, receipts[0].Status)
+	assert.Equal(t, domain.ReceiptUndeliverable, receipts[1].Status)
+	assert.Equal(t, "blocked", receipts[1].Reason)
+
+	plain, err := NewHTTPProvider(config.HTTPProviderConfig{Name: "plain", URL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, 0, plain.StatusBatchSize())
+}
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// HMACInboundParser implements domain.InboundParser for inbound messages
+// signed with the same scheme as delivery receipts
+type HMACInboundParser struct {
+	*signatureVerifier
+	name    string
+	mapping *inboundMapping
+}
+
+// inboundMapping extracts inbound messages from provider payloads
+type inboundMapping struct {
+	itemsPath     string
+	messageIDPath string
+	fromPath      string
+	toPath        string
+	bodyPath      string
+	timestampPath string
+}
+
+func newInboundMapping(cfg config.InboundMapping) *inboundMapping {
+	return &inboundMapping{
+		itemsPath:     cfg.ItemsPath,
+		messageIDPath: stringOrDefault(cfg.MessageIDPath, "$.message_id"),
+		fromPath:      stringOrDefault(cfg.FromPath, "$.from"),
+		toPath:        stringOrDefault(cfg.ToPath, "$.to"),
+		bodyPath:      stringOrDefault(cfg.BodyPath, "$.body"),
+		timestampPath: stringOrDefault(cfg.TimestampPath, "$.timestamp"),
+	}
+}
+
+// NewHMACInboundParser creates a new HMACInboundParser for the named provider
+func NewHMACInboundParser(name string, cfg config.InboundWebhookConfig) (*HMACInboundParser, error) {
+	verifier, err := newSignatureVerifier(cfg.Signature, cfg.MaxSkew)
+	if err != nil {
+		return nil, fmt.Errorf("inbound: %w", err)
+	}
+
+	return &HMACInboundParser{
+		signatureVerifier: verifier,
+		name:              name,
+		mapping:           newInboundMapping(cfg.InboundMapping),
+	}, nil
+}
+
+// NewInboundParsers creates an inbound parser for every configured provider
+func NewInboundParsers(cfg map[string]config.InboundWebhookConfig) (map[string]domain.InboundParser, error) {
+	parsers := make(map[string]domain.InboundParser, len(cfg))
+	for name, inboundCfg := range cfg {
+		parser, err := NewHMACInboundParser(name, inboundCfg)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		parsers[name] = parser
+	}
+	return parsers, nil
+}
+
+// Parse decodes one inbound message or a batch of them from body
+func (p *HMACInboundParser) Parse(body []byte) ([]*domain.InboundMessage, error) {
+	data, err := decodeJSON(body)
+	if err != nil {
+		return nil, domain.NewValidationError("body", "inbound body must be valid JSON")
+	}
+
+	m := p.mapping
+	if m.itemsPath != "" {
+		items, ok := extractPath(data, m.itemsPath)
+		if !ok {
+			return nil, domain.NewValidationError("body", fmt.Sprintf("no messages at %s", m.itemsPath))
+		}
+		data = items
+	}
+
+	items, ok := data.([]any)
+	if !ok {
+		items = []any{data}
+	}
+
+	messages := make([]*domain.InboundMessage, 0, len(items))
+	for i, item := range items {
+		message, err := p.parseItem(item)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("messages[%d]", i), err.Error())
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (p *HMACInboundParser) parseItem(item any) (*domain.InboundMessage, error) {
+	m := p.mapping
+
+	from, ok := extractString(item, m.fromPath)
+	if !ok || strings.TrimSpace(from) == "" {
+		return nil, fmt.Errorf("missing sender at %s", m.fromPath)
+	}
+
+	body, ok := extractString(item, m.bodyPath)
+	if !ok {
+		return nil, fmt.Errorf("missing body at %s", m.bodyPath)
+	}
+
+	to, _ := extractString(item, m.toPath)
+	message := domain.NewInboundMessage(p.name, strings.TrimSpace(from), strings.TrimSpace(to), body)
+
+	if messageID, ok := extractString(item, m.messageIDPath); ok && messageID != "" {
+		message.ExternalID = &messageID
+	}
+
+	if ts, ok := extractString(item, m.timestampPath); ok {
+		if receivedAt, ok := parseReceiptTime(ts); ok {
+			message.ReceivedAt = receivedAt
+		}
+	}
+
+	return message, nil
+}
+
+// InboundForwarder implements domain.InboundForwarder by posting each
+// message as JSON to the customer callback URL
+type InboundForwarder struct {
+	client *http.Client
+	url    string
+	auth   Authenticator
+}
+
+// NewInboundForwarder creates a new InboundForwarder
+func NewInboundForwarder(cfg config.InboundConfig) (*InboundForwarder, error) {
+	client := &http.Client{
+		Timeout: cfg.CallbackTimeout,
+	}
+
+	auth, err := NewAuthenticator(cfg.CallbackAuth, client)
+	if err != nil {
+		return nil, fmt.Errorf("inbound callback: %w", err)
+	}
+
+	return &InboundForwarder{
+		client: client,
+		url:    cfg.CallbackURL,
+		auth:   auth,
+	}, nil
+}
+
+// Forward posts message to the callback URL
+func (f *InboundForwarder) Forward(ctx context.Context, message *domain.InboundMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbound message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := authenticate(ctx, f.auth, req, body); err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("callback request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("callback returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
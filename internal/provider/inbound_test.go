@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+func TestHMACInboundParser_Parse(t *testing.T) {
+	parser, err := NewHMACInboundParser("netgsm", config.InboundWebhookConfig{
+		Signature: config.AuthConfig{Secret: "s3cret"},
+		InboundMapping: config.InboundMapping{
+			ItemsPath:     "$.messages",
+			MessageIDPath: "$.id",
+			FromPath:      "$.msisdn",
+			ToPath:        "$.shortcode",
+			BodyPath:      "$.text",
+			TimestampPath: "$.received",
+		},
+	})
+	require.NoError(t, err)
+
+	messages, err := parser.Parse([]byte(`{"messages":[
+		{"id":"in-1","msisdn":"+905551234567","shortcode":"4455","text":"STOP","received":"2024-01-02T03:04:05Z"},
+		{"msisdn":"+905551234568","text":"hello"}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	assert.Equal(t, "netgsm", messages[0].Provider)
+	assert.Equal(t, "in-1", *messages[0].ExternalID)
+	assert.Equal(t, "+905551234567", messages[0].From)
+	assert.Equal(t, "4455", messages[0].To)
+	assert.Equal(t, "STOP", messages[0].Body)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), messages[0].ReceivedAt)
+	assert.Nil(t, messages[1].ExternalID)
+
+	_, err = parser.Parse([]byte(`{"messages":[{"text":"STOP"}]}`))
+	assert.ErrorAs(t, err, new(domain.ValidationError))
+}
+
+func TestInboundForwarder_Forward(t *testing.T) {
+	var received domain.InboundMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	forwarder, err := NewInboundForwarder(config.InboundConfig{
+		CallbackURL:     server.URL,
+		CallbackTimeout: time.Second,
+		CallbackAuth:    config.AuthConfig{Type: "bearer", Secret: "token"},
+	})
+	require.NoError(t, err)
+
+	message := domain.NewInboundMessage("webhook", "+905551234567", "4455", "STOP")
+	require.NoError(t, forwarder.Forward(context.Background(), message))
+	assert.Equal(t, message.ID, received.ID)
+	assert.Equal(t, "STOP", received.Body)
+}
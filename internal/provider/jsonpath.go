@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractPath resolves a JSONPath-style expression against decoded JSON.
+// Supported syntax is a dotted path with optional array indexes, with or
+// without the leading "$", e.g. "$.data.messages[0].id" or "result.status".
+func extractPath(data any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return data, true
+	}
+
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes, ok := parseSegment(segment)
+		if !ok {
+			return nil, false
+		}
+
+		if name != "" {
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			current, ok = obj[name]
+			if !ok {
+				return nil, false
+			}
+		}
+
+		for _, idx := range indexes {
+			arr, ok := current.([]any)
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			current = arr[idx]
+		}
+	}
+
+	return current, true
+}
+
+// extractString resolves path and formats the result as a string
+func extractString(data any, path string) (string, bool) {
+	value, ok := extractPath(data, path)
+	if !ok || value == nil {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
+
+// parseSegment splits "items[0][1]" into the key "items" and indexes [0 1]
+func parseSegment(segment string) (string, []int, bool) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		return segment, nil, segment != ""
+	}
+
+	name := segment[:open]
+	rest := segment[open:]
+	indexes := make([]int, 0, 1)
+
+	for rest != "" {
+		if rest[0] != '[' {
+			return "", nil, false
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return "", nil, false
+		}
+		idx, err := strconv.Atoi(rest[1:end])
+		if err != nil {
+			return "", nil, false
+		}
+		indexes = append(indexes, idx)
+		rest = rest[end+1:]
+	}
+
+	return name, indexes, true
+}
+
+// decodeJSON decodes body into a generic value for path extraction
+func decodeJSON(body []byte) (any, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+var (
+	defaultDeliveredValues     = []string{"delivered"}
+	defaultUndeliverableValues = []string{"undeliverable", "undelivered", "failed", "rejected", "expired"}
+)
+
+// HMACReceiptParser implements domain.ReceiptParser for receipts signed
+// with the same "<timestamp>.<body>" HMAC-SHA256 scheme used by hmac auth
+type HMACReceiptParser struct {
+	*signatureVerifier
+	mapping *receiptMapping
+}
+
+// receiptMapping extracts delivery receipts from provider payloads
+type receiptMapping struct {
+	itemsPath           string
+	messageIDPath       string
+	statusPath          string
+	reasonPath          string
+	timestampPath       string
+	deliveredValues     []string
+	undeliverableValues []string
+}
+
+func newReceiptMapping(cfg config.ReceiptMapping) *receiptMapping {
+	m := &receiptMapping{
+		itemsPath:           cfg.ItemsPath,
+		messageIDPath:       stringOrDefault(cfg.MessageIDPath, "$.message_id"),
+		statusPath:          stringOrDefault(cfg.StatusPath, "$.status"),
+		reasonPath:          stringOrDefault(cfg.ReasonPath, "$.reason"),
+		timestampPath:       stringOrDefault(cfg.TimestampPath, "$.timestamp"),
+		deliveredValues:     cfg.DeliveredValues,
+		undeliverableValues: cfg.UndeliverableValues,
+	}
+	if len(m.deliveredValues) == 0 {
+		m.deliveredValues = defaultDeliveredValues
+	}
+	if len(m.undeliverableValues) == 0 {
+		m.undeliverableValues = defaultUndeliverableValues
+	}
+	return m
+}
+
+// NewHMACReceiptParser creates a new HMACReceiptParser
+func NewHMACReceiptParser(cfg config.ReceiptConfig) (*HMACReceiptParser, error) {
+	verifier, err := newSignatureVerifier(cfg.Signature, cfg.MaxSkew)
+	if err != nil {
+		return nil, fmt.Errorf("receipts: %w", err)
+	}
+
+	return &HMACReceiptParser{
+		signatureVerifier: verifier,
+		mapping:           newReceiptMapping(cfg.ReceiptMapping),
+	}, nil
+}
+
+// NewReceiptParsers creates a receipt parser for every configured provider
+func NewReceiptParsers(cfg map[string]config.ReceiptConfig) (map[string]domain.ReceiptParser, error) {
+	parsers := make(map[string]domain.ReceiptParser, len(cfg))
+	for name, receiptCfg := range cfg {
+		parser, err := NewHMACReceiptParser(receiptCfg)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		parsers[name] = parser
+	}
+	return parsers, nil
+}
+
+// Parse decodes one receipt or a batch of receipts from body
+func (p *HMACReceiptParser) Parse(body []byte) ([]*domain.DeliveryReceipt, error) {
+	data, err := decodeJSON(body)
+	if err != nil {
+		return nil, domain.NewValidationError("body", "receipt body must be valid JSON")
+	}
+
+	return p.mapping.parse(data)
+}
+
+// parse extracts receipts from a decoded payload
+func (m *receiptMapping) parse(data any) ([]*domain.DeliveryReceipt, error) {
+
+	if m.itemsPath != "" {
+		items, ok := extractPath(data, m.itemsPath)
+		if !ok {
+			return nil, domain.NewValidationError("body", fmt.Sprintf("no receipts at %s", m.itemsPath))
+		}
+		data = items
+	}
+
+	items, ok := data.([]any)
+	if !ok {
+		items = []any{data}
+	}
+
+	receipts := make([]*domain.DeliveryReceipt, 0, len(items))
+	for i, item := range items {
+		receipt, err := m.parseItem(item)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("receipts[%d]", i), err.Error())
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+func (m *receiptMapping) parseItem(item any) (*domain.DeliveryReceipt, error) {
+	messageID, ok := extractString(item, m.messageIDPath)
+	if !ok || messageID == "" {
+		return nil, fmt.Errorf("missing message id at %s", m.messageIDPath)
+	}
+
+	status, ok := extractString(item, m.statusPath)
+	if !ok {
+		return nil, fmt.Errorf("missing status at %s", m.statusPath)
+	}
+
+	receipt := &domain.DeliveryReceipt{
+		ExternalID: messageID,
+		Status:     m.mapStatus(status),
+	}
+
+	if reason, ok := extractString(item, m.reasonPath); ok {
+		receipt.Reason = reason
+	}
+	if receipt.Status == domain.ReceiptUndeliverable && receipt.Reason == "" {
+		receipt.Reason = status
+	}
+
+	if ts, ok := extractString(item, m.timestampPath); ok {
+		if occurredAt, ok := parseReceiptTime(ts); ok {
+			receipt.OccurredAt = &occurredAt
+		}
+	}
+
+	return receipt, nil
+}
+
+func (m *receiptMapping) mapStatus(status string) domain.ReceiptStatus {
+	for _, v := range m.deliveredValues {
+		if strings.EqualFold(v, status) {
+			return domain.ReceiptDelivered
+		}
+	}
+	for _, v := range m.undeliverableValues {
+		if strings.EqualFold(v, status) {
+			return domain.ReceiptUndeliverable
+		}
+	}
+	return domain.ReceiptPending
+}
+
+// parseReceiptTime accepts RFC 3339 timestamps and unix seconds
+func parseReceiptTime(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), true
+	}
+	if unix, err := strconv.ParseFloat(value, 64); err == nil {
+		sec := int64(unix)
+		return time.Unix(sec, int64((unix-float64(sec))*float64(time.Second))).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+func TestHMACReceiptParser_Verify(t *testing.T) {
+	parser, err := NewHMACReceiptParser(config.ReceiptConfig{Signature: config.AuthConfig{Secret: "s3cret"}})
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	parser.now = func() time.Time { return now }
+
+	body := []byte(`{"message_id":"msg-1","status":"delivered"}`)
+	signed := func(ts string, b []byte) http.Header {
+		h := http.Header{}
+		h.Set(defaultTimestampHeader, ts)
+		h.Set(defaultSignatureHeader, SignHMAC([]byte("s3cret"), ts, b))
+		return h
+	}
+
+	assert.NoError(t, parser.Verify(signed("1700000000", body), body))
+	assert.True(t, errors.Is(parser.Verify(signed("1700000000", body), []byte(`{"tampered":true}`)), domain.ErrInvalidSignature))
+	assert.True(t, errors.Is(parser.Verify(signed("1699990000", body), body), domain.ErrInvalidSignature))
+	assert.True(t, errors.Is(parser.Verify(http.Header{}, body), domain.ErrInvalidSignature))
+}
+
+func TestHMACReceiptParser_Parse(t *testing.T) {
+	parser, err := NewHMACReceiptParser(config.ReceiptConfig{
+		Signature: config.AuthConfig{Secret: "s3cret"},
+		ReceiptMapping: config.ReceiptMapping{
+			ItemsPath:           "$.events",
+			MessageIDPath:       "$.sms.id",
+			StatusPath:          "$.sms.state",
+			ReasonPath:          "$.sms.error.description",
+			TimestampPath:       "$.at",
+			DeliveredValues:     []string{"DELIVRD"},
+			UndeliverableValues: []string{"UNDELIV", "REJECTD"},
+		},
+	})
+	require.NoError(t, err)
+
+	receipts, err := parser.Parse([]byte(`{"events":[
+		{"sms":{"id":"a","state":"DELIVRD"},"at":"2024-01-02T03:04:05Z"},
+		{"sms":{"id":"b","state":"UNDELIV","error":{"description":"absent subscriber"}},"at":1704164645},
+		{"sms":{"id":"c","state":"REJECTD"}},
+		{"sms":{"id":"d","state":"ENROUTE"}}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, receipts, 4)
+
+	assert.Equal(t, "a", receipts[0].ExternalID)
+	assert.Equal(t, domain.ReceiptDelivered, receipts[0].Status)
+	require.NotNil(t, receipts[0].OccurredAt)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), *receipts[0].OccurredAt)
+
+	assert.Equal(t, domain.ReceiptUndeliverable, receipts[1].Status)
+	assert.Equal(t, "absent subscriber", receipts[1].Reason)
+	require.NotNil(t, receipts[1].OccurredAt)
+	assert.Equal(t, int64(1704164645), receipts[1].OccurredAt.Unix())
+
+	assert.Equal(t, domain.ReceiptUndeliverable, receipts[2].Status)
+	assert.Equal(t, "REJECTD", receipts[2].Reason)
+
+	assert.Equal(t, domain.ReceiptPending, receipts[3].Status)
+
+	_, err = parser.Parse([]byte(`{"events":[{"sms":{"state":"DELIVRD"}}]}`))
+	assert.Error(t, err)
+}
+
+func TestNewHMACReceiptParser_RequiresSecret(t *testing.T) {
+	_, err := NewHMACReceiptParser(config.ReceiptConfig{})
+	assert.Error(t, err)
+
+	_, err = NewHMACReceiptParser(config.ReceiptConfig{Signature: config.AuthConfig{Type: "bearer", Secret: "x"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// WebhookProviderName is the name the built-in webhook provider is
+// registered under so it can take part in weighted routes
+const WebhookProviderName = "webhook"
+
+// routeBuckets is the resolution of the sticky routing hash
+const routeBuckets = 10000
+
+// ChannelRouter implements domain.NotificationProvider by dispatching each
+// request to one of the providers routed for its channel. When a channel
+// has several weighted providers, recipients are hashed onto the weights so
+// the same recipient keeps using the same provider while weights are stable,
+// and only the recipients in the shifted share move when they change.
+type ChannelRouter struct {
+	defaultName string
+	providers   map[string]domain.NotificationProvider
+
+	mu     sync.RWMutex
+	routes map[domain.Channel][]domain.RouteWeight
+}
+
+// NewChannelRouter creates a new ChannelRouter. Channels without a route
+// are sent through the default provider, registered under defaultName.
+func NewChannelRouter(defaultName string, defaultProvider domain.NotificationProvider) *ChannelRouter {
+	return &ChannelRouter{
+		defaultName: defaultName,
+		providers:   map[string]domain.NotificationProvider{defaultName: defaultProvider},
+		routes:      make(map[domain.Channel][]domain.RouteWeight),
+	}
+}
+
+// NewChannelRouterFromConfig registers the built-in providers and the
+// configured HTTP providers, then assigns them to channels according to
+// cfg.Channels and cfg.Routes. defaultName must be one of builtin.
+func NewChannelRouterFromConfig(
+	cfg config.ProvidersConfig,
+	builtin map[string]domain.NotificationProvider,
+	defaultName string,
+) (*ChannelRouter, error) {
+	defaultProvider, ok := builtin[defaultName]
+	if !ok {
+		return nil, fmt.Errorf("unknown default provider %q", defaultName)
+	}
+	router := NewChannelRouter(defaultName, defaultProvider)
+
+	for name, p := range builtin {
+		if name == defaultName {
+			continue
+		}
+		if err := router.AddProvider(name, p); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, httpCfg := range cfg.HTTP {
+		p, err := NewHTTPProvider(httpCfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := router.AddProvider(httpCfg.Name, p); err != nil {
+			return nil, err
+		}
+	}
+
+	for channel, name := range cfg.Channels {
+		if err := router.SetRoutes(domain.Channel(channel), []domain.RouteWeight{{Provider: name, Weight: 1}}); err != nil {
+			return nil, err
+		}
+	}
+
+	for channel, routes := range cfg.Routes {
+		weights := make([]domain.RouteWeight, 0, len(routes))
+		for _, route := range routes {
+			weights = append(weights, domain.RouteWeight{Provider: route.Provider, Weight: route.Weight})
+		}
+		if err := router.SetRoutes(domain.Channel(channel), weights); err != nil {
+			return nil, err
+		}
+	}
+
+	return router, nil
+}
+
+// AddProvider registers a named provider that routes can refer to
+func (r *ChannelRouter) AddProvider(name string, provider domain.NotificationProvider) error {
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("duplicate provider name %q", name)
+	}
+	r.providers[name] = provider
+	return nil
+}
+
+// SetRoutes replaces the weighted providers for a channel. Provider order
+// is significant for stickiness: keep it stable between weight changes.
+func (r *ChannelRouter) SetRoutes(channel domain.Channel, weights []domain.RouteWeight) error {
+	if !channel.IsValid() {
+		return domain.NewValidationError("channel", fmt.Sprintf("invalid channel %q", channel))
+	}
+	if len(weights) == 0 {
+		return domain.NewValidationError("weights", "at least one provider is required")
+	}
+
+	total := 0
+	seen := make(map[string]bool, len(weights))
+	for _, w := range weights {
+		if _, ok := r.providers[w.Provider]; !ok {
+			return domain.NewValidationError("provider", fmt.Sprintf("unknown provider %q", w.Provider))
+		}
+		if seen[w.Provider] {
+			return domain.NewValidationError("provider", fmt.Sprintf("duplicate provider %q", w.Provider))
+		}
+		if w.Weight < 0 {
+			return domain.NewValidationError("weight", "weights must not be negative")
+		}
+		seen[w.Provider] = true
+		total += w.Weight
+	}
+	if total == 0 {
+		return domain.NewValidationError("weight", "at least one weight must be positive")
+	}
+
+	routes := make([]domain.RouteWeight, len(weights))
+	copy(routes, weights)
+
+	r.mu.Lock()
+	r.routes[channel] = routes
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Routes returns the current routes for all channels
+func (r *ChannelRouter) Routes() map[domain.Channel][]domain.RouteWeight {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make(map[domain.Channel][]domain.RouteWeight, len(r.routes))
+	for channel, weights := range r.routes {
+		routes[channel] = append([]domain.RouteWeight(nil), weights...)
+	}
+	return routes
+}
+
+// Providers returns the names of all registered providers
+func (r *ChannelRouter) Providers() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StatusCheckers returns the registered providers that support status polling
+func (r *ChannelRouter) StatusCheckers() map[string]domain.StatusChecker {
+	checkers := make(map[string]domain.StatusChecker)
+	for name, p := range r.providers {
+		if checker, ok := p.(domain.StatusChecker); ok && checker.StatusBatchSize() > 0 {
+			checkers[name] = checker
+		}
+	}
+	return checkers
+}
+
+// Send sends the request through the provider selected for its recipient
+func (r *ChannelRouter) Send(ctx context.Context, req *domain.ProviderRequest) (*domain.ProviderResponse, error) {
+	channel := domain.Channel(req.Channel)
+	name := r.selectProvider(channel, req.To)
+	p := r.providers[name]
+
+	resp, err := p.Send(ctx, req)
+	if resp != nil && resp.Provider == "" {
+		resp.Provider = name
+	}
+
+	return resp, err
+}
+
+// selectProvider picks the provider for a recipient on a channel
+func (r *ChannelRouter) selectProvider(channel domain.Channel, recipient string) string {
+	r.mu.RLock()
+	weights := r.routes[channel]
+	r.mu.RUnlock()
+
+	if len(weights) == 0 {
+		return r.defaultName
+	}
+	if len(weights) == 1 {
+		return weights[0].Provider
+	}
+
+	total := 0
+	for _, w := range weights {
+		total += w.Weight
+	}
+
+	bucket := int(recipientBucket(recipient)) * total / routeBuckets
+	for _, w := range weights {
+		if bucket < w.Weight {
+			return w.Provider
+		}
+		bucket -= w.Weight
+	}
+
+	return weights[len(weights)-1].Provider
+}
+
+// recipientBucket maps a recipient onto [0, routeBuckets)
+func recipientBucket(recipient string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(recipient))
+	return h.Sum32() % routeBuckets
+}
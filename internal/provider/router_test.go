@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+type stubProvider struct {
+	name string
+}
+
+func (p *stubProvider) Send(_ context.Context, _ *domain.ProviderRequest) (*domain.ProviderResponse, error) {
+	return &domain.ProviderResponse{MessageID: p.name}, nil
+}
+
+func TestChannelRouter_WeightedRouting(t *testing.T) {
+	router := NewChannelRouter(WebhookProviderName, &stubProvider{name: WebhookProviderName})
+	require.NoError(t, router.AddProvider("new", &stubProvider{name: "new"}))
+
+	route := func(oldWeight, newWeight int) map[string]string {
+		require.NoError(t, router.SetRoutes(domain.ChannelSMS, []domain.RouteWeight{
+			{Provider: WebhookProviderName, Weight: oldWeight},
+			{Provider: "new", Weight: newWeight},
+		}))
+
+		assignments := make(map[string]string)
+		for i := 0; i < 10000; i++ {
+			to := fmt.Sprintf("+90555%07d", i)
+			resp, err := router.Send(context.Background(), &domain.ProviderRequest{To: to, Channel: "sms"})
+			require.NoError(t, err)
+			assignments[to] = resp.Provider
+		}
+		return assignments
+	}
+
+	countNew := func(assignments map[string]string) int {
+		n := 0
+		for _, p := range assignments {
+			if p == "new" {
+				n++
+			}
+		}
+		return n
+	}
+
+	at5 := route(95, 5)
+	at25 := route(75, 25)
+
+	assert.InDelta(t, 500, countNew(at5), 150)
+	assert.InDelta(t, 2500, countNew(at25), 300)
+
+	// Recipients already moved to the new provider stay there as it ramps up
+	for to, p := range at5 {
+		if p == "new" {
+			assert.Equal(t, "new", at25[to], "recipient %s flipped back", to)
+		}
+	}
+
+	// Sticky per recipient for the same weights
+	assert.Equal(t, at25, route(75, 25))
+}
+
+func TestChannelRouter_SetRoutesValidation(t *testing.T) {
+	router := NewChannelRouter(WebhookProviderName, &stubProvider{name: WebhookProviderName})
+
+	assert.Error(t, router.SetRoutes(domain.ChannelSMS, nil))
+	assert.Error(t, router.SetRoutes(domain.ChannelSMS, []domain.RouteWeight{{Provider: "missing", Weight: 1}}))
+	assert.Error(t, router.SetRoutes(domain.ChannelSMS, []domain.RouteWeight{{Provider: WebhookProviderName, Weight: 0}}))
+	assert.Error(t, router.SetRoutes(domain.Channel("fax"), []domain.RouteWeight{{Provider: WebhookProviderName, Weight: 1}}))
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// SandboxProviderName is the name the sandbox provider is registered under
+const SandboxProviderName = "sandbox"
+
+// SandboxStatusMetadataKey lets a notification force the sandbox outcome:
+// a 2xx value succeeds, any other status code fails with that status
+const SandboxStatusMetadataKey = "sandbox_status"
+
+// SandboxProvider implements domain.NotificationProvider by capturing
+// messages in a store instead of delivering them. It can inject latency and
+// failures to exercise retry handling.
+type SandboxProvider struct {
+	store       domain.SandboxStore
+	errorRate   float64
+	latency     time.Duration
+	statusCodes []int
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSandboxProvider creates a new SandboxProvider
+func NewSandboxProvider(cfg config.SandboxConfig, store domain.SandboxStore) *SandboxProvider {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	statusCodes := cfg.StatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = []int{http.StatusServiceUnavailable}
+	}
+
+	return &SandboxProvider{
+		store:       store,
+		errorRate:   cfg.ErrorRate,
+		latency:     cfg.Latency,
+		statusCodes: statusCodes,
+		rand:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Send records the message and returns a simulated provider response
+func (p *SandboxProvider) Send(ctx context.Context, req *domain.ProviderRequest) (*domain.ProviderResponse, error) {
+	start := time.Now()
+
+	if p.latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(p.latency):
+		}
+	}
+
+	status := p.outcome(req)
+
+	msg := &domain.SandboxMessage{
+		ID:             "sandbox-" + uuid.New().String(),
+		NotificationID: req.NotificationID,
+		To:             req.To,
+		Channel:        domain.Channel(req.Channel),
+		Content:        req.Content,
+		Metadata:       req.Metadata,
+		Status:         "accepted",
+		StatusCode:     status,
+		LatencyMs:      time.Since(start).Milliseconds(),
+		CapturedAt:     time.Now().UTC(),
+	}
+	if !isSuccessStatus(status) {
+		msg.Status = "failed"
+	}
+
+	if err := p.store.Add(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to capture sandbox message: %w", err)
+	}
+
+	if !isSuccessStatus(status) {
+		retryable := status >= 500 || status == http.StatusTooManyRequests
+		return nil, newProviderError(SandboxProviderName, status, "sandbox injected failure", retryable)
+	}
+
+	return &domain.ProviderResponse{
+		MessageID:  msg.ID,
+		Status:     "accepted",
+		Timestamp:  msg.CapturedAt,
+		StatusCode: status,
+	}, nil
+}
+
+// outcome returns the status code to simulate for a request
+func (p *SandboxProvider) outcome(req *domain.ProviderRequest) int {
+	if forced, ok := forcedStatus(req.Metadata); ok {
+		return forced
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.errorRate > 0 && p.rand.Float64() < p.errorRate {
+		return p.statusCodes[p.rand.Intn(len(p.statusCodes))]
+	}
+
+	return http.StatusAccepted
+}
+
+// forcedStatus reads SandboxStatusMetadataKey from notification metadata
+func forcedStatus(metadata map[string]any) (int, bool) {
+	switch v := metadata[SandboxStatusMetadataKey].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		status, err := strconv.Atoi(v)
+		return status, err == nil
+	}
+	return 0, false
+}
+
+func isSuccessStatus(status int) bool {
+	return status >= 200 && status < 300
+}
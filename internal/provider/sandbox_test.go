@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+	"github.com/insider-one/notification-service/internal/repository/memory"
+)
+
+func TestSandboxProvider_CapturesMessages(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewSandboxStore(3)
+	p := NewSandboxProvider(config.SandboxConfig{}, store)
+
+	for i := 0; i < 5; i++ {
+		_, err := p.Send(ctx, &domain.ProviderRequest{To: fmt.Sprintf("user-%d", i%2), Channel: "sms", Content: "hi"})
+		require.NoError(t, err)
+	}
+
+	all, err := store.List(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3, "store should be bounded")
+	assert.Equal(t, "user-0", all[0].To, "newest first")
+
+	filtered, err := store.List(ctx, "user-1", 0)
+	require.NoError(t, err)
+	assert.Len(t, filtered, 1)
+}
+
+func TestSandboxProvider_FailureInjection(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("metadata forces status", func(t *testing.T) {
+		p := NewSandboxProvider(config.SandboxConfig{}, memory.NewSandboxStore(10))
+
+		_, err := p.Send(ctx, &domain.ProviderRequest{To: "x", Channel: "sms", Metadata: map[string]any{SandboxStatusMetadataKey: float64(400)}})
+		var providerErr domain.ProviderError
+		require.True(t, errors.As(err, &providerErr))
+		assert.Equal(t, http.StatusBadRequest, providerErr.StatusCode)
+		assert.False(t, providerErr.Retryable)
+		assert.Equal(t, SandboxProviderName, providerErr.Provider)
+
+		_, err = p.Send(ctx, &domain.ProviderRequest{To: "x", Channel: "sms", Metadata: map[string]any{SandboxStatusMetadataKey: "503"}})
+		require.True(t, errors.As(err, &providerErr))
+		assert.True(t, providerErr.Retryable)
+	})
+
+	t.Run("seeded error rate is deterministic", func(t *testing.T) {
+		cfg := config.SandboxConfig{ErrorRate: 0.5, StatusCodes: []int{500, 429}, Seed: 42}
+		outcomes := func() []bool {
+			p := NewSandboxProvider(cfg, memory.NewSandboxStore(10))
+			result := make([]bool, 20)
+			for i := range result {
+				_, err := p.Send(ctx, &domain.ProviderRequest{To: "x", Channel: "sms"})
+				result[i] = err == nil
+			}
+			return result
+		}
+
+		first := outcomes()
+		assert.Equal(t, first, outcomes())
+		assert.Contains(t, first, true)
+		assert.Contains(t, first, false)
+	})
+}
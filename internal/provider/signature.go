@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const defaultWebhookMaxSkew = 5 * time.Minute
+
+// signatureVerifier authenticates webhooks signed with the same
+// "<timestamp>.<body>" HMAC-SHA256 scheme used by hmac auth
+type signatureVerifier struct {
+	key             []byte
+	header          string
+	timestampHeader string
+	maxSkew         time.Duration
+	now             func() time.Time
+}
+
+func newSignatureVerifier(cfg config.AuthConfig, maxSkew config.Duration) (*signatureVerifier, error) {
+	if cfg.Type != "" && cfg.Type != authHMAC {
+		return nil, fmt.Errorf("unsupported signature type %q", cfg.Type)
+	}
+
+	secret, err := cfg.ResolveSecret()
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("signature secret is required")
+	}
+
+	skew := time.Duration(maxSkew)
+	if skew <= 0 {
+		skew = defaultWebhookMaxSkew
+	}
+
+	return &signatureVerifier{
+		key:             []byte(secret),
+		header:          headerOrDefault(cfg.Header, defaultSignatureHeader),
+		timestampHeader: headerOrDefault(cfg.TimestampHeader, defaultTimestampHeader),
+		maxSkew:         skew,
+		now:             time.Now,
+	}, nil
+}
+
+// Verify checks the body signature and rejects stale timestamps to limit replays
+func (v *signatureVerifier) Verify(header http.Header, body []byte) error {
+	timestamp := header.Get(v.timestampHeader)
+	signature := header.Get(v.header)
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: missing %s or %s header", domain.ErrInvalidSignature, v.header, v.timestampHeader)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", domain.ErrInvalidSignature)
+	}
+	skew := v.now().Sub(time.Unix(unix, 0))
+	if skew > v.maxSkew || skew < -v.maxSkew {
+		return fmt.Errorf("%w: timestamp outside allowed window", domain.ErrInvalidSignature)
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", domain.ErrInvalidSignature)
+	}
+	want, _ := hex.DecodeString(SignHMAC(v.key, timestamp, body))
+	if !hmac.Equal(got, want) {
+		return domain.ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -17,16 +17,25 @@ import (
 type WebhookProvider struct {
 	client  *http.Client
 	baseURL string
+	auth    Authenticator
 }
 
 // NewWebhookProvider creates a new WebhookProvider
-func NewWebhookProvider(cfg config.WebhookConfig) *WebhookProvider {
+func NewWebhookProvider(cfg config.WebhookConfig) (*WebhookProvider, error) {
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+	}
+
+	auth, err := NewAuthenticator(cfg.Auth, client)
+	if err != nil {
+		return nil, fmt.Errorf("webhook provider: %w", err)
+	}
+
 	return &WebhookProvider{
-		client: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		client:  client,
 		baseURL: cfg.URL,
-	}
+		auth:    auth,
+	}, nil
 }
 
 // Send sends a notification to the webhook provider
@@ -44,9 +53,13 @@ func (p *WebhookProvider) Send(ctx context.Context, req *domain.ProviderRequest)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
 
+	if err := authenticate(ctx, p.auth, httpReq, body); err != nil {
+		return nil, err
+	}
+
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return nil, domain.NewProviderError(0, fmt.Sprintf("request failed: %v", err), true)
+		return nil, newProviderError(WebhookProviderName, 0, fmt.Sprintf("request failed: %v", err), true)
 	}
 	defer resp.Body.Close()
 
@@ -57,8 +70,9 @@ func (p *WebhookProvider) Send(ctx context.Context, req *domain.ProviderRequest)
 
 	// Check status code
 	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
-		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
-		return nil, domain.NewProviderError(resp.StatusCode, string(respBody), retryable)
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+			invalidateOnUnauthorized(p.auth, resp.StatusCode)
+		return nil, newProviderError(WebhookProviderName, resp.StatusCode, errorBody(respBody), retryable)
 	}
 
 	// Parse responses
@@ -71,6 +85,8 @@ func (p *WebhookProvider) Send(ctx context.Context, req *domain.ProviderRequest)
 			Timestamp: time.Now().UTC(),
 		}
 	}
+	providerResp.StatusCode = resp.StatusCode
+	providerResp.Body = string(respBody)
 
 	return &providerResp, nil
 }
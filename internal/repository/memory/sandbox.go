@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// SandboxStore implements domain.SandboxStore as an in-process ring buffer
+type SandboxStore struct {
+	mu       sync.RWMutex
+	messages []*domain.SandboxMessage
+	next     int
+	full     bool
+}
+
+// NewSandboxStore creates a new SandboxStore holding up to capacity messages
+func NewSandboxStore(capacity int) *SandboxStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SandboxStore{
+		messages: make([]*domain.SandboxMessage, capacity),
+	}
+}
+
+// Add stores a message, evicting the oldest one when full
+func (s *SandboxStore) Add(_ context.Context, msg *domain.SandboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[s.next] = msg
+	s.next = (s.next + 1) % len(s.messages)
+	if s.next == 0 {
+		s.full = true
+	}
+
+	return nil
+}
+
+// List returns the newest messages first, optionally filtered by recipient
+func (s *SandboxStore) List(_ context.Context, recipient string, limit int) ([]*domain.SandboxMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := s.next
+	if s.full {
+		count = len(s.messages)
+	}
+
+	result := make([]*domain.SandboxMessage, 0)
+	for i := 0; i < count && (limit <= 0 || len(result) < limit); i++ {
+		idx := (s.next - 1 - i + len(s.messages)) % len(s.messages)
+		msg := s.messages[idx]
+		if recipient != "" && msg.To != recipient {
+			continue
+		}
+		result = append(result, msg)
+	}
+
+	return result, nil
+}
+
+// Clear removes all captured messages
+func (s *SandboxStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = make([]*domain.SandboxMessage, len(s.messages))
+	s.next = 0
+	s.full = false
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const attemptColumns = `id, notification_id, attempt_number, provider, channel, outcome, started_at, completed_at,
+	latency_ms, http_status, provider_message_id, response_body, error, correlation_id`
+
+// AttemptRepository implements domain.DeliveryAttemptRepository and
+// domain.ProviderReporter using PostgreSQL
+type AttemptRepository struct {
+	db *DB
+}
+
+// NewAttemptRepository creates a new AttemptRepository
+func NewAttemptRepository(db *DB) *AttemptRepository {
+	return &AttemptRepository{db: db}
+}
+
+// Create stores a delivery attempt, numbering it after the notification's
+// previous attempts
+func (r *AttemptRepository) Create(ctx context.Context, a *domain.DeliveryAttempt) error {
+	query := `
+		INSERT INTO delivery_attempts (` + attemptColumns + `)
+		SELECT $1, $2, COALESCE(MAX(attempt_number), 0) + 1, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		FROM delivery_attempts
+		WHERE notification_id = $2
+		RETURNING attempt_number
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		a.ID, a.NotificationID, a.Provider, a.Channel, a.Outcome, a.StartedAt, a.CompletedAt,
+		a.LatencyMs, a.HTTPStatus, a.ProviderMessageID, a.ResponseBody, a.Error, a.CorrelationID,
+	).Scan(&a.AttemptNumber)
+	if err != nil {
+		return fmt.Errorf("failed to create delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// ListByNotification returns the attempts made for a notification, oldest first
+func (r *AttemptRepository) ListByNotification(ctx context.Context, notificationID uuid.UUID) ([]*domain.DeliveryAttempt, error) {
+	query := `
+		SELECT ` + attemptColumns + `
+		FROM delivery_attempts
+		WHERE notification_id = $1
+		ORDER BY attempt_number
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, notificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := make([]*domain.DeliveryAttempt, 0)
+	for rows.Next() {
+		a := &domain.DeliveryAttempt{}
+		err := rows.Scan(
+			&a.ID, &a.NotificationID, &a.AttemptNumber, &a.Provider, &a.Channel, &a.Outcome,
+			&a.StartedAt, &a.CompletedAt, &a.LatencyMs, &a.HTTPStatus, &a.ProviderMessageID,
+			&a.ResponseBody, &a.Error, &a.CorrelationID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery attempt: %w", err)
+		}
+		attempts = append(attempts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating delivery attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// ProviderReport aggregates attempt counts, latency and errors by provider
+// and channel
+func (r *AttemptRepository) ProviderReport(ctx context.Context, filter domain.ProviderReportFilter) ([]*domain.ProviderReportRow, error) {
+	conditions := []string{"1=1"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.Provider != nil {
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", argIndex))
+		args = append(args, *filter.Provider)
+		argIndex++
+	}
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, fmt.Sprintf("started_at >= $%d", argIndex))
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		conditions = append(conditions, fmt.Sprintf("started_at <= $%d", argIndex))
+		args = append(args, *filter.EndDate)
+		argIndex++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	query := fmt.Sprintf(`
+		SELECT provider, channel, COUNT(*),
+			COUNT(*) FILTER (WHERE outcome = '%s'),
+			COALESCE(AVG(latency_ms), 0)::float8,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0)::float8
+		FROM delivery_attempts
+		WHERE %s
+		GROUP BY 1, 2
+		ORDER BY 2, 1
+	`, domain.AttemptSucceeded, whereClause)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider report: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]*domain.ProviderReportRow, 0)
+	byKey := make(map[string]*domain.ProviderReportRow)
+	for rows.Next() {
+		row := &domain.ProviderReportRow{ErrorsByStatus: make(map[string]int64)}
+		if err := rows.Scan(
+			&row.Provider, &row.Channel, &row.Attempts, &row.Successes, &row.AvgLatencyMs, &row.P95LatencyMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan provider report: %w", err)
+		}
+		row.Failures = row.Attempts - row.Successes
+		if row.Attempts > 0 {
+			row.ErrorRate = float64(row.Failures) / float64(row.Attempts)
+		}
+		report = append(report, row)
+		byKey[row.Provider+"/"+string(row.Channel)] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating provider report: %w", err)
+	}
+
+	errorsQuery := fmt.Sprintf(`
+		SELECT provider, channel, COALESCE(http_status::text, 'none'), COUNT(*)
+		FROM delivery_attempts
+		WHERE %s AND outcome <> '%s'
+		GROUP BY 1, 2, 3
+	`, whereClause, domain.AttemptSucceeded)
+
+	errRows, err := r.db.Pool.Query(ctx, errorsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider errors: %w", err)
+	}
+	defer errRows.Close()
+
+	for errRows.Next() {
+		var provider, status string
+		var channel domain.Channel
+		var count int64
+		if err := errRows.Scan(&provider, &channel, &status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan provider errors: %w", err)
+		}
+		if row, ok := byKey[provider+"/"+string(channel)]; ok {
+			row.ErrorsByStatus[status] = count
+		}
+	}
+
+	if err := errRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating provider errors: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const digestRuleColumns = `id, category, channel, window_seconds, max_count, template_name, created_at, updated_at`
+
+// DigestRuleRepository implements domain.DigestRuleRepository using PostgreSQL
+type DigestRuleRepository struct {
+	db *DB
+}
+
+// NewDigestRuleRepository creates a new DigestRuleRepository
+func NewDigestRuleRepository(db *DB) *DigestRuleRepository {
+	return &DigestRuleRepository{db: db}
+}
+
+// Create creates a new digest rule
+func (r *DigestRuleRepository) Create(ctx context.Context, rule *domain.DigestRule) error {
+	query := `
+		INSERT INTO digest_rules (` + digestRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		rule.ID, rule.Category, rule.Channel, rule.Window, rule.MaxCount, rule.TemplateName,
+		rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create digest rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a digest rule by ID
+func (r *DigestRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DigestRule, error) {
+	query := `SELECT ` + digestRuleColumns + ` FROM digest_rules WHERE id = $1`
+
+	return r.scanDigestRule(ctx, query, id)
+}
+
+// GetFor retrieves the digest rule of a category and channel
+func (r *DigestRuleRepository) GetFor(ctx context.Context, category string, channel domain.Channel) (*domain.DigestRule, error) {
+	query := `SELECT ` + digestRuleColumns + ` FROM digest_rules WHERE category = $1 AND channel = $2`
+
+	return r.scanDigestRule(ctx, query, category, channel)
+}
+
+// List retrieves all digest rules
+func (r *DigestRuleRepository) List(ctx context.Context) ([]*domain.DigestRule, error) {
+	query := `
+		SELECT ` + digestRuleColumns + `
+		FROM digest_rules
+		ORDER BY category ASC, channel ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.DigestRule, 0)
+	for rows.Next() {
+		rule, err := scanDigestRuleRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Update updates the window, max count and template of a digest rule
+func (r *DigestRuleRepository) Update(ctx context.Context, rule *domain.DigestRule) error {
+	query := `
+		UPDATE digest_rules SET
+			window_seconds = $2, max_count = $3, template_name = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, rule.ID, rule.Window, rule.MaxCount, rule.TemplateName)
+	if err != nil {
+		return fmt.Errorf("failed to update digest rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a digest rule
+func (r *DigestRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM digest_rules WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete digest rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDue retrieves the groups of held notifications whose window has
+// passed at t, that reached their rule's max count or whose rule no longer
+// exists, oldest first
+func (r *DigestRuleRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.DigestGroup, error) {
+	query := `
+		SELECT n.recipient, n.channel, n.category
+		FROM notifications n
+		LEFT JOIN digest_rules d ON d.category = n.category AND d.channel = n.channel
+		WHERE n.status = 'held'
+		GROUP BY n.recipient, n.channel, n.category, d.id, d.window_seconds, d.max_count
+		HAVING d.id IS NULL
+			OR MIN(n.created_at) <= $1 - make_interval(secs => d.window_seconds)
+			OR COUNT(*) >= d.max_count
+		ORDER BY MIN(n.created_at) ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, t, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due digests: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make([]*domain.DigestGroup, 0)
+	for rows.Next() {
+		g := &domain.DigestGroup{}
+		if err := rows.Scan(&g.Recipient, &g.Channel, &g.Category); err != nil {
+			return nil, fmt.Errorf("failed to scan due digest: %w", err)
+		}
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due digests: %w", err)
+	}
+
+	return groups, nil
+}
+
+// Helper functions
+
+func (r *DigestRuleRepository) scanDigestRule(ctx context.Context, query string, args ...any) (*domain.DigestRule, error) {
+	rule, err := scanDigestRuleRow(r.db.Pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan digest rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func scanDigestRuleRow(row pgx.Row) (*domain.DigestRule, error) {
+	rule := &domain.DigestRule{}
+	err := row.Scan(
+		&rule.ID, &rule.Category, &rule.Channel, &rule.Window, &rule.MaxCount, &rule.TemplateName,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const inboundColumns = `id, provider, external_id, from_number, to_number, body, action, received_at, created_at`
+
+// InboundRepository implements domain.InboundRepository using PostgreSQL
+type InboundRepository struct {
+	db *DB
+}
+
+// NewInboundRepository creates a new InboundRepository
+func NewInboundRepository(db *DB) *InboundRepository {
+	return &InboundRepository{db: db}
+}
+
+// Create stores an inbound message. It returns domain.ErrAlreadyExists when
+// the provider has already delivered a message with the same external ID.
+func (r *InboundRepository) Create(ctx context.Context, m *domain.InboundMessage) error {
+	query := `
+		INSERT INTO inbound_messages (` + inboundColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		m.ID, m.Provider, m.ExternalID, m.From, m.To, m.Body, m.Action, m.ReceivedAt, m.CreatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create inbound message: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an inbound message by ID
+func (r *InboundRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.InboundMessage, error) {
+	query := `
+		SELECT ` + inboundColumns + `
+		FROM inbound_messages
+		WHERE id = $1
+	`
+
+	m := &domain.InboundMessage{}
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&m.ID, &m.Provider, &m.ExternalID, &m.From, &m.To, &m.Body, &m.Action, &m.ReceivedAt, &m.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan inbound message: %w", err)
+	}
+
+	return m, nil
+}
+
+// List lists inbound messages with filters and pagination
+func (r *InboundRepository) List(ctx context.Context, filter domain.InboundFilter) (*domain.InboundListResult, error) {
+	conditions := []string{"1=1"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.From != nil {
+		conditions = append(conditions, fmt.Sprintf("from_number = $%d", argIndex))
+		args = append(args, *filter.From)
+		argIndex++
+	}
+
+	if filter.Provider != nil {
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", argIndex))
+		args = append(args, *filter.Provider)
+		argIndex++
+	}
+
+	if filter.Action != nil {
+		conditions = append(conditions, fmt.Sprintf("action = $%d", argIndex))
+		args = append(args, *filter.Action)
+		argIndex++
+	}
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, fmt.Sprintf("received_at >= $%d", argIndex))
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		conditions = append(conditions, fmt.Sprintf("received_at <= $%d", argIndex))
+		args = append(args, *filter.EndDate)
+		argIndex++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM inbound_messages WHERE %s", whereClause)
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count inbound messages: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := fmt.Sprintf(`
+		SELECT `+inboundColumns+`
+		FROM inbound_messages
+		WHERE %s
+		ORDER BY received_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, pageSize, offset)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inbound messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]*domain.InboundMessage, 0)
+	for rows.Next() {
+		m := &domain.InboundMessage{}
+		err := rows.Scan(
+			&m.ID, &m.Provider, &m.ExternalID, &m.From, &m.To, &m.Body, &m.Action, &m.ReceivedAt, &m.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inbound message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inbound messages: %w", err)
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.InboundListResult{
+		Messages:   messages,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
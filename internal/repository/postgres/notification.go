@@ -14,9 +14,28 @@ import (
 	"github.com/insider-one/notification-service/internal/domain"
 )
 
+// notificationColumns is the column list shared by all notification queries
+const notificationColumns = `id, batch_id, recipient, channel, content, priority, status,
+			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
+			metadata, error_message, created_at, updated_at,
+			provider, segments, cost, currency, delivered_at, opens, clicks, version,
+			expires_at, category, timezone, digest_id`
+
+const insertNotificationQuery = `
+		INSERT INTO notifications (` + notificationColumns + `
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28
+		)
+	`
+
+const statusEventColumns = `id, notification_id, from_status, to_status, actor, instance,
+			error, reason, correlation_id, created_at`
+
 // NotificationRepository implements domain.NotificationRepository using PostgreSQL
 type NotificationRepository struct {
-	db *DB
+	db       *DB
+	instance string
 }
 
 // NewNotificationRepository creates a new NotificationRepository
@@ -24,6 +43,12 @@ func NewNotificationRepository(db *DB) *NotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
+// SetInstance sets the instance name recorded on the status events this
+// repository writes
+func (r *NotificationRepository) SetInstance(instance string) {
+	r.instance = instance
+}
+
 // Create creates a new notification
 func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
 	metadata, err := json.Marshal(n.Metadata)
@@ -31,20 +56,18 @@ func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notificat
 		metadata = []byte("{}")
 	}
 
-	query := `
-		INSERT INTO notifications (
-			id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		)
-	`
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	_, err = r.db.Pool.Exec(ctx, query,
+	_, err = tx.Exec(ctx, insertNotificationQuery,
 		n.ID, n.BatchID, n.Recipient, n.Channel, n.Content, n.Priority, n.Status,
 		n.ScheduledAt, n.SentAt, n.ExternalID, n.RetryCount, n.IdempotencyKey,
 		metadata, n.ErrorMessage, n.CreatedAt, n.UpdatedAt,
+		n.Provider, n.Segments, n.Cost, n.Currency, n.DeliveredAt, n.Opens, n.Clicks, n.Version,
+		n.ExpiresAt, n.Category, n.TimeZone, n.DigestID,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "idempotency_key") {
@@ -53,6 +76,15 @@ func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notificat
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
+	if err := r.insertStatusEvents(ctx, tx, n); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	n.ClearPendingEvents()
 	return nil
 }
 
@@ -68,26 +100,18 @@ func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications
 	}
 	defer tx.Rollback(ctx)
 
-	query := `
-		INSERT INTO notifications (
-			id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		)
-	`
-
 	for _, n := range notifications {
 		metadata, err := json.Marshal(n.Metadata)
 		if err != nil {
 			metadata = []byte("{}")
 		}
 
-		_, err = tx.Exec(ctx, query,
+		_, err = tx.Exec(ctx, insertNotificationQuery,
 			n.ID, n.BatchID, n.Recipient, n.Channel, n.Content, n.Priority, n.Status,
 			n.ScheduledAt, n.SentAt, n.ExternalID, n.RetryCount, n.IdempotencyKey,
 			metadata, n.ErrorMessage, n.CreatedAt, n.UpdatedAt,
+			n.Provider, n.Segments, n.Cost, n.Currency, n.DeliveredAt, n.Opens, n.Clicks, n.Version,
+			n.ExpiresAt, n.Category, n.TimeZone, n.DigestID,
 		)
 		if err != nil {
 			if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "idempotency_key") {
@@ -95,21 +119,26 @@ func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications
 			}
 			return fmt.Errorf("failed to create notification in batch: %w", err)
 		}
+
+		if err := r.insertStatusEvents(ctx, tx, n); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	for _, n := range notifications {
+		n.ClearPendingEvents()
+	}
 	return nil
 }
 
 // GetByID retrieves a notification by ID
 func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
 	query := `
-		SELECT id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
+		SELECT ` + notificationColumns + `
 		FROM notifications
 		WHERE id = $1
 	`
@@ -120,9 +149,7 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 // GetByBatchID retrieves all notifications in a batch
 func (r *NotificationRepository) GetByBatchID(ctx context.Context, batchID uuid.UUID) ([]*domain.Notification, error) {
 	query := `
-		SELECT id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
+		SELECT ` + notificationColumns + `
 		FROM notifications
 		WHERE batch_id = $1
 		ORDER BY created_at ASC
@@ -134,9 +161,7 @@ func (r *NotificationRepository) GetByBatchID(ctx context.Context, batchID uuid.
 // GetByIdempotencyKey retrieves a notification by idempotency key
 func (r *NotificationRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Notification, error) {
 	query := `
-		SELECT id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
+		SELECT ` + notificationColumns + `
 		FROM notifications
 		WHERE idempotency_key = $1
 	`
@@ -144,7 +169,8 @@ func (r *NotificationRepository) GetByIdempotencyKey(ctx context.Context, key st
 	return r.scanNotification(ctx, query, key)
 }
 
-// Update updates an existing notification
+// Update updates an existing notification if it has not changed since it
+// was read, returning domain.ErrVersionConflict otherwise
 func (r *NotificationRepository) Update(ctx context.Context, n *domain.Notification) error {
 	metadata, err := json.Marshal(n.Metadata)
 	if err != nil {
@@ -156,23 +182,49 @@ func (r *NotificationRepository) Update(ctx context.Context, n *domain.Notificat
 			batch_id = $2, recipient = $3, channel = $4, content = $5,
 			priority = $6, status = $7, scheduled_at = $8, sent_at = $9,
 			external_id = $10, retry_count = $11, idempotency_key = $12,
-			metadata = $13, error_message = $14
-		WHERE id = $1
+			metadata = $13, error_message = $14, provider = $15,
+			segments = $16, cost = $17, currency = $18, delivered_at = $19,
+			expires_at = $20, category = $21, timezone = $22, digest_id = $23, version = version + 1
+		WHERE id = $1 AND version = $24
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query,
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query,
 		n.ID, n.BatchID, n.Recipient, n.Channel, n.Content, n.Priority, n.Status,
 		n.ScheduledAt, n.SentAt, n.ExternalID, n.RetryCount, n.IdempotencyKey,
-		metadata, n.ErrorMessage,
+		metadata, n.ErrorMessage, n.Provider, n.Segments, n.Cost, n.Currency, n.DeliveredAt,
+		n.ExpiresAt, n.Category, n.TimeZone, n.DigestID, n.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update notification: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return domain.ErrNotFound
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM notifications WHERE id = $1)`, n.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check notification: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	if err := r.insertStatusEvents(ctx, tx, n); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	n.Version++
+	n.ClearPendingEvents()
 	return nil
 }
 
@@ -194,42 +246,8 @@ func (r *NotificationRepository) Delete(ctx context.Context, id uuid.UUID) error
 
 // List lists notifications with filters and pagination
 func (r *NotificationRepository) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationListResult, error) {
-	// Build the WHERE clause
-	conditions := []string{"1=1"}
-	args := []any{}
-	argIndex := 1
-
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, *filter.Status)
-		argIndex++
-	}
-
-	if filter.Channel != nil {
-		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
-		args = append(args, *filter.Channel)
-		argIndex++
-	}
-
-	if filter.BatchID != nil {
-		conditions = append(conditions, fmt.Sprintf("batch_id = $%d", argIndex))
-		args = append(args, *filter.BatchID)
-		argIndex++
-	}
-
-	if filter.StartDate != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
-		args = append(args, *filter.StartDate)
-		argIndex++
-	}
-
-	if filter.EndDate != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argIndex))
-		args = append(args, *filter.EndDate)
-		argIndex++
-	}
-
-	whereClause := strings.Join(conditions, " AND ")
+	whereClause, args := notificationFilterWhere(filter)
+	argIndex := len(args) + 1
 
 	// Get total count
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM notifications WHERE %s", whereClause)
@@ -254,9 +272,7 @@ func (r *NotificationRepository) List(ctx context.Context, filter domain.Notific
 
 	// Get notifications
 	query := fmt.Sprintf(`
-		SELECT id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
+		SELECT `+notificationColumns+`
 		FROM notifications
 		WHERE %s
 		ORDER BY created_at DESC
@@ -283,12 +299,213 @@ func (r *NotificationRepository) List(ctx context.Context, filter domain.Notific
 	}, nil
 }
 
+// CancelMatching cancels every notification matching filter whose status
+// still allows cancelling. The notifications and their status events are
+// updated in a single statement.
+func (r *NotificationRepository) CancelMatching(ctx context.Context, filter domain.NotificationFilter) (*domain.BulkCancelResult, error) {
+	whereClause, args := notificationFilterWhere(filter)
+	argIndex := len(args) + 1
+
+	cancellable := make([]string, 0)
+	for _, status := range domain.StatusesBefore(domain.StatusCancelled) {
+		cancellable = append(cancellable, string(status))
+	}
+	queued := []string{string(domain.StatusPending), string(domain.StatusQueued)}
+
+	query := fmt.Sprintf(`
+		WITH targets AS (
+			SELECT id, status
+			FROM notifications
+			WHERE %s AND status = ANY($%d)
+			FOR UPDATE
+		), cancelled AS (
+			UPDATE notifications n
+			SET status = '%s', version = n.version + 1
+			FROM targets t
+			WHERE n.id = t.id
+			RETURNING n.id, t.status AS from_status, n.channel, n.priority, n.retry_count
+		), events AS (
+			INSERT INTO notification_status_events (`+statusEventColumns+`)
+			SELECT gen_random_uuid(), id, from_status, '%s', $%d, $%d, NULL, NULL, $%d, NOW()
+			FROM cancelled
+		)
+		SELECT id, channel, priority, retry_count, from_status = ANY($%d)
+		FROM cancelled
+	`, whereClause, argIndex, domain.StatusCancelled, domain.StatusCancelled,
+		argIndex+1, argIndex+2, argIndex+3, argIndex+4)
+
+	args = append(args,
+		cancellable,
+		nullIfEmpty(domain.ActorFrom(ctx)),
+		nullIfEmpty(r.instance),
+		nullIfEmpty(domain.CorrelationIDFrom(ctx)),
+		queued,
+	)
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel notifications: %w", err)
+	}
+
+	result := &domain.BulkCancelResult{QueueItems: make([]*domain.QueueItem, 0)}
+	for rows.Next() {
+		item := &domain.QueueItem{}
+		var wasQueued bool
+		if err := rows.Scan(&item.NotificationID, &item.Channel, &item.Priority, &item.RetryCount, &wasQueued); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan cancelled notification: %w", err)
+		}
+		result.Cancelled++
+		if wasQueued {
+			result.QueueItems = append(result.QueueItems, item)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cancelled notifications: %w", err)
+	}
+
+	inFlightQuery := fmt.Sprintf("SELECT COUNT(*) FROM notifications WHERE %s AND status = '%s'",
+		whereClause, domain.StatusProcessing)
+	if err := tx.QueryRow(ctx, inFlightQuery, args[:argIndex-1]...).Scan(&result.InFlight); err != nil {
+		return nil, fmt.Errorf("failed to count in-flight notifications: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// RetryMatching queues every failed notification matching filter again
+// unless it has expired. The notifications and their status events are
+// updated in a single statement.
+func (r *NotificationRepository) RetryMatching(ctx context.Context, filter domain.RetryFilter, priority *domain.Priority) (*domain.BulkRetryResult, error) {
+	whereClause, args := retryFilterWhere(filter)
+	argIndex := len(args) + 1
+
+	query := fmt.Sprintf(`
+		WITH targets AS (
+			SELECT id
+			FROM notifications
+			WHERE %s AND (expires_at IS NULL OR expires_at > NOW())
+			FOR UPDATE
+		), retried AS (
+			UPDATE notifications n
+			SET status = '%s', retry_count = 0, error_message = NULL,
+				priority = COALESCE($%d, n.priority), version = n.version + 1
+			FROM targets t
+			WHERE n.id = t.id
+			RETURNING n.id, n.channel, n.priority
+		), events AS (
+			INSERT INTO notification_status_events (`+statusEventColumns+`)
+			SELECT gen_random_uuid(), id, '%s', '%s', $%d, $%d, NULL, '%s', $%d, NOW()
+			FROM retried
+		)
+		SELECT id, channel, priority
+		FROM retried
+	`, whereClause, domain.StatusQueued, argIndex, domain.StatusFailed, domain.StatusQueued,
+		argIndex+1, argIndex+2, domain.RetryReasonManual, argIndex+3)
+
+	var priorityArg *string
+	if priority != nil {
+		value := string(*priority)
+		priorityArg = &value
+	}
+	args = append(args,
+		priorityArg,
+		nullIfEmpty(domain.ActorFrom(ctx)),
+		nullIfEmpty(r.instance),
+		nullIfEmpty(domain.CorrelationIDFrom(ctx)),
+	)
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry notifications: %w", err)
+	}
+
+	result := &domain.BulkRetryResult{QueueItems: make([]*domain.QueueItem, 0)}
+	for rows.Next() {
+		item := &domain.QueueItem{}
+		if err := rows.Scan(&item.NotificationID, &item.Channel, &item.Priority); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan retried notification: %w", err)
+		}
+		result.Retried++
+		result.QueueItems = append(result.QueueItems, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating retried notifications: %w", err)
+	}
+
+	// Retried notifications are no longer failed, so only expired ones remain
+	expiredQuery := fmt.Sprintf("SELECT COUNT(*) FROM notifications WHERE %s AND expires_at <= NOW()", whereClause)
+	if err := tx.QueryRow(ctx, expiredQuery, args[:argIndex-1]...).Scan(&result.Expired); err != nil {
+		return nil, fmt.Errorf("failed to count expired notifications: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// RevertRetry moves the retried notifications among ids that no worker has
+// picked up yet back to failed, recording their status events in the same
+// statement
+func (r *NotificationRepository) RevertRetry(ctx context.Context, ids []uuid.UUID, errorMsg string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		WITH reverted AS (
+			UPDATE notifications
+			SET status = '%s', error_message = $2, version = version + 1
+			WHERE id = ANY($1) AND status = '%s'
+			RETURNING id
+		), events AS (
+			INSERT INTO notification_status_events (`+statusEventColumns+`)
+			SELECT gen_random_uuid(), id, '%s', '%s', $3, $4, $2, NULL, $5, NOW()
+			FROM reverted
+		)
+		SELECT COUNT(*) FROM reverted
+	`, domain.StatusFailed, domain.StatusQueued, domain.StatusQueued, domain.StatusFailed)
+
+	var reverted int64
+	err := r.db.Pool.QueryRow(ctx, query,
+		ids,
+		errorMsg,
+		nullIfEmpty(domain.ActorFrom(ctx)),
+		nullIfEmpty(r.instance),
+		nullIfEmpty(domain.CorrelationIDFrom(ctx)),
+	).Scan(&reverted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revert retried notifications: %w", err)
+	}
+
+	return reverted, nil
+}
+
 // GetScheduledNotifications retrieves scheduled notifications ready to be sent
 func (r *NotificationRepository) GetScheduledNotifications(ctx context.Context, before time.Time, limit int) ([]*domain.Notification, error) {
 	query := `
-		SELECT id, batch_id, recipient, channel, content, priority, status,
-			scheduled_at, sent_at, external_id, retry_count, idempotency_key,
-			metadata, error_message, created_at, updated_at
+		SELECT ` + notificationColumns + `
 		FROM notifications
 		WHERE status = 'scheduled' AND scheduled_at <= $1
 		ORDER BY scheduled_at ASC
@@ -298,22 +515,380 @@ func (r *NotificationRepository) GetScheduledNotifications(ctx context.Context,
 	return r.scanNotifications(ctx, query, before, limit)
 }
 
-// UpdateStatus updates only the status of a notification
-func (r *NotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.Status) error {
-	query := `UPDATE notifications SET status = $2 WHERE id = $1`
+// ListHeld retrieves the held notifications of a digest group, oldest first
+func (r *NotificationRepository) ListHeld(ctx context.Context, group domain.DigestGroup, limit int) ([]*domain.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE status = 'held' AND recipient = $1 AND channel = $2 AND category = $3
+		ORDER BY created_at ASC
+		LIMIT $4
+	`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, status)
+	return r.scanNotifications(ctx, query, group.Recipient, group.Channel, group.Category, limit)
+}
+
+// Digest creates a digest notification and marks the originals merged into
+// it in a single transaction, returning domain.ErrVersionConflict if any
+// original has changed since it was read
+func (r *NotificationRepository) Digest(ctx context.Context, digest *domain.Notification, originals []*domain.Notification) error {
+	metadata, err := json.Marshal(digest.Metadata)
 	if err != nil {
-		return fmt.Errorf("failed to update notification status: %w", err)
+		metadata = []byte("{}")
 	}
 
-	if result.RowsAffected() == 0 {
-		return domain.ErrNotFound
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, insertNotificationQuery,
+		digest.ID, digest.BatchID, digest.Recipient, digest.Channel, digest.Content, digest.Priority, digest.Status,
+		digest.ScheduledAt, digest.SentAt, digest.ExternalID, digest.RetryCount, digest.IdempotencyKey,
+		metadata, digest.ErrorMessage, digest.CreatedAt, digest.UpdatedAt,
+		digest.Provider, digest.Segments, digest.Cost, digest.Currency, digest.DeliveredAt, digest.Opens, digest.Clicks, digest.Version,
+		digest.ExpiresAt, digest.Category, digest.TimeZone, digest.DigestID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create digest notification: %w", err)
+	}
+
+	if err := r.insertStatusEvents(ctx, tx, digest); err != nil {
+		return err
 	}
 
+	query := `
+		UPDATE notifications SET status = $2, digest_id = $3, version = version + 1
+		WHERE id = $1 AND version = $4
+	`
+	for _, n := range originals {
+		result, err := tx.Exec(ctx, query, n.ID, n.Status, n.DigestID, n.Version)
+		if err != nil {
+			return fmt.Errorf("failed to update digested notification: %w", err)
+		}
+		// Deleted, cancelled or digested by another replica
+		if result.RowsAffected() == 0 {
+			return domain.ErrVersionConflict
+		}
+
+		if err := r.insertStatusEvents(ctx, tx, n); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	digest.ClearPendingEvents()
+	for _, n := range originals {
+		n.Version++
+		n.ClearPendingEvents()
+	}
 	return nil
 }
 
+// GetByExternalID retrieves a notification by the message ID its provider
+// assigned. Notifications sent before the provider was recorded match any provider.
+func (r *NotificationRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE external_id = $1 AND (provider = $2 OR provider IS NULL)
+		ORDER BY sent_at DESC NULLS LAST
+		LIMIT 1
+	`
+
+	return r.scanNotification(ctx, query, externalID, provider)
+}
+
+// ClaimStatusChecks returns sent notifications of the given providers that
+// were sent after sentAfter and not checked since checkedBefore, marking them
+// checked now. Rows are locked with SKIP LOCKED so concurrent pollers on
+// other instances claim different notifications.
+func (r *NotificationRepository) ClaimStatusChecks(ctx context.Context, providers []string, sentAfter, checkedBefore time.Time, limit int) ([]*domain.Notification, error) {
+	query := `
+		UPDATE notifications SET status_checked_at = NOW()
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = 'sent' AND external_id IS NOT NULL
+				AND provider = ANY($1) AND sent_at >= $2
+				AND (status_checked_at IS NULL OR status_checked_at <= $3)
+			ORDER BY status_checked_at ASC NULLS FIRST, sent_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationColumns + `
+	`
+
+	return r.scanNotifications(ctx, query, providers, sentAfter, checkedBefore, limit)
+}
+
+// ListStatusEvents returns the status history of a notification, oldest first
+func (r *NotificationRepository) ListStatusEvents(ctx context.Context, notificationID uuid.UUID) ([]*domain.StatusEvent, error) {
+	query := `
+		SELECT ` + statusEventColumns + `
+		FROM notification_status_events
+		WHERE notification_id = $1
+		ORDER BY created_at ASC, seq ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, notificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*domain.StatusEvent, 0)
+	for rows.Next() {
+		e := &domain.StatusEvent{}
+		var fromStatus *domain.Status
+		var actor, instance *string
+		err := rows.Scan(
+			&e.ID, &e.NotificationID, &fromStatus, &e.ToStatus, &actor, &instance,
+			&e.Error, &e.Reason, &e.CorrelationID, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status event: %w", err)
+		}
+		if fromStatus != nil {
+			e.FromStatus = *fromStatus
+		}
+		if actor != nil {
+			e.Actor = *actor
+		}
+		if instance != nil {
+			e.Instance = *instance
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status events: %w", err)
+	}
+
+	return events, nil
+}
+
+// insertStatusEvents stores the status transitions recorded on n since it
+// was last saved, attributing them to the actor and correlation ID in ctx
+func (r *NotificationRepository) insertStatusEvents(ctx context.Context, tx pgx.Tx, n *domain.Notification) error {
+	query := `
+		INSERT INTO notification_status_events (` + statusEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	actor := domain.ActorFrom(ctx)
+	correlationID := nullIfEmpty(domain.CorrelationIDFrom(ctx))
+
+	for _, e := range n.PendingEvents() {
+		e.Actor = actor
+		e.Instance = r.instance
+		e.CorrelationID = correlationID
+
+		_, err := tx.Exec(ctx, query,
+			e.ID, e.NotificationID, nullIfEmpty(string(e.FromStatus)), e.ToStatus,
+			nullIfEmpty(e.Actor), nullIfEmpty(e.Instance), e.Error, e.Reason, e.CorrelationID, e.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert status event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// notificationFilterWhere builds the WHERE clause and arguments selecting
+// the notifications that match filter
+func notificationFilterWhere(filter domain.NotificationFilter) (string, []any) {
+	conditions := []string{"1=1"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.BatchID != nil {
+		conditions = append(conditions, fmt.Sprintf("batch_id = $%d", argIndex))
+		args = append(args, *filter.BatchID)
+		argIndex++
+	}
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argIndex))
+		args = append(args, *filter.EndDate)
+		argIndex++
+	}
+
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err == nil {
+			conditions = append(conditions, fmt.Sprintf("metadata @> $%d::jsonb", argIndex))
+			args = append(args, string(metadata))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// retryFilterWhere builds the WHERE clause selecting the failed
+// notifications matching filter. A notification fails when it stops
+// changing, so updated_at is its failure time.
+func retryFilterWhere(filter domain.RetryFilter) (string, []any) {
+	conditions := []string{fmt.Sprintf("status = '%s'", domain.StatusFailed)}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.BatchID != nil {
+		conditions = append(conditions, fmt.Sprintf("batch_id = $%d", argIndex))
+		args = append(args, *filter.BatchID)
+		argIndex++
+	}
+
+	if filter.FailedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("updated_at >= $%d", argIndex))
+		args = append(args, *filter.FailedAfter)
+		argIndex++
+	}
+
+	if filter.FailedBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("updated_at <= $%d", argIndex))
+		args = append(args, *filter.FailedBefore)
+		argIndex++
+	}
+
+	if filter.Provider != nil || filter.ProviderStatus != nil {
+		lastAttempt := []string{"1=1"}
+		if filter.Provider != nil {
+			lastAttempt = append(lastAttempt, fmt.Sprintf("last.provider = $%d", argIndex))
+			args = append(args, *filter.Provider)
+			argIndex++
+		}
+		if filter.ProviderStatus != nil {
+			lastAttempt = append(lastAttempt, fmt.Sprintf("last.http_status = $%d", argIndex))
+			args = append(args, *filter.ProviderStatus)
+			argIndex++
+		}
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM (
+				SELECT provider, http_status FROM delivery_attempts a
+				WHERE a.notification_id = notifications.id
+				ORDER BY attempt_number DESC
+				LIMIT 1
+			) last
+			WHERE %s
+		)`, strings.Join(lastAttempt, " AND ")))
+	}
+
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err == nil {
+			conditions = append(conditions, fmt.Sprintf("metadata @> $%d::jsonb", argIndex))
+			args = append(args, string(metadata))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// SpendReport aggregates recorded costs by day, channel and optionally
+// batch or campaign
+func (r *NotificationRepository) SpendReport(ctx context.Context, filter domain.SpendReportFilter) ([]*domain.SpendReportRow, error) {
+	conditions := []string{"cost IS NOT NULL", "sent_at IS NOT NULL"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, fmt.Sprintf("sent_at >= $%d", argIndex))
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		conditions = append(conditions, fmt.Sprintf("sent_at <= $%d", argIndex))
+		args = append(args, *filter.EndDate)
+		argIndex++
+	}
+
+	batchExpr, campaignExpr := "NULL::uuid", "NULL::text"
+	switch filter.GroupBy {
+	case domain.SpendGroupByBatch:
+		batchExpr = "batch_id"
+	case domain.SpendGroupByCampaign:
+		campaignExpr = fmt.Sprintf("metadata->>'%s'", domain.CampaignMetadataKey)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT date_trunc('day', sent_at AT TIME ZONE 'UTC') AS day, channel,
+			%s AS batch_id, %s AS campaign, COALESCE(currency, ''),
+			COUNT(*), COALESCE(SUM(segments), 0), COALESCE(SUM(cost), 0)::float8
+		FROM notifications
+		WHERE %s
+		GROUP BY 1, 2, 3, 4, 5
+		ORDER BY 1, 2, 3, 4, 5
+	`, batchExpr, campaignExpr, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spend report: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]*domain.SpendReportRow, 0)
+	for rows.Next() {
+		row := &domain.SpendReportRow{}
+		if err := rows.Scan(
+			&row.Day, &row.Channel, &row.BatchID, &row.Campaign, &row.Currency,
+			&row.Count, &row.Segments, &row.Cost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan spend report: %w", err)
+		}
+		row.Day = row.Day.UTC()
+		report = append(report, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating spend report: %w", err)
+	}
+
+	return report, nil
+}
+
 // Helper functions
 
 func (r *NotificationRepository) scanNotification(ctx context.Context, query string, args ...any) (*domain.Notification, error) {
@@ -326,6 +901,8 @@ func (r *NotificationRepository) scanNotification(ctx context.Context, query str
 		&n.ID, &n.BatchID, &n.Recipient, &n.Channel, &n.Content, &n.Priority, &n.Status,
 		&n.ScheduledAt, &n.SentAt, &n.ExternalID, &n.RetryCount, &n.IdempotencyKey,
 		&metadata, &n.ErrorMessage, &n.CreatedAt, &n.UpdatedAt,
+		&n.Provider, &n.Segments, &n.Cost, &n.Currency, &n.DeliveredAt, &n.Opens, &n.Clicks, &n.Version,
+		&n.ExpiresAt, &n.Category, &n.TimeZone, &n.DigestID,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -357,6 +934,8 @@ func (r *NotificationRepository) scanNotifications(ctx context.Context, query st
 			&n.ID, &n.BatchID, &n.Recipient, &n.Channel, &n.Content, &n.Priority, &n.Status,
 			&n.ScheduledAt, &n.SentAt, &n.ExternalID, &n.RetryCount, &n.IdempotencyKey,
 			&metadata, &n.ErrorMessage, &n.CreatedAt, &n.UpdatedAt,
+			&n.Provider, &n.Segments, &n.Cost, &n.Currency, &n.DeliveredAt, &n.Opens, &n.Clicks, &n.Version,
+			&n.ExpiresAt, &n.Category, &n.TimeZone, &n.DigestID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan notification: %w", err)
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const planColumns = `id, content, template_name, template_vars, priority, category, metadata, steps,
+	current_step, status, step_deadline, completed_at, error, version, created_at, updated_at`
+
+// PlanRepository implements domain.PlanRepository using PostgreSQL
+type PlanRepository struct {
+	db *DB
+}
+
+// NewPlanRepository creates a new PlanRepository
+func NewPlanRepository(db *DB) *PlanRepository {
+	return &PlanRepository{db: db}
+}
+
+// Create creates a new plan
+func (r *PlanRepository) Create(ctx context.Context, p *domain.Plan) error {
+	templateVars, metadata, steps, err := marshalPlan(p)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO notification_plans (` + planColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+
+	_, err = r.db.Pool.Exec(ctx, query,
+		p.ID, p.Content, p.TemplateName, templateVars, p.Priority, p.Category, metadata, steps,
+		p.CurrentStep, p.Status, p.StepDeadline, p.CompletedAt, p.Error, p.Version, p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create plan: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a plan by ID
+func (r *PlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM notification_plans WHERE id = $1`
+
+	p, err := scanPlan(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan plan: %w", err)
+	}
+
+	return p, nil
+}
+
+// Update updates an existing plan if it has not changed since it was read,
+// returning domain.ErrVersionConflict otherwise
+func (r *PlanRepository) Update(ctx context.Context, p *domain.Plan) error {
+	_, _, steps, err := marshalPlan(p)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE notification_plans SET
+			steps = $2, current_step = $3, status = $4, step_deadline = $5, completed_at = $6,
+			error = $7, version = version + 1
+		WHERE id = $1 AND version = $8
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		p.ID, steps, p.CurrentStep, p.Status, p.StepDeadline, p.CompletedAt, p.Error, p.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM notification_plans WHERE id = $1)`, p.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check plan: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	p.Version++
+	return nil
+}
+
+// ListDue retrieves active plans whose current step has not started, has
+// timed out at t or whose notification reached a final status, and those
+// with any delivered step, oldest deadline first
+func (r *PlanRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.Plan, error) {
+	final := make([]string, 0)
+	for _, status := range domain.FinalStatuses() {
+		final = append(final, string(status))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notification_plans p
+		LEFT JOIN notifications n ON n.id = (p.steps -> p.current_step ->> 'notification_id')::uuid
+		WHERE p.status = '%s'
+			AND (n.id IS NULL OR p.step_deadline <= $1 OR n.status = ANY($2) OR EXISTS (
+				SELECT 1
+				FROM jsonb_array_elements(p.steps) s
+				JOIN notifications d ON d.id = (s ->> 'notification_id')::uuid
+				WHERE d.status = '%s'
+			))
+		ORDER BY p.step_deadline ASC NULLS FIRST
+		LIMIT $3
+	`, planColumnList("p"), domain.PlanActive, domain.StatusDelivered)
+
+	rows, err := r.db.Pool.Query(ctx, query, t, final, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := make([]*domain.Plan, 0)
+	for rows.Next() {
+		p, err := scanPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan plan: %w", err)
+		}
+		plans = append(plans, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// Helper functions
+
+// planColumnList qualifies planColumns with a table alias
+func planColumnList(alias string) string {
+	columns := strings.Split(planColumns, ",")
+	for i, column := range columns {
+		columns[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func marshalPlan(p *domain.Plan) ([]byte, []byte, []byte, error) {
+	templateVars, err := json.Marshal(p.TemplateVars)
+	if err != nil {
+		templateVars = []byte("{}")
+	}
+	metadata, err := json.Marshal(p.Metadata)
+	if err != nil {
+		metadata = []byte("{}")
+	}
+	steps, err := json.Marshal(p.Steps)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal plan steps: %w", err)
+	}
+	return templateVars, metadata, steps, nil
+}
+
+func scanPlan(row pgx.Row) (*domain.Plan, error) {
+	p := &domain.Plan{}
+	var templateVars, metadata, steps []byte
+
+	err := row.Scan(
+		&p.ID, &p.Content, &p.TemplateName, &templateVars, &p.Priority, &p.Category, &metadata, &steps,
+		&p.CurrentStep, &p.Status, &p.StepDeadline, &p.CompletedAt, &p.Error, &p.Version, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templateVars) > 0 {
+		json.Unmarshal(templateVars, &p.TemplateVars)
+	}
+	if len(metadata) > 0 {
+		json.Unmarshal(metadata, &p.Metadata)
+	}
+	if err := json.Unmarshal(steps, &p.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plan steps: %w", err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const publicationColumns = `id, topic, content, template_name, template_vars, priority, category, metadata, expires_at,
+	status, last_subscription_id, subscribers, notifications, skipped, error, version, created_at, updated_at, completed_at`
+
+// PublicationRepository implements domain.PublicationRepository using PostgreSQL
+type PublicationRepository struct {
+	db *DB
+}
+
+// NewPublicationRepository creates a new PublicationRepository
+func NewPublicationRepository(db *DB) *PublicationRepository {
+	return &PublicationRepository{db: db}
+}
+
+// Create creates a new publication
+func (r *PublicationRepository) Create(ctx context.Context, p *domain.Publication) error {
+	templateVars, err := json.Marshal(p.TemplateVars)
+	if err != nil {
+		templateVars = []byte("{}")
+	}
+	metadata, err := json.Marshal(p.Metadata)
+	if err != nil {
+		metadata = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO topic_publications (` + publicationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`
+
+	_, err = r.db.Pool.Exec(ctx, query,
+		p.ID, p.Topic, p.Content, p.TemplateName, templateVars, p.Priority, p.Category, metadata, p.ExpiresAt,
+		p.Status, p.LastSubscriptionID, p.Subscribers, p.Notifications, p.Skipped, p.Error, p.Version,
+		p.CreatedAt, p.UpdatedAt, p.CompletedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "topic_publications_topic_fkey") {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to create publication: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a publication by ID
+func (r *PublicationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Publication, error) {
+	query := `SELECT ` + publicationColumns + ` FROM topic_publications WHERE id = $1`
+
+	p, err := scanPublication(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan publication: %w", err)
+	}
+
+	return p, nil
+}
+
+// Update updates the progress of a publication if it has not changed since
+// it was read, returning domain.ErrVersionConflict otherwise
+func (r *PublicationRepository) Update(ctx context.Context, p *domain.Publication) error {
+	query := `
+		UPDATE topic_publications SET
+			status = $2, last_subscription_id = $3, subscribers = $4, notifications = $5, skipped = $6,
+			error = $7, completed_at = $8, version = version + 1
+		WHERE id = $1 AND version = $9
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		p.ID, p.Status, p.LastSubscriptionID, p.Subscribers, p.Notifications, p.Skipped,
+		p.Error, p.CompletedAt, p.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update publication: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM topic_publications WHERE id = $1)`, p.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check publication: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	p.Version++
+	return nil
+}
+
+// ListDue retrieves pending and running publications, oldest first
+func (r *PublicationRepository) ListDue(ctx context.Context, limit int) ([]*domain.Publication, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM topic_publications
+		WHERE status IN ('%s', '%s')
+		ORDER BY created_at ASC
+		LIMIT $1
+	`, publicationColumns, domain.PublicationPending, domain.PublicationRunning)
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query publications: %w", err)
+	}
+	defer rows.Close()
+
+	publications := make([]*domain.Publication, 0)
+	for rows.Next() {
+		p, err := scanPublication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan publication: %w", err)
+		}
+		publications = append(publications, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating publications: %w", err)
+	}
+
+	return publications, nil
+}
+
+// Helper functions
+
+func scanPublication(row pgx.Row) (*domain.Publication, error) {
+	p := &domain.Publication{}
+	var templateVars, metadata []byte
+
+	err := row.Scan(
+		&p.ID, &p.Topic, &p.Content, &p.TemplateName, &templateVars, &p.Priority, &p.Category, &metadata, &p.ExpiresAt,
+		&p.Status, &p.LastSubscriptionID, &p.Subscribers, &p.Notifications, &p.Skipped, &p.Error, &p.Version,
+		&p.CreatedAt, &p.UpdatedAt, &p.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templateVars) > 0 {
+		json.Unmarshal(templateVars, &p.TemplateVars)
+	}
+	if len(metadata) > 0 {
+		json.Unmarshal(metadata, &p.Metadata)
+	}
+
+	return p, nil
+}
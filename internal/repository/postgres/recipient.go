@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const recipientColumns = `user_id, phone, email, device_tokens, locale, timezone, created_at, updated_at`
+
+const upsertRecipientQuery = `
+		INSERT INTO recipients (` + recipientColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+			phone = EXCLUDED.phone, email = EXCLUDED.email, device_tokens = EXCLUDED.device_tokens,
+			locale = EXCLUDED.locale, timezone = EXCLUDED.timezone, updated_at = EXCLUDED.updated_at
+	`
+
+// RecipientRepository implements domain.RecipientRepository using PostgreSQL
+type RecipientRepository struct {
+	db *DB
+}
+
+// NewRecipientRepository creates a new RecipientRepository
+func NewRecipientRepository(db *DB) *RecipientRepository {
+	return &RecipientRepository{db: db}
+}
+
+// Create creates a new recipient
+func (r *RecipientRepository) Create(ctx context.Context, rc *domain.Recipient) error {
+	query := `
+		INSERT INTO recipients (` + recipientColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		rc.UserID, rc.Phone, rc.Email, rc.DeviceTokens, rc.Locale, rc.TimeZone, rc.CreatedAt, rc.UpdatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create recipient: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert creates a recipient or replaces the profile of the same user
+func (r *RecipientRepository) Upsert(ctx context.Context, rc *domain.Recipient) error {
+	_, err := r.db.Pool.Exec(ctx, upsertRecipientQuery,
+		rc.UserID, rc.Phone, rc.Email, rc.DeviceTokens, rc.Locale, rc.TimeZone, rc.CreatedAt, rc.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert recipient: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertBatch creates or replaces multiple recipients in a single transaction
+func (r *RecipientRepository) UpsertBatch(ctx context.Context, recipients []*domain.Recipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, rc := range recipients {
+		_, err := tx.Exec(ctx, upsertRecipientQuery,
+			rc.UserID, rc.Phone, rc.Email, rc.DeviceTokens, rc.Locale, rc.TimeZone, rc.CreatedAt, rc.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert recipient: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID retrieves the recipient of a user
+func (r *RecipientRepository) GetByUserID(ctx context.Context, userID string) (*domain.Recipient, error) {
+	query := `SELECT ` + recipientColumns + ` FROM recipients WHERE user_id = $1`
+
+	rc, err := scanRecipient(r.db.Pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan recipient: %w", err)
+	}
+
+	return rc, nil
+}
+
+// List lists recipients with pagination
+func (r *RecipientRepository) List(ctx context.Context, filter domain.RecipientFilter) (*domain.RecipientListResult, error) {
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM recipients").Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT ` + recipientColumns + `
+		FROM recipients
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	recipients, err := r.scanRecipients(ctx, query, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.RecipientListResult{
+		Recipients: recipients,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Delete deletes the recipient of a user
+func (r *RecipientRepository) Delete(ctx context.Context, userID string) error {
+	query := `DELETE FROM recipients WHERE user_id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recipient: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindByUserIDs returns the recipients among userIDs
+func (r *RecipientRepository) FindByUserIDs(ctx context.Context, userIDs []string) (map[string]*domain.Recipient, error) {
+	found := make(map[string]*domain.Recipient)
+	if len(userIDs) == 0 {
+		return found, nil
+	}
+
+	query := `SELECT ` + recipientColumns + ` FROM recipients WHERE user_id = ANY($1)`
+
+	recipients, err := r.scanRecipients(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rc := range recipients {
+		found[rc.UserID] = rc
+	}
+
+	return found, nil
+}
+
+// Helper functions
+
+func scanRecipient(row pgx.Row) (*domain.Recipient, error) {
+	rc := &domain.Recipient{}
+	err := row.Scan(
+		&rc.UserID, &rc.Phone, &rc.Email, &rc.DeviceTokens, &rc.Locale, &rc.TimeZone, &rc.CreatedAt, &rc.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+func (r *RecipientRepository) scanRecipients(ctx context.Context, query string, args ...any) ([]*domain.Recipient, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := make([]*domain.Recipient, 0)
+	for rows.Next() {
+		rc, err := scanRecipient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recipient: %w", err)
+		}
+		recipients = append(recipients, rc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recipients: %w", err)
+	}
+
+	return recipients, nil
+}
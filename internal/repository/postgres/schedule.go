@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const scheduleColumns = `id, name, recipient, channel, template_name, template_vars, priority, category,
+	metadata, cron, rrule, timezone, starts_at, ends_at, max_occurrences, occurrences, status,
+	next_run_at, last_run_at, last_error, version, created_at, updated_at`
+
+// ScheduleRepository implements domain.ScheduleRepository using PostgreSQL
+type ScheduleRepository struct {
+	db *DB
+}
+
+// NewScheduleRepository creates a new ScheduleRepository
+func NewScheduleRepository(db *DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Create creates a new schedule
+func (r *ScheduleRepository) Create(ctx context.Context, s *domain.Schedule) error {
+	templateVars, metadata := marshalScheduleMaps(s)
+
+	query := `
+		INSERT INTO schedules (` + scheduleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		s.ID, s.Name, s.Recipient, s.Channel, s.TemplateName, templateVars, s.Priority, s.Category,
+		metadata, s.Cron, s.RRule, s.TimeZone, s.StartsAt, s.EndsAt, s.MaxOccurrences, s.Occurrences, s.Status,
+		s.NextRunAt, s.LastRunAt, s.LastError, s.Version, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a schedule by ID
+func (r *ScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = $1`
+
+	s, err := scanSchedule(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan schedule: %w", err)
+	}
+
+	return s, nil
+}
+
+// List retrieves schedules with filtering and pagination
+func (r *ScheduleRepository) List(ctx context.Context, filter domain.ScheduleFilter) (*domain.ScheduleListResult, error) {
+	conditions := []string{"1=1"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.Recipient != nil {
+		conditions = append(conditions, fmt.Sprintf("recipient = $%d", argIndex))
+		args = append(args, *filter.Recipient)
+		argIndex++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM schedules WHERE %s", whereClause)
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count schedules: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := fmt.Sprintf(`
+		SELECT `+scheduleColumns+`
+		FROM schedules
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, pageSize, offset)
+	schedules, err := r.scanSchedules(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.ScheduleListResult{
+		Schedules:  schedules,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Update updates an existing schedule if it has not changed since it was
+// read, returning domain.ErrVersionConflict otherwise
+func (r *ScheduleRepository) Update(ctx context.Context, s *domain.Schedule) error {
+	templateVars, metadata := marshalScheduleMaps(s)
+
+	query := `
+		UPDATE schedules SET
+			name = $2, recipient = $3, channel = $4, template_name = $5, template_vars = $6,
+			priority = $7, category = $8, metadata = $9, cron = $10, rrule = $11, timezone = $12,
+			starts_at = $13, ends_at = $14, max_occurrences = $15, occurrences = $16, status = $17,
+			next_run_at = $18, last_run_at = $19, last_error = $20, version = version + 1
+		WHERE id = $1 AND version = $21
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		s.ID, s.Name, s.Recipient, s.Channel, s.TemplateName, templateVars,
+		s.Priority, s.Category, metadata, s.Cron, s.RRule, s.TimeZone,
+		s.StartsAt, s.EndsAt, s.MaxOccurrences, s.Occurrences, s.Status,
+		s.NextRunAt, s.LastRunAt, s.LastError, s.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM schedules WHERE id = $1)`, s.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check schedule: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrVersionConflict
+	}
+
+	s.Version++
+	return nil
+}
+
+// Delete deletes a schedule
+func (r *ScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM schedules WHERE id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDue retrieves active schedules whose next run is at or before t,
+// earliest first
+func (r *ScheduleRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.Schedule, error) {
+	query := fmt.Sprintf(`
+		SELECT `+scheduleColumns+`
+		FROM schedules
+		WHERE status = '%s' AND next_run_at <= $1
+		ORDER BY next_run_at ASC
+		LIMIT $2
+	`, domain.ScheduleActive)
+
+	return r.scanSchedules(ctx, query, t, limit)
+}
+
+// Helper functions
+
+func marshalScheduleMaps(s *domain.Schedule) ([]byte, []byte) {
+	templateVars, err := json.Marshal(s.TemplateVars)
+	if err != nil {
+		templateVars = []byte("{}")
+	}
+	metadata, err := json.Marshal(s.Metadata)
+	if err != nil {
+		metadata = []byte("{}")
+	}
+	return templateVars, metadata
+}
+
+func scanSchedule(row pgx.Row) (*domain.Schedule, error) {
+	s := &domain.Schedule{}
+	var templateVars, metadata []byte
+
+	err := row.Scan(
+		&s.ID, &s.Name, &s.Recipient, &s.Channel, &s.TemplateName, &templateVars, &s.Priority, &s.Category,
+		&metadata, &s.Cron, &s.RRule, &s.TimeZone, &s.StartsAt, &s.EndsAt, &s.MaxOccurrences, &s.Occurrences,
+		&s.Status, &s.NextRunAt, &s.LastRunAt, &s.LastError, &s.Version, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templateVars) > 0 {
+		json.Unmarshal(templateVars, &s.TemplateVars)
+	}
+	if len(metadata) > 0 {
+		json.Unmarshal(metadata, &s.Metadata)
+	}
+
+	return s, nil
+}
+
+func (r *ScheduleRepository) scanSchedules(ctx context.Context, query string, args ...any) ([]*domain.Schedule, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]*domain.Schedule, 0)
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules: %w", err)
+	}
+
+	return schedules, nil
+}
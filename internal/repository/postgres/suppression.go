@@ -0,0 +1,280 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const suppressionColumns = `recipient, channel, reason, note, expires_at, created_at, updated_at`
+
+const upsertSuppressionQuery = `
+		INSERT INTO suppressions (` + suppressionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (channel, recipient) DO UPDATE SET
+			reason = EXCLUDED.reason, note = EXCLUDED.note,
+			expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+	`
+
+// SuppressionRepository implements domain.SuppressionRepository using PostgreSQL
+type SuppressionRepository struct {
+	db *DB
+}
+
+// NewSuppressionRepository creates a new SuppressionRepository
+func NewSuppressionRepository(db *DB) *SuppressionRepository {
+	return &SuppressionRepository{db: db}
+}
+
+// Upsert creates or replaces a suppression
+func (r *SuppressionRepository) Upsert(ctx context.Context, s *domain.Suppression) error {
+	_, err := r.db.Pool.Exec(ctx, upsertSuppressionQuery,
+		s.Recipient, s.Channel, s.Reason, s.Note, s.ExpiresAt, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert suppression: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertBatch creates or replaces multiple suppressions in a single transaction
+func (r *SuppressionRepository) UpsertBatch(ctx context.Context, suppressions []*domain.Suppression) error {
+	if len(suppressions) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, s := range suppressions {
+		_, err := tx.Exec(ctx, upsertSuppressionQuery,
+			s.Recipient, s.Channel, s.Reason, s.Note, s.ExpiresAt, s.CreatedAt, s.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert suppression: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the suppression for a recipient on a channel, including expired ones
+func (r *SuppressionRepository) Get(ctx context.Context, channel domain.Channel, recipient string) (*domain.Suppression, error) {
+	query := `
+		SELECT ` + suppressionColumns + `
+		FROM suppressions
+		WHERE channel = $1 AND recipient = $2
+	`
+
+	s := &domain.Suppression{}
+	err := r.db.Pool.QueryRow(ctx, query, channel, recipient).Scan(
+		&s.Recipient, &s.Channel, &s.Reason, &s.Note, &s.ExpiresAt, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan suppression: %w", err)
+	}
+
+	return s, nil
+}
+
+// List lists suppressions with filters and pagination
+func (r *SuppressionRepository) List(ctx context.Context, filter domain.SuppressionFilter) (*domain.SuppressionListResult, error) {
+	conditions := []string{"1=1"}
+	args := []any{}
+	argIndex := 1
+
+	if filter.Channel != nil {
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", argIndex))
+		args = append(args, *filter.Channel)
+		argIndex++
+	}
+
+	if filter.Reason != nil {
+		conditions = append(conditions, fmt.Sprintf("reason = $%d", argIndex))
+		args = append(args, *filter.Reason)
+		argIndex++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM suppressions WHERE %s", whereClause)
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count suppressions: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := fmt.Sprintf(`
+		SELECT `+suppressionColumns+`
+		FROM suppressions
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, pageSize, offset)
+	suppressions, err := r.scanSuppressions(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.SuppressionListResult{
+		Suppressions: suppressions,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+		TotalPages:   totalPages,
+	}, nil
+}
+
+// Delete removes the suppression for a recipient on a channel
+func (r *SuppressionRepository) Delete(ctx context.Context, channel domain.Channel, recipient string) error {
+	query := `DELETE FROM suppressions WHERE channel = $1 AND recipient = $2`
+
+	result, err := r.db.Pool.Exec(ctx, query, channel, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to delete suppression: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindActive returns the unexpired suppressions among recipients on a channel
+func (r *SuppressionRepository) FindActive(ctx context.Context, channel domain.Channel, recipients []string) (map[string]*domain.Suppression, error) {
+	found := make(map[string]*domain.Suppression)
+	if len(recipients) == 0 {
+		return found, nil
+	}
+
+	query := `
+		SELECT ` + suppressionColumns + `
+		FROM suppressions
+		WHERE channel = $1 AND recipient = ANY($2)
+		  AND (expires_at IS NULL OR expires_at > NOW())
+	`
+
+	suppressions, err := r.scanSuppressions(ctx, query, channel, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range suppressions {
+		found[s.Recipient] = s
+	}
+
+	return found, nil
+}
+
+// ListUnnormalizedPhones returns SMS suppressions whose recipient is not an
+// E.164 number, in recipient order after the given recipient
+func (r *SuppressionRepository) ListUnnormalizedPhones(ctx context.Context, after string, limit int) ([]*domain.Suppression, error) {
+	query := `
+		SELECT ` + suppressionColumns + `
+		FROM suppressions
+		WHERE channel = 'sms' AND recipient !~ '^\+[0-9]+$' AND recipient > $1
+		ORDER BY recipient
+		LIMIT $2
+	`
+
+	return r.scanSuppressions(ctx, query, after, limit)
+}
+
+// suppressionReasonRank orders reasons from the one an opt-in may lift to
+// the ones only an operator should
+const suppressionReasonRank = `ARRAY['unsubscribe', 'manual', 'bounce', 'complaint']`
+
+// Rekey moves the suppression for a recipient to a new recipient. When one
+// is already stored under the new recipient the two are merged into the
+// strictest of both: the higher ranked reason with its note, and the later
+// expiry, where no expiry is the latest.
+func (r *SuppressionRepository) Rekey(ctx context.Context, channel domain.Channel, from, to string) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM suppressions
+			WHERE channel = $1 AND recipient = $2
+			RETURNING ` + suppressionColumns + `
+		)
+		INSERT INTO suppressions (` + suppressionColumns + `)
+		SELECT $3, channel, reason, note, expires_at, created_at, updated_at FROM moved
+		ON CONFLICT (channel, recipient) DO UPDATE SET
+			reason = CASE WHEN array_position(` + suppressionReasonRank + `, EXCLUDED.reason::text) >
+				array_position(` + suppressionReasonRank + `, suppressions.reason::text)
+				THEN EXCLUDED.reason ELSE suppressions.reason END,
+			note = CASE WHEN array_position(` + suppressionReasonRank + `, EXCLUDED.reason::text) >
+				array_position(` + suppressionReasonRank + `, suppressions.reason::text)
+				THEN EXCLUDED.note ELSE suppressions.note END,
+			expires_at = CASE WHEN suppressions.expires_at IS NULL OR EXCLUDED.expires_at IS NULL THEN NULL
+				ELSE GREATEST(suppressions.expires_at, EXCLUDED.expires_at) END,
+			created_at = LEAST(suppressions.created_at, EXCLUDED.created_at),
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, channel, from, to); err != nil {
+		return fmt.Errorf("failed to rekey suppression: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SuppressionRepository) scanSuppressions(ctx context.Context, query string, args ...any) ([]*domain.Suppression, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suppressions: %w", err)
+	}
+	defer rows.Close()
+
+	suppressions := make([]*domain.Suppression, 0)
+	for rows.Next() {
+		s := &domain.Suppression{}
+		err := rows.Scan(
+			&s.Recipient, &s.Channel, &s.Reason, &s.Note, &s.ExpiresAt, &s.CreatedAt, &s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suppression: %w", err)
+		}
+		suppressions = append(suppressions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
@@ -31,12 +31,12 @@ func (r *TemplateRepository) Create(ctx context.Context, t *domain.Template) err
 	}
 
 	query := `
-		INSERT INTO templates (id, name, channel, content, variables, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO templates (id, name, channel, content, variables, tracking_disabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err = r.db.Pool.Exec(ctx, query,
-		t.ID, t.Name, t.Channel, t.Content, variables, t.CreatedAt, t.UpdatedAt,
+		t.ID, t.Name, t.Channel, t.Content, variables, t.TrackingDisabled, t.CreatedAt, t.UpdatedAt,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -51,7 +51,7 @@ func (r *TemplateRepository) Create(ctx context.Context, t *domain.Template) err
 // GetByID retrieves a template by ID
 func (r *TemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Template, error) {
 	query := `
-		SELECT id, name, channel, content, variables, created_at, updated_at
+		SELECT id, name, channel, content, variables, tracking_disabled, created_at, updated_at
 		FROM templates
 		WHERE id = $1
 	`
@@ -62,7 +62,7 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 // GetByName retrieves a template by name
 func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*domain.Template, error) {
 	query := `
-		SELECT id, name, channel, content, variables, created_at, updated_at
+		SELECT id, name, channel, content, variables, tracking_disabled, created_at, updated_at
 		FROM templates
 		WHERE name = $1
 	`
@@ -73,7 +73,7 @@ func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*domai
 // List retrieves all templates
 func (r *TemplateRepository) List(ctx context.Context) ([]*domain.Template, error) {
 	query := `
-		SELECT id, name, channel, content, variables, created_at, updated_at
+		SELECT id, name, channel, content, variables, tracking_disabled, created_at, updated_at
 		FROM templates
 		ORDER BY name ASC
 	`
@@ -90,12 +90,13 @@ func (r *TemplateRepository) Update(ctx context.Context, t *domain.Template) err
 
 	query := `
 		UPDATE templates SET
-			name = $2, channel = $3, content = $4, variables = $5
+			name = $2, channel = $3, content = $4, variables = $5,
+			tracking_disabled = $6
 		WHERE id = $1
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query,
-		t.ID, t.Name, t.Channel, t.Content, variables,
+		t.ID, t.Name, t.Channel, t.Content, variables, t.TrackingDisabled,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -136,7 +137,7 @@ func (r *TemplateRepository) scanTemplate(ctx context.Context, query string, arg
 	var variables []byte
 
 	err := row.Scan(
-		&t.ID, &t.Name, &t.Channel, &t.Content, &variables, &t.CreatedAt, &t.UpdatedAt,
+		&t.ID, &t.Name, &t.Channel, &t.Content, &variables, &t.TrackingDisabled, &t.CreatedAt, &t.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -165,7 +166,7 @@ func (r *TemplateRepository) scanTemplates(ctx context.Context, query string, ar
 		var variables []byte
 
 		err := rows.Scan(
-			&t.ID, &t.Name, &t.Channel, &t.Content, &variables, &t.CreatedAt, &t.UpdatedAt,
+			&t.ID, &t.Name, &t.Channel, &t.Content, &variables, &t.TrackingDisabled, &t.CreatedAt, &t.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan template: %w", err)
@@ -0,0 +1,275 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const topicColumns = `name, description, created_at, updated_at`
+
+const subscriptionColumns = `id, topic, user_id, channels, created_at, updated_at`
+
+// TopicRepository implements domain.TopicRepository using PostgreSQL
+type TopicRepository struct {
+	db *DB
+}
+
+// NewTopicRepository creates a new TopicRepository
+func NewTopicRepository(db *DB) *TopicRepository {
+	return &TopicRepository{db: db}
+}
+
+// Create creates a new topic
+func (r *TopicRepository) Create(ctx context.Context, t *domain.Topic) error {
+	query := `
+		INSERT INTO topics (` + topicColumns + `)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, t.Name, t.Description, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create topic: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a topic by name
+func (r *TopicRepository) GetByName(ctx context.Context, name string) (*domain.Topic, error) {
+	query := `SELECT ` + topicColumns + ` FROM topics WHERE name = $1`
+
+	t := &domain.Topic{}
+	err := r.db.Pool.QueryRow(ctx, query, name).Scan(&t.Name, &t.Description, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan topic: %w", err)
+	}
+
+	return t, nil
+}
+
+// List lists topics with pagination
+func (r *TopicRepository) List(ctx context.Context, filter domain.TopicFilter) (*domain.TopicListResult, error) {
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM topics").Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count topics: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT ` + topicColumns + `
+		FROM topics
+		ORDER BY name ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topics: %w", err)
+	}
+	defer rows.Close()
+
+	topics := make([]*domain.Topic, 0)
+	for rows.Next() {
+		t := &domain.Topic{}
+		if err := rows.Scan(&t.Name, &t.Description, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan topic: %w", err)
+		}
+		topics = append(topics, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating topics: %w", err)
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.TopicListResult{
+		Topics:     topics,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Delete deletes a topic with its subscriptions and publications
+func (r *TopicRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM topics WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Subscribe creates a subscription or replaces the channels of an existing one
+func (r *TopicRepository) Subscribe(ctx context.Context, s *domain.Subscription) error {
+	query := `
+		INSERT INTO topic_subscriptions (topic, user_id, channels, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (topic, user_id) DO UPDATE SET
+			channels = EXCLUDED.channels, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		s.Topic, s.UserID, channelStrings(s.Channels), s.CreatedAt, s.UpdatedAt,
+	).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "topic_subscriptions_user_id_fkey") {
+			return fmt.Errorf("%w: %s", domain.ErrRecipientNotFound, s.UserID)
+		}
+		if strings.Contains(err.Error(), "topic_subscriptions_topic_fkey") {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	return nil
+}
+
+// Unsubscribe deletes the subscription of a recipient to a topic
+func (r *TopicRepository) Unsubscribe(ctx context.Context, topic, userID string) error {
+	query := `DELETE FROM topic_subscriptions WHERE topic = $1 AND user_id = $2`
+
+	result, err := r.db.Pool.Exec(ctx, query, topic, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListSubscriptions lists the subscriptions to a topic with pagination
+func (r *TopicRepository) ListSubscriptions(ctx context.Context, topic string, filter domain.SubscriptionFilter) (*domain.SubscriptionListResult, error) {
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM topic_subscriptions WHERE topic = $1", topic).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM topic_subscriptions
+		WHERE topic = $1
+		ORDER BY id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	subscriptions, err := r.scanSubscriptions(ctx, query, topic, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &domain.SubscriptionListResult{
+		Subscriptions: subscriptions,
+		Total:         total,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalPages:    totalPages,
+	}, nil
+}
+
+// ListSubscriptionsAfter retrieves the next subscriptions to a topic after
+// afterID, in ID order
+func (r *TopicRepository) ListSubscriptionsAfter(ctx context.Context, topic string, afterID int64, limit int) ([]*domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM topic_subscriptions
+		WHERE topic = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`
+
+	return r.scanSubscriptions(ctx, query, topic, afterID, limit)
+}
+
+// Helper functions
+
+func (r *TopicRepository) scanSubscriptions(ctx context.Context, query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*domain.Subscription, 0)
+	for rows.Next() {
+		s := &domain.Subscription{}
+		var channels []string
+		if err := rows.Scan(&s.ID, &s.Topic, &s.UserID, &channels, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		s.Channels = make([]domain.Channel, 0, len(channels))
+		for _, c := range channels {
+			s.Channels = append(s.Channels, domain.Channel(c))
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func channelStrings(channels []domain.Channel) []string {
+	values := make([]string, 0, len(channels))
+	for _, c := range channels {
+		values = append(values, string(c))
+	}
+	return values
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// TrackingRepository implements domain.TrackingRepository using PostgreSQL
+type TrackingRepository struct {
+	db *DB
+}
+
+// NewTrackingRepository creates a new TrackingRepository
+func NewTrackingRepository(db *DB) *TrackingRepository {
+	return &TrackingRepository{db: db}
+}
+
+// RecordEvent stores a tracking event and increments the notification's
+// open or click counter in the same transaction
+func (r *TrackingRepository) RecordEvent(ctx context.Context, e *domain.TrackingEvent) error {
+	var counter string
+	switch e.Type {
+	case domain.TrackingEventOpen:
+		counter = "opens"
+	case domain.TrackingEventClick:
+		counter = "clicks"
+	default:
+		return fmt.Errorf("unknown tracking event type %q", e.Type)
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		`UPDATE notifications SET `+counter+` = `+counter+` + 1 WHERE id = $1`,
+		e.NotificationID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update engagement counter: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	query := `
+		INSERT INTO tracking_events (id, notification_id, type, url, user_agent, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		e.ID, e.NotificationID, e.Type, e.URL, e.UserAgent, e.IPAddress, e.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create tracking event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetBatchEngagement aggregates the engagement counters of the emails in a batch
+func (r *TrackingRepository) GetBatchEngagement(ctx context.Context, batchID uuid.UUID) (*domain.BatchEngagement, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE opens > 0),
+			COUNT(*) FILTER (WHERE clicks > 0),
+			COALESCE(SUM(opens), 0),
+			COALESCE(SUM(clicks), 0)
+		FROM notifications
+		WHERE batch_id = $1 AND channel = 'email'
+	`
+
+	e := &domain.BatchEngagement{BatchID: batchID}
+	err := r.db.Pool.QueryRow(ctx, query, batchID).Scan(
+		&e.Emails, &e.Opened, &e.Clicked, &e.Opens, &e.Clicks,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch engagement: %w", err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const (
+	dedupKeyPrefix = "notification:dedup:"
+)
+
+// releaseScript deletes a dedup key only if it still holds the given
+// notification ID, so a release never removes a later notification's entry
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DedupStore implements domain.DedupStore with one expiring Redis key per
+// content hash
+type DedupStore struct {
+	client *Client
+}
+
+// NewDedupStore creates a new DedupStore
+func NewDedupStore(client *Client) *DedupStore {
+	return &DedupStore{client: client}
+}
+
+// dedupKey returns the Redis key for a content hash
+func dedupKey(key string) string {
+	return dedupKeyPrefix + key
+}
+
+// Claim sets each entry's key with SET NX GET, which stores the key and
+// reads the ID already stored in a single atomic command
+func (s *DedupStore) Claim(ctx context.Context, entries []*domain.DedupEntry) ([]uuid.UUID, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	pipe := s.client.client.Pipeline()
+	cmds := make([]*redis.StatusCmd, 0, len(entries))
+	for _, e := range entries {
+		cmds = append(cmds, pipe.SetArgs(ctx, dedupKey(e.Key), e.NotificationID.String(), redis.SetArgs{
+			Mode: "NX",
+			TTL:  e.TTL,
+			Get:  true,
+		}))
+	}
+
+	// A key that was stored replies nil, which Exec reports as redis.Nil
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to claim dedup keys: %w", err)
+	}
+
+	existing := make([]uuid.UUID, len(entries))
+	for i, cmd := range cmds {
+		value, err := cmd.Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim dedup key: %w", err)
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification ID in dedup key: %w", err)
+		}
+		existing[i] = id
+	}
+
+	return existing, nil
+}
+
+// Release deletes the keys of entries that still hold their notification ID
+func (s *DedupStore) Release(ctx context.Context, entries []*domain.DedupEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// EVALSHA cannot fall back to EVAL inside a pipeline, so send the script
+	pipe := s.client.client.Pipeline()
+	for _, e := range entries {
+		releaseScript.Eval(ctx, pipe, []string{dedupKey(e.Key)}, e.NotificationID.String())
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to release dedup keys: %w", err)
+	}
+
+	return nil
+}
@@ -110,6 +110,56 @@ func (q *Queue) Dequeue(ctx context.Context, channel domain.Channel) (*domain.Qu
 	return &item, nil
 }
 
+// Remove removes an item from the queue. Items are stored as their JSON
+// encoding, so item must match the enqueued item field for field.
+func (q *Queue) Remove(ctx context.Context, item *domain.QueueItem) (bool, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal queue item: %w", err)
+	}
+
+	removed, err := q.client.client.ZRem(ctx, queueKey(item.Channel), string(data)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove queue item: %w", err)
+	}
+
+	return removed > 0, nil
+}
+
+// RemoveBatch removes multiple items from the queue
+func (q *Queue) RemoveBatch(ctx context.Context, items []*domain.QueueItem) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	// Group members by channel
+	channelMembers := make(map[domain.Channel][]any)
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal queue item: %w", err)
+		}
+		channelMembers[item.Channel] = append(channelMembers[item.Channel], string(data))
+	}
+
+	pipe := q.client.client.Pipeline()
+	cmds := make([]*redis.IntCmd, 0, len(channelMembers))
+	for channel, members := range channelMembers {
+		cmds = append(cmds, pipe.ZRem(ctx, queueKey(channel), members...))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to remove batch: %w", err)
+	}
+
+	var removed int64
+	for _, cmd := range cmds {
+		removed += cmd.Val()
+	}
+
+	return removed, nil
+}
+
 // GetQueueDepth returns the number of items in the queue for a channel
 func (q *Queue) GetQueueDepth(ctx context.Context, channel domain.Channel) (int64, error) {
 	key := queueKey(channel)
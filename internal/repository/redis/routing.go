@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const (
+	routingKey = "provider:routes"
+)
+
+// RoutingStore implements domain.RoutingStore using a Redis hash keyed by channel
+type RoutingStore struct {
+	client *Client
+}
+
+// NewRoutingStore creates a new RoutingStore
+func NewRoutingStore(client *Client) *RoutingStore {
+	return &RoutingStore{client: client}
+}
+
+// GetRoutes returns the stored route weights for all channels
+func (s *RoutingStore) GetRoutes(ctx context.Context) (map[domain.Channel][]domain.RouteWeight, error) {
+	values, err := s.client.client.HGetAll(ctx, routingKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routes: %w", err)
+	}
+
+	routes := make(map[domain.Channel][]domain.RouteWeight, len(values))
+	for channel, data := range values {
+		var weights []domain.RouteWeight
+		if err := json.Unmarshal([]byte(data), &weights); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal routes for %s: %w", channel, err)
+		}
+		routes[domain.Channel(channel)] = weights
+	}
+
+	return routes, nil
+}
+
+// SetRoutes stores the route weights for a channel
+func (s *RoutingStore) SetRoutes(ctx context.Context, channel domain.Channel, weights []domain.RouteWeight) error {
+	data, err := json.Marshal(weights)
+	if err != nil {
+		return fmt.Errorf("failed to marshal routes: %w", err)
+	}
+
+	if err := s.client.client.HSet(ctx, routingKey, string(channel), string(data)).Err(); err != nil {
+		return fmt.Errorf("failed to set routes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const (
+	sandboxKey = "sandbox:messages"
+)
+
+// SandboxStore implements domain.SandboxStore using a capped Redis list,
+// so captured messages are shared by all instances
+type SandboxStore struct {
+	client   *Client
+	capacity int64
+}
+
+// NewSandboxStore creates a new SandboxStore holding up to capacity messages
+func NewSandboxStore(client *Client, capacity int) *SandboxStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SandboxStore{client: client, capacity: int64(capacity)}
+}
+
+// Add stores a message, trimming the oldest ones beyond capacity
+func (s *SandboxStore) Add(ctx context.Context, msg *domain.SandboxMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sandbox message: %w", err)
+	}
+
+	pipe := s.client.client.TxPipeline()
+	pipe.LPush(ctx, sandboxKey, string(data))
+	pipe.LTrim(ctx, sandboxKey, 0, s.capacity-1)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store sandbox message: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the newest messages first, optionally filtered by recipient
+func (s *SandboxStore) List(ctx context.Context, recipient string, limit int) ([]*domain.SandboxMessage, error) {
+	values, err := s.client.client.LRange(ctx, sandboxKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sandbox messages: %w", err)
+	}
+
+	result := make([]*domain.SandboxMessage, 0)
+	for _, value := range values {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+
+		var msg domain.SandboxMessage
+		if err := json.Unmarshal([]byte(value), &msg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sandbox message: %w", err)
+		}
+		if recipient != "" && msg.To != recipient {
+			continue
+		}
+		result = append(result, &msg)
+	}
+
+	return result, nil
+}
+
+// Clear removes all captured messages
+func (s *SandboxStore) Clear(ctx context.Context) error {
+	if err := s.client.client.Del(ctx, sandboxKey).Err(); err != nil {
+		return fmt.Errorf("failed to clear sandbox messages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// SetDedup enables content deduplication: a notification repeating the
+// rendered content sent to the same recipient on the same channel within
+// the channel's window is not created again. Notifications with an
+// idempotency key are deduplicated by the key instead.
+func (s *NotificationService) SetDedup(store domain.DedupStore, policy domain.DedupPolicy) {
+	s.dedupStore = store
+	s.dedup = policy
+}
+
+// dedupResult is the outcome of deduplicating notifications about to be
+// created
+type dedupResult struct {
+	// create are the notifications to store
+	create []*domain.Notification
+	// results are the notifications to respond with, in request order,
+	// with each duplicate replaced by the notification it repeats
+	results []*domain.Notification
+	// claimed are the dedup entries stored for create, released if storing
+	// them fails
+	claimed []*domain.DedupEntry
+}
+
+// applyDedup finds the notifications that repeat an earlier one, within
+// notifications or in the dedup store. In DedupReject mode a duplicate
+// fails with domain.ErrDuplicateContent. The dedup store is best effort:
+// when it cannot be reached every notification is created.
+func (s *NotificationService) applyDedup(ctx context.Context, notifications []*domain.Notification) (*dedupResult, error) {
+	result := &dedupResult{create: notifications, results: notifications}
+	if s.dedupStore == nil {
+		return result, nil
+	}
+
+	// Index of the first notification with each key, and of the notification
+	// each duplicate within the request repeats
+	first := make(map[string]int)
+	repeats := make(map[int]int)
+	entries := make([]*domain.DedupEntry, 0, len(notifications))
+	entryIndex := make([]int, 0, len(notifications))
+
+	for i, n := range notifications {
+		window, ok := s.dedup.Window(n.Channel)
+		if !ok || n.IdempotencyKey != nil {
+			continue
+		}
+		key := domain.DedupKey(n)
+		if j, ok := first[key]; ok {
+			if s.dedup.Mode == domain.DedupReject {
+				return nil, fmt.Errorf("notification %d: %w: same as notification %d", i, domain.ErrDuplicateContent, j)
+			}
+			repeats[i] = j
+			continue
+		}
+		first[key] = i
+		entries = append(entries, &domain.DedupEntry{Key: key, NotificationID: n.ID, TTL: window})
+		entryIndex = append(entryIndex, i)
+	}
+	if len(entries) == 0 {
+		return result, nil
+	}
+
+	existing, err := s.dedupStore.Claim(ctx, entries)
+	if err != nil {
+		s.logger.Warn("failed to check duplicate notifications", "error", err)
+		return result, nil
+	}
+
+	originals := make(map[int]uuid.UUID)
+	for k, id := range existing {
+		if id == uuid.Nil {
+			result.claimed = append(result.claimed, entries[k])
+			continue
+		}
+		originals[entryIndex[k]] = id
+	}
+	if len(originals) == 0 && len(repeats) == 0 {
+		return result, nil
+	}
+
+	if s.dedup.Mode == domain.DedupReject {
+		s.releaseDedup(ctx, result.claimed)
+		for i := range notifications {
+			if id, ok := originals[i]; ok {
+				return nil, duplicateError(len(notifications), i, id)
+			}
+		}
+	}
+
+	result.create = make([]*domain.Notification, 0, len(notifications))
+	result.results = make([]*domain.Notification, len(notifications))
+	for i, n := range notifications {
+		if id, ok := originals[i]; ok {
+			original, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				s.releaseDedup(ctx, result.claimed)
+				// The original is not stored yet or its request failed
+				if errors.Is(err, domain.ErrNotFound) {
+					return nil, duplicateError(len(notifications), i, id)
+				}
+				return nil, fmt.Errorf("failed to get original notification: %w", err)
+			}
+			s.logger.Info("duplicate notification",
+				"notification_id", original.ID,
+				"channel", n.Channel,
+			)
+			result.results[i] = original
+			continue
+		}
+		if j, ok := repeats[i]; ok {
+			result.results[i] = result.results[j]
+			continue
+		}
+		result.create = append(result.create, n)
+		result.results[i] = n
+	}
+
+	return result, nil
+}
+
+// duplicateError returns the error of the notification at index i of a
+// request of count notifications that repeats the notification id
+func duplicateError(count, i int, id uuid.UUID) error {
+	err := domain.DuplicateError{NotificationID: id}
+	if count == 1 {
+		return err
+	}
+	return fmt.Errorf("notification %d: %w", i, err)
+}
+
+// releaseDedup removes the dedup entries of notifications that were not
+// created, so a retry of the request is not reported as a duplicate
+func (s *NotificationService) releaseDedup(ctx context.Context, entries []*domain.DedupEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := s.dedupStore.Release(ctx, entries); err != nil {
+		s.logger.Warn("failed to release dedup entries", "error", err)
+	}
+}
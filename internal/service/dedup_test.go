@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockDedupStore is a mock implementation of domain.DedupStore
+type MockDedupStore struct {
+	mock.Mock
+}
+
+func (m *MockDedupStore) Claim(ctx context.Context, entries []*domain.DedupEntry) ([]uuid.UUID, error) {
+	args := m.Called(ctx, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockDedupStore) Release(ctx context.Context, entries []*domain.DedupEntry) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+func TestNotificationService_CreateDuplicate(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	policy := domain.DedupPolicy{
+		Windows: map[domain.Channel]time.Duration{domain.ChannelSMS: time.Minute},
+		Mode:    domain.DedupReturnOriginal,
+	}
+	req := CreateRequest{Recipient: "+905551234567", Channel: domain.ChannelSMS, Content: "Your code is 1234"}
+
+	setup := func(policy domain.DedupPolicy) (*NotificationService, *MockNotificationRepository, *MockQueue, *MockDedupStore) {
+		repo := new(MockNotificationRepository)
+		queue := new(MockQueue)
+		store := new(MockDedupStore)
+		svc := NewNotificationService(repo, new(MockTemplateRepository), queue, logger)
+		svc.SetDedup(store, policy)
+		return svc, repo, queue, store
+	}
+
+	t.Run("creates the first notification", func(t *testing.T) {
+		svc, repo, queue, store := setup(policy)
+
+		store.On("Claim", ctx, mock.MatchedBy(func(entries []*domain.DedupEntry) bool {
+			return len(entries) == 1 && entries[0].TTL == time.Minute
+		})).Return([]uuid.UUID{uuid.Nil}, nil)
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil)
+		repo.On("Update", ctx, mock.Anything).Return(nil)
+
+		notification, err := svc.Create(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusQueued, notification.Status)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("returns the original of a duplicate", func(t *testing.T) {
+		svc, repo, _, store := setup(policy)
+		original := domain.NewNotification(req.Recipient, req.Channel, req.Content)
+
+		store.On("Claim", ctx, mock.Anything).Return([]uuid.UUID{original.ID}, nil)
+		repo.On("GetByID", ctx, original.ID).Return(original, nil)
+
+		notification, err := svc.Create(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, original, notification)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a duplicate", func(t *testing.T) {
+		svc, repo, _, store := setup(domain.DedupPolicy{Windows: policy.Windows, Mode: domain.DedupReject})
+		originalID := uuid.New()
+
+		store.On("Claim", ctx, mock.Anything).Return([]uuid.UUID{originalID}, nil)
+
+		_, err := svc.Create(ctx, req)
+
+		var duplicateErr domain.DuplicateError
+		require.ErrorAs(t, err, &duplicateErr)
+		assert.Equal(t, originalID, duplicateErr.NotificationID)
+		assert.ErrorIs(t, err, domain.ErrDuplicateContent)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("releases the entry when the notification is not stored", func(t *testing.T) {
+		svc, repo, _, store := setup(policy)
+
+		store.On("Claim", ctx, mock.Anything).Return([]uuid.UUID{uuid.Nil}, nil)
+		repo.On("Create", ctx, mock.Anything).Return(errors.New("connection refused"))
+		store.On("Release", ctx, mock.MatchedBy(func(entries []*domain.DedupEntry) bool {
+			return len(entries) == 1
+		})).Return(nil)
+
+		_, err := svc.Create(ctx, req)
+
+		require.Error(t, err)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("skips notifications with an idempotency key or no window", func(t *testing.T) {
+		svc, repo, queue, store := setup(policy)
+		key := "order-42"
+
+		repo.On("GetByIdempotencyKey", ctx, key).Return(nil, domain.ErrNotFound)
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil)
+		repo.On("Update", ctx, mock.Anything).Return(nil)
+
+		_, err := svc.Create(ctx, CreateRequest{Recipient: req.Recipient, Channel: req.Channel, Content: req.Content, IdempotencyKey: &key})
+		require.NoError(t, err)
+		_, err = svc.Create(ctx, CreateRequest{Recipient: "user@example.com", Channel: domain.ChannelEmail, Content: "Hi"})
+		require.NoError(t, err)
+
+		store.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_CreateBatchDuplicates(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	windows := map[domain.Channel]time.Duration{domain.ChannelSMS: time.Minute}
+	reqs := []CreateRequest{
+		{Recipient: "+905551234567", Channel: domain.ChannelSMS, Content: "Your code is 1234"},
+		{Recipient: "+905551234567", Channel: domain.ChannelSMS, Content: "Your code is 1234"},
+		{Recipient: "+905559876543", Channel: domain.ChannelSMS, Content: "Your code is 1234"},
+	}
+
+	t.Run("creates each content once and returns the originals", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		queue := new(MockQueue)
+		store := new(MockDedupStore)
+		svc := NewNotificationService(repo, new(MockTemplateRepository), queue, logger)
+		svc.SetDedup(store, domain.DedupPolicy{Windows: windows, Mode: domain.DedupReturnOriginal})
+		original := domain.NewNotification("+905559876543", domain.ChannelSMS, "Your code is 1234")
+
+		store.On("Claim", ctx, mock.MatchedBy(func(entries []*domain.DedupEntry) bool {
+			return len(entries) == 2
+		})).Return([]uuid.UUID{uuid.Nil, original.ID}, nil)
+		repo.On("GetByID", ctx, original.ID).Return(original, nil)
+		repo.On("CreateBatch", ctx, mock.MatchedBy(func(n []*domain.Notification) bool {
+			return len(n) == 1
+		})).Return(nil)
+		queue.On("EnqueueBatch", ctx, mock.Anything).Return(nil)
+
+		notifications, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: reqs})
+
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+		assert.Same(t, notifications[0], notifications[1])
+		assert.Equal(t, original, notifications[2])
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a duplicate within the batch", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		store := new(MockDedupStore)
+		svc := NewNotificationService(repo, new(MockTemplateRepository), new(MockQueue), logger)
+		svc.SetDedup(store, domain.DedupPolicy{Windows: windows, Mode: domain.DedupReject})
+
+		_, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: reqs})
+
+		assert.ErrorIs(t, err, domain.ErrDuplicateContent)
+		store.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+}
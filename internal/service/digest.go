@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// DigestService handles digest rules and merges held notifications into
+// digests once they are due
+type DigestService struct {
+	repo          domain.DigestRuleRepository
+	templateRepo  domain.TemplateRepository
+	notifications *NotificationService
+	logger        *slog.Logger
+	batchSize     int
+}
+
+// NewDigestService creates a new DigestService
+func NewDigestService(
+	repo domain.DigestRuleRepository,
+	templateRepo domain.TemplateRepository,
+	notifications *NotificationService,
+	logger *slog.Logger,
+) *DigestService {
+	return &DigestService{
+		repo:          repo,
+		templateRepo:  templateRepo,
+		notifications: notifications,
+		logger:        logger,
+		batchSize:     100,
+	}
+}
+
+// CreateDigestRuleRequest represents a request to create a digest rule
+type CreateDigestRuleRequest struct {
+	Category     string         `json:"category"`
+	Channel      domain.Channel `json:"channel"`
+	Window       int            `json:"window"`
+	MaxCount     int            `json:"max_count"`
+	TemplateName string         `json:"template_name"`
+}
+
+// UpdateDigestRuleRequest represents a request to update a digest rule.
+// The category and channel of a rule cannot be changed.
+type UpdateDigestRuleRequest struct {
+	Window       *int    `json:"window,omitempty"`
+	MaxCount     *int    `json:"max_count,omitempty"`
+	TemplateName *string `json:"template_name,omitempty"`
+}
+
+// Create creates a digest rule. Notifications of the rule's category and
+// channel created from then on are held until their digest is sent.
+func (s *DigestService) Create(ctx context.Context, req CreateDigestRuleRequest) (*domain.DigestRule, error) {
+	rule := domain.NewDigestRule(req.Category, req.Channel, req.Window, req.MaxCount, req.TemplateName)
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create digest rule: %w", err)
+	}
+
+	s.logger.Info("digest rule created",
+		"digest_rule_id", rule.ID,
+		"category", rule.Category,
+		"channel", rule.Channel,
+	)
+
+	return rule, nil
+}
+
+// GetByID retrieves a digest rule by ID
+func (s *DigestService) GetByID(ctx context.Context, id uuid.UUID) (*domain.DigestRule, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List lists all digest rules
+func (s *DigestService) List(ctx context.Context) ([]*domain.DigestRule, error) {
+	return s.repo.List(ctx)
+}
+
+// Update updates the window, max count or template of a digest rule. The
+// change applies to notifications that are already held.
+func (s *DigestService) Update(ctx context.Context, id uuid.UUID, req UpdateDigestRuleRequest) (*domain.DigestRule, error) {
+	rule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Window != nil {
+		rule.Window = *req.Window
+	}
+	if req.MaxCount != nil {
+		rule.MaxCount = *req.MaxCount
+	}
+	if req.TemplateName != nil {
+		rule.TemplateName = *req.TemplateName
+	}
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update digest rule: %w", err)
+	}
+
+	s.logger.Info("digest rule updated", "digest_rule_id", rule.ID)
+
+	return rule, nil
+}
+
+// Delete deletes a digest rule. Notifications it holds are released on the
+// next flush and sent on their own.
+func (s *DigestService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("digest rule deleted", "digest_rule_id", id)
+
+	return nil
+}
+
+// FlushDue sends the digests that are due at now. A group holding a single
+// notification, or whose rule was deleted or whose template cannot be
+// found, is released instead: its notifications are queued on their own.
+// Originals are marked digested with versioned updates in the same
+// transaction that creates the digest, so replicas flushing concurrently
+// send every digest once.
+func (s *DigestService) FlushDue(ctx context.Context, now time.Time) int {
+	groups, err := s.repo.ListDue(ctx, now, s.batchSize)
+	if err != nil {
+		s.logger.Error("failed to get due digests", "error", err)
+		return 0
+	}
+
+	flushed := 0
+	for _, group := range groups {
+		if err := s.flush(ctx, group, now); err != nil {
+			// Another replica flushed the group first
+			if errors.Is(err, domain.ErrVersionConflict) {
+				continue
+			}
+			s.logger.Error("failed to flush digest",
+				"recipient", group.Recipient,
+				"channel", group.Channel,
+				"category", group.Category,
+				"error", err,
+			)
+			continue
+		}
+		flushed++
+	}
+
+	return flushed
+}
+
+func (s *DigestService) flush(ctx context.Context, group *domain.DigestGroup, now time.Time) error {
+	rule, err := s.repo.GetFor(ctx, group.Category, group.Channel)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("failed to get digest rule: %w", err)
+	}
+
+	limit := domain.MaxDigestMaxCount
+	if rule != nil {
+		limit = rule.MaxCount
+	}
+	held, err := s.notifications.repo.ListHeld(ctx, *group, limit)
+	if err != nil {
+		return fmt.Errorf("failed to get held notifications: %w", err)
+	}
+	held, err = s.expireHeld(ctx, held, now)
+	if err != nil {
+		return err
+	}
+	if len(held) == 0 {
+		return nil
+	}
+
+	if rule == nil || len(held) == 1 {
+		return s.release(ctx, held)
+	}
+
+	template, err := s.templateRepo.GetByName(ctx, rule.TemplateName)
+	if err != nil {
+		s.logger.Warn("digest template not found, releasing held notifications",
+			"digest_rule_id", rule.ID,
+			"template_name", rule.TemplateName,
+			"error", err,
+		)
+		return s.release(ctx, held)
+	}
+
+	digest := newDigest(group, template, held)
+	if err := s.notifications.applySendWindow(digest, digest.TimeZone); err != nil {
+		return err
+	}
+	for _, n := range held {
+		if err := n.MarkAsDigested(digest.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.notifications.repo.Digest(ctx, digest, held); err != nil {
+		return err
+	}
+
+	if digest.Status == domain.StatusPending {
+		if err := s.notifications.enqueueNotification(ctx, digest); err != nil {
+			s.logger.Error("failed to enqueue digest",
+				"notification_id", digest.ID,
+				"error", err,
+			)
+		}
+	}
+
+	for _, n := range held {
+		s.notifications.broadcastStatus(n)
+	}
+	s.notifications.broadcastStatus(digest)
+
+	s.logger.Info("digest created",
+		"notification_id", digest.ID,
+		"digest_rule_id", rule.ID,
+		"count", len(held),
+	)
+
+	return nil
+}
+
+// release queues held notifications on their own. Each is recorded as
+// queued before it is enqueued, since a worker skips a notification that is
+// still held and would drop its queue entry.
+func (s *DigestService) release(ctx context.Context, held []*domain.Notification) error {
+	for _, n := range held {
+		if err := n.MarkAsQueued(); err != nil {
+			return err
+		}
+		if err := s.notifications.repo.Update(ctx, n); err != nil {
+			// The notification was cancelled since it was listed
+			if errors.Is(err, domain.ErrVersionConflict) {
+				continue
+			}
+			return fmt.Errorf("failed to release held notification %s: %w", n.ID, err)
+		}
+		if err := s.notifications.queue.Enqueue(ctx, queueItem(n)); err != nil {
+			s.notifications.failUnqueued(ctx, n, "held notification could not be queued: "+err.Error())
+			s.notifications.broadcastStatus(n)
+			return fmt.Errorf("failed to release held notification %s: %w", n.ID, err)
+		}
+		s.notifications.broadcastStatus(n)
+	}
+	return nil
+}
+
+// expireHeld marks the held notifications whose expiry passed during the
+// window as expired and returns the others, which are still to be sent
+func (s *DigestService) expireHeld(ctx context.Context, held []*domain.Notification, now time.Time) ([]*domain.Notification, error) {
+	remaining := make([]*domain.Notification, 0, len(held))
+	for _, n := range held {
+		if !n.IsExpired(now) {
+			remaining = append(remaining, n)
+			continue
+		}
+		if err := n.MarkAsExpired(); err != nil {
+			return nil, err
+		}
+		if err := s.notifications.repo.Update(ctx, n); err != nil {
+			// The notification was cancelled since it was listed
+			if errors.Is(err, domain.ErrVersionConflict) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to expire held notification %s: %w", n.ID, err)
+		}
+		s.notifications.broadcastStatus(n)
+	}
+	return remaining, nil
+}
+
+// validate checks the rule and that its template exists and only uses
+// digest variables
+func (s *DigestService) validate(ctx context.Context, rule *domain.DigestRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	template, err := s.templateRepo.GetByName(ctx, rule.TemplateName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrTemplateNotFound
+		}
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return domain.ValidateDigestTemplate(template)
+}
+
+// newDigest renders the digest notification merging held, which is ordered
+// oldest first. It takes the highest priority and the latest time zone of
+// the held notifications.
+func newDigest(group *domain.DigestGroup, template *domain.Template, held []*domain.Notification) *domain.Notification {
+	content := template.Render(domain.DigestVars(group.Category, held))
+	if maxLen := maxContentLength(group.Channel); len(content) > maxLen {
+		// Drop a rune cut in half by the truncation
+		content = strings.ToValidUTF8(content[:maxLen], "")
+	}
+
+	digest := domain.NewNotification(group.Recipient, group.Channel, content)
+	digest.Category = group.Category
+	digest.Metadata = map[string]any{domain.DigestCountMetadataKey: len(held)}
+	digest.Priority = domain.PriorityLow
+	for _, n := range held {
+		if n.Priority.Weight() < digest.Priority.Weight() {
+			digest.Priority = n.Priority
+		}
+	}
+	digest.TimeZone = held[len(held)-1].TimeZone
+	if template.TrackingDisabled {
+		digest.DisableTracking()
+	}
+
+	return digest
+}
+
+// applyDigests holds the pending notifications that match a digest rule
+func applyDigests(ctx context.Context, repo domain.DigestRuleRepository, notifications []*domain.Notification) error {
+	type ruleKey struct {
+		category string
+		channel  domain.Channel
+	}
+	matches := make(map[ruleKey]bool)
+
+	for _, n := range notifications {
+		if n.Status != domain.StatusPending || n.Category == "" {
+			continue
+		}
+
+		key := ruleKey{n.Category, n.Channel}
+		match, ok := matches[key]
+		if !ok {
+			_, err := repo.GetFor(ctx, n.Category, n.Channel)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("failed to check digest rules: %w", err)
+			}
+			match = err == nil
+			matches[key] = match
+		}
+
+		if match {
+			if err := n.MarkAsHeld(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockDigestRuleRepository is a mock implementation of domain.DigestRuleRepository
+type MockDigestRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestRuleRepository) Create(ctx context.Context, rule *domain.DigestRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockDigestRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DigestRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestRule), args.Error(1)
+}
+
+func (m *MockDigestRuleRepository) GetFor(ctx context.Context, category string, channel domain.Channel) (*domain.DigestRule, error) {
+	args := m.Called(ctx, category, channel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestRule), args.Error(1)
+}
+
+func (m *MockDigestRuleRepository) List(ctx context.Context) ([]*domain.DigestRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DigestRule), args.Error(1)
+}
+
+func (m *MockDigestRuleRepository) Update(ctx context.Context, rule *domain.DigestRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockDigestRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDigestRuleRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.DigestGroup, error) {
+	args := m.Called(ctx, t, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DigestGroup), args.Error(1)
+}
+
+func TestDigestService_Create(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("rejects templates using other variables", func(t *testing.T) {
+		rules := new(MockDigestRuleRepository)
+		templates := new(MockTemplateRepository)
+		svc := NewDigestService(rules, templates, nil, logger)
+
+		templates.On("GetByName", ctx, "comment_digest").
+			Return(domain.NewTemplate("comment_digest", domain.ChannelPush, "{{count}} new comments from {{author}}"), nil)
+
+		_, err := svc.Create(ctx, CreateDigestRuleRequest{
+			Category: "comment", Channel: domain.ChannelPush, Window: 300, MaxCount: 20, TemplateName: "comment_digest",
+		})
+
+		var validationErr domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "template_name", validationErr.Field)
+		rules.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates a rule", func(t *testing.T) {
+		rules := new(MockDigestRuleRepository)
+		templates := new(MockTemplateRepository)
+		svc := NewDigestService(rules, templates, nil, logger)
+
+		templates.On("GetByName", ctx, "comment_digest").
+			Return(domain.NewTemplate("comment_digest", domain.ChannelPush, "{{count}} new comments"), nil)
+		rules.On("Create", ctx, mock.Anything).Return(nil)
+
+		rule, err := svc.Create(ctx, CreateDigestRuleRequest{
+			Category: "comment", Channel: domain.ChannelPush, Window: 300, MaxCount: 20, TemplateName: "comment_digest",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 300, rule.Window)
+		rules.AssertExpectations(t)
+	})
+}
+
+func TestNotificationService_CreateHeld(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mockRepo := new(MockNotificationRepository)
+	mockQueue := new(MockQueue)
+	rules := new(MockDigestRuleRepository)
+
+	svc := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+	svc.SetDigests(rules)
+
+	rules.On("GetFor", ctx, "comment", domain.ChannelPush).
+		Return(domain.NewDigestRule("comment", domain.ChannelPush, 300, 20, "comment_digest"), nil).Once()
+	mockRepo.On("CreateBatch", ctx, mock.Anything).Return(nil)
+	mockQueue.On("EnqueueBatch", ctx, mock.MatchedBy(func(items []*domain.QueueItem) bool {
+		return len(items) == 1
+	})).Return(nil)
+
+	notifications, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: []CreateRequest{
+		{Recipient: "device-token-1", Channel: domain.ChannelPush, Content: "Ayse commented", Category: "comment"},
+		{Recipient: "device-token-1", Channel: domain.ChannelPush, Content: "Mehmet commented", Category: "comment"},
+		{Recipient: "device-token-1", Channel: domain.ChannelPush, Content: "Your order shipped"},
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusHeld, notifications[0].Status)
+	assert.Equal(t, domain.StatusHeld, notifications[1].Status)
+	assert.Equal(t, domain.StatusQueued, notifications[2].Status)
+	rules.AssertExpectations(t)
+	mockQueue.AssertExpectations(t)
+}
+
+func TestDigestService_FlushDue(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	now := time.Now().UTC()
+
+	group := &domain.DigestGroup{Recipient: "device-token-1", Channel: domain.ChannelPush, Category: "comment"}
+	rule := domain.NewDigestRule("comment", domain.ChannelPush, 300, 20, "comment_digest")
+	template := domain.NewTemplate("comment_digest", domain.ChannelPush, "{{count}} new comments, latest: {{latest}}")
+
+	held := func(contents ...string) []*domain.Notification {
+		notifications := make([]*domain.Notification, 0, len(contents))
+		for _, content := range contents {
+			n := domain.NewNotification(group.Recipient, group.Channel, content)
+			n.Category = group.Category
+			require.NoError(t, n.MarkAsHeld())
+			notifications = append(notifications, n)
+		}
+		return notifications
+	}
+
+	setup := func() (*DigestService, *MockDigestRuleRepository, *MockTemplateRepository, *MockNotificationRepository, *MockQueue) {
+		rules := new(MockDigestRuleRepository)
+		templates := new(MockTemplateRepository)
+		notificationRepo := new(MockNotificationRepository)
+		queue := new(MockQueue)
+		notifications := NewNotificationService(notificationRepo, templates, queue, logger)
+		return NewDigestService(rules, templates, notifications, logger), rules, templates, notificationRepo, queue
+	}
+
+	t.Run("merges held notifications into a digest", func(t *testing.T) {
+		svc, rules, templates, notificationRepo, queue := setup()
+		originals := held("Ayse commented", "Mehmet commented")
+		originals[1].Priority = domain.PriorityHigh
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		templates.On("GetByName", ctx, "comment_digest").Return(template, nil)
+		var digest *domain.Notification
+		notificationRepo.On("Digest", ctx, mock.MatchedBy(func(d *domain.Notification) bool {
+			return d.Content == "2 new comments, latest: Mehmet commented" &&
+				d.Priority == domain.PriorityHigh &&
+				d.Metadata[domain.DigestCountMetadataKey] == 2
+		}), originals).Run(func(args mock.Arguments) {
+			digest = args.Get(1).(*domain.Notification)
+		}).Return(nil)
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil)
+		notificationRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+		assert.Equal(t, 1, svc.FlushDue(ctx, now))
+
+		require.NotNil(t, digest)
+		for _, n := range originals {
+			assert.Equal(t, domain.StatusDigested, n.Status)
+			assert.Equal(t, digest.ID, *n.DigestID)
+		}
+		assert.Equal(t, domain.StatusQueued, digest.Status)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("releases a single held notification", func(t *testing.T) {
+		svc, rules, templates, notificationRepo, queue := setup()
+		originals := held("Ayse commented")
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil)
+		notificationRepo.On("Update", ctx, originals[0]).Return(nil)
+
+		assert.Equal(t, 1, svc.FlushDue(ctx, now))
+
+		assert.Equal(t, domain.StatusQueued, originals[0].Status)
+		templates.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+		notificationRepo.AssertNotCalled(t, "Digest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("records a released notification as queued before enqueuing it", func(t *testing.T) {
+		svc, rules, _, notificationRepo, queue := setup()
+		originals := held("Ayse commented")
+
+		var persisted domain.Status
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		notificationRepo.On("Update", ctx, originals[0]).Run(func(args mock.Arguments) {
+			persisted = args.Get(1).(*domain.Notification).Status
+		}).Return(nil).Once()
+		// A worker dequeuing right away sees the stored status
+		queue.On("Enqueue", ctx, mock.Anything).Run(func(mock.Arguments) {
+			assert.True(t, persisted.CanTransitionTo(domain.StatusProcessing))
+		}).Return(nil).Once()
+
+		assert.Equal(t, 1, svc.FlushDue(ctx, now))
+
+		assert.Equal(t, domain.StatusQueued, persisted)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("fails a released notification that cannot be enqueued", func(t *testing.T) {
+		svc, rules, _, notificationRepo, queue := setup()
+		originals := held("Ayse commented")
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		notificationRepo.On("Update", ctx, originals[0]).Return(nil).Twice()
+		queue.On("Enqueue", ctx, mock.Anything).Return(errors.New("redis down")).Once()
+
+		assert.Equal(t, 0, svc.FlushDue(ctx, now))
+
+		assert.Equal(t, domain.StatusFailed, originals[0].Status)
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("expires notifications whose expiry passed while held", func(t *testing.T) {
+		svc, rules, templates, notificationRepo, queue := setup()
+		originals := held("Ayse commented", "Mehmet commented")
+		expiresAt := now.Add(-time.Minute)
+		originals[0].ExpiresAt = &expiresAt
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		notificationRepo.On("Update", ctx, originals[0]).Return(nil).Once()
+		notificationRepo.On("Update", ctx, originals[1]).Return(nil).Once()
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil).Once()
+
+		assert.Equal(t, 1, svc.FlushDue(ctx, now))
+
+		assert.Equal(t, domain.StatusExpired, originals[0].Status)
+		assert.Equal(t, domain.StatusQueued, originals[1].Status)
+		templates.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+		notificationRepo.AssertNotCalled(t, "Digest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("releases notifications held by a deleted rule", func(t *testing.T) {
+		svc, rules, _, notificationRepo, queue := setup()
+		originals := held("Ayse commented", "Mehmet commented")
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(nil, domain.ErrNotFound)
+		notificationRepo.On("ListHeld", ctx, *group, domain.MaxDigestMaxCount).Return(originals, nil)
+		queue.On("Enqueue", ctx, mock.Anything).Return(nil).Twice()
+		notificationRepo.On("Update", ctx, mock.Anything).Return(nil).Twice()
+
+		svc.FlushDue(ctx, now)
+
+		for _, n := range originals {
+			assert.Equal(t, domain.StatusQueued, n.Status)
+		}
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("skips a group flushed by another replica", func(t *testing.T) {
+		svc, rules, templates, notificationRepo, queue := setup()
+		originals := held("Ayse commented", "Mehmet commented")
+
+		rules.On("ListDue", ctx, now, 100).Return([]*domain.DigestGroup{group}, nil)
+		rules.On("GetFor", ctx, "comment", domain.ChannelPush).Return(rule, nil)
+		notificationRepo.On("ListHeld", ctx, *group, 20).Return(originals, nil)
+		templates.On("GetByName", ctx, "comment_digest").Return(template, nil)
+		notificationRepo.On("Digest", ctx, mock.Anything, originals).Return(domain.ErrVersionConflict)
+
+		assert.Equal(t, 0, svc.FlushDue(ctx, now))
+		queue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// InboundService stores inbound messages, applies opt-out and opt-in
+// keywords to the suppression list and forwards messages to the customer
+type InboundService struct {
+	repo         domain.InboundRepository
+	suppressions domain.SuppressionRepository
+	keywords     *domain.InboundKeywords
+	forwarder    domain.InboundForwarder
+	logger       *slog.Logger
+	phoneRegion  string
+}
+
+// NewInboundService creates a new InboundService
+func NewInboundService(
+	repo domain.InboundRepository,
+	suppressions domain.SuppressionRepository,
+	keywords *domain.InboundKeywords,
+	logger *slog.Logger,
+) *InboundService {
+	return &InboundService{
+		repo:         repo,
+		suppressions: suppressions,
+		keywords:     keywords,
+		logger:       logger,
+	}
+}
+
+// SetForwarder sets the forwarder inbound messages are delivered to
+func (s *InboundService) SetForwarder(forwarder domain.InboundForwarder) {
+	s.forwarder = forwarder
+}
+
+// SetPhoneRegion sets the region sender numbers given in national format
+// are read in
+func (s *InboundService) SetPhoneRegion(region string) {
+	s.phoneRegion = region
+}
+
+// InboundResult summarizes how a batch of inbound messages was handled
+type InboundResult struct {
+	Received   int `json:"received"`
+	Duplicates int `json:"duplicates"`
+	OptOuts    int `json:"opt_outs"`
+	OptIns     int `json:"opt_ins"`
+}
+
+// Receive handles messages posted by provider. Messages the provider
+// redelivers are skipped. Forwarding failures are logged and do not fail
+// the request, since the provider would otherwise redeliver the batch.
+func (s *InboundService) Receive(ctx context.Context, provider string, messages []*domain.InboundMessage) (*InboundResult, error) {
+	result := &InboundResult{Received: len(messages)}
+
+	for _, message := range messages {
+		message.Action = s.keywords.Classify(message.Body)
+
+		if err := s.repo.Create(ctx, message); err != nil {
+			if errors.Is(err, domain.ErrAlreadyExists) {
+				result.Duplicates++
+				continue
+			}
+			return nil, err
+		}
+
+		switch message.Action {
+		case domain.InboundActionOptOut:
+			if err := s.optOut(ctx, message); err != nil {
+				return nil, err
+			}
+			result.OptOuts++
+		case domain.InboundActionOptIn:
+			if err := s.optIn(ctx, message); err != nil {
+				return nil, err
+			}
+			result.OptIns++
+		}
+
+		s.forward(ctx, message)
+	}
+
+	s.logger.Info("inbound messages received",
+		"provider", provider,
+		"received", result.Received,
+		"opt_outs", result.OptOuts,
+		"opt_ins", result.OptIns,
+	)
+
+	return result, nil
+}
+
+// GetByID retrieves an inbound message by ID
+func (s *InboundService) GetByID(ctx context.Context, id uuid.UUID) (*domain.InboundMessage, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List lists inbound messages with filters
+func (s *InboundService) List(ctx context.Context, filter domain.InboundFilter) (*domain.InboundListResult, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// optOut suppresses SMS to the sender as an unsubscribe
+func (s *InboundService) optOut(ctx context.Context, message *domain.InboundMessage) error {
+	recipient := domain.SuppressionRecipient(domain.ChannelSMS, message.From, s.phoneRegion)
+	suppression := domain.NewSuppression(recipient, domain.ChannelSMS, domain.SuppressionUnsubscribe)
+	note := "inbound keyword: " + strings.ToUpper(strings.TrimSpace(message.Body))
+	suppression.Note = &note
+
+	if err := s.suppressions.Upsert(ctx, suppression); err != nil {
+		return err
+	}
+
+	s.logger.Info("recipient opted out by keyword", "inbound_id", message.ID)
+	return nil
+}
+
+// optIn lifts an unsubscribe for the sender. Suppressions for other reasons
+// such as bounces or manual blocks are kept.
+func (s *InboundService) optIn(ctx context.Context, message *domain.InboundMessage) error {
+	recipient := domain.SuppressionRecipient(domain.ChannelSMS, message.From, s.phoneRegion)
+
+	suppression, err := s.suppressions.Get(ctx, domain.ChannelSMS, recipient)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if suppression.Reason != domain.SuppressionUnsubscribe {
+		s.logger.Info("opt-in keeps suppression",
+			"inbound_id", message.ID,
+			"reason", suppression.Reason,
+		)
+		return nil
+	}
+
+	if err := s.suppressions.Delete(ctx, domain.ChannelSMS, recipient); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	s.logger.Info("recipient opted in by keyword", "inbound_id", message.ID)
+	return nil
+}
+
+func (s *InboundService) forward(ctx context.Context, message *domain.InboundMessage) {
+	if s.forwarder == nil {
+		return
+	}
+
+	if err := s.forwarder.Forward(ctx, message); err != nil {
+		s.logger.Error("failed to forward inbound message",
+			"inbound_id", message.ID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockInboundRepository is a mock implementation of domain.InboundRepository
+type MockInboundRepository struct {
+	mock.Mock
+}
+
+func (m *MockInboundRepository) Create(ctx context.Context, message *domain.InboundMessage) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+func (m *MockInboundRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.InboundMessage, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.InboundMessage), args.Error(1)
+}
+
+func (m *MockInboundRepository) List(ctx context.Context, filter domain.InboundFilter) (*domain.InboundListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.InboundListResult), args.Error(1)
+}
+
+// MockInboundForwarder is a mock implementation of domain.InboundForwarder
+type MockInboundForwarder struct {
+	mock.Mock
+}
+
+func (m *MockInboundForwarder) Forward(ctx context.Context, message *domain.InboundMessage) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+func TestInboundService_Receive(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	keywords := domain.NewInboundKeywords([]string{"STOP"}, []string{"START"})
+
+	t.Run("opt-out keyword suppresses the sender", func(t *testing.T) {
+		repo := new(MockInboundRepository)
+		suppressions := new(MockSuppressionRepository)
+		forwarder := new(MockInboundForwarder)
+
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+		suppressions.On("Upsert", ctx, mock.MatchedBy(func(s *domain.Suppression) bool {
+			return s.Channel == domain.ChannelSMS && s.Recipient == "+905551234567" &&
+				s.Reason == domain.SuppressionUnsubscribe
+		})).Return(nil).Once()
+		forwarder.On("Forward", ctx, mock.Anything).Return(errors.New("callback down"))
+
+		svc := NewInboundService(repo, suppressions, keywords, logger)
+		svc.SetForwarder(forwarder)
+
+		messages := []*domain.InboundMessage{
+			domain.NewInboundMessage("webhook", "+905551234567", "4455", "Stop"),
+			domain.NewInboundMessage("webhook", "+905551234568", "4455", "Where is my order?"),
+		}
+		result, err := svc.Receive(ctx, "webhook", messages)
+
+		require.NoError(t, err)
+		assert.Equal(t, &InboundResult{Received: 2, OptOuts: 1}, result)
+		assert.Equal(t, domain.InboundActionOptOut, messages[0].Action)
+		assert.Equal(t, domain.InboundActionNone, messages[1].Action)
+		forwarder.AssertNumberOfCalls(t, "Forward", 2)
+		suppressions.AssertExpectations(t)
+	})
+
+	t.Run("opt-in lifts only unsubscribes", func(t *testing.T) {
+		repo := new(MockInboundRepository)
+		suppressions := new(MockSuppressionRepository)
+
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+		suppressions.On("Get", ctx, domain.ChannelSMS, "+905551234567").Return(
+			domain.NewSuppression("+905551234567", domain.ChannelSMS, domain.SuppressionUnsubscribe), nil)
+		suppressions.On("Get", ctx, domain.ChannelSMS, "+905551234568").Return(
+			domain.NewSuppression("+905551234568", domain.ChannelSMS, domain.SuppressionManual), nil)
+		suppressions.On("Delete", ctx, domain.ChannelSMS, "+905551234567").Return(nil).Once()
+
+		svc := NewInboundService(repo, suppressions, keywords, logger)
+		svc.SetPhoneRegion("TR")
+		result, err := svc.Receive(ctx, "webhook", []*domain.InboundMessage{
+			domain.NewInboundMessage("webhook", "0555 123 45 67", "4455", "START"),
+			domain.NewInboundMessage("webhook", "+905551234568", "4455", "START"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.OptIns)
+		suppressions.AssertExpectations(t)
+		suppressions.AssertNotCalled(t, "Delete", ctx, domain.ChannelSMS, "+905551234568")
+	})
+
+	t.Run("skips redelivered messages", func(t *testing.T) {
+		repo := new(MockInboundRepository)
+		suppressions := new(MockSuppressionRepository)
+
+		repo.On("Create", ctx, mock.Anything).Return(domain.ErrAlreadyExists)
+
+		svc := NewInboundService(repo, suppressions, keywords, logger)
+		result, err := svc.Receive(ctx, "webhook", []*domain.InboundMessage{
+			domain.NewInboundMessage("webhook", "+905551234567", "4455", "STOP"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Duplicates)
+		suppressions.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
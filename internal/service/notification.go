@@ -23,6 +23,15 @@ type NotificationService struct {
 	queue           domain.Queue
 	logger          *slog.Logger
 	statusBroadcast func(notification *domain.Notification)
+	suppressions    domain.SuppressionRepository
+	attempts        domain.DeliveryAttemptRepository
+	expiry          domain.ExpiryPolicy
+	quietHours      *domain.QuietHours
+	recipients      domain.RecipientRepository
+	digests         domain.DigestRuleRepository
+	dedupStore      domain.DedupStore
+	dedup           domain.DedupPolicy
+	phoneRegion     string
 }
 
 // NewNotificationService creates a new NotificationService
@@ -45,9 +54,50 @@ func (s *NotificationService) SetStatusBroadcast(fn func(notification *domain.No
 	s.statusBroadcast = fn
 }
 
-// CreateRequest represents a request to create a notification
+// SetAttempts sets the delivery log notifications' provider requests are read from
+func (s *NotificationService) SetAttempts(repo domain.DeliveryAttemptRepository) {
+	s.attempts = repo
+}
+
+// SetExpiryPolicy sets the default time to live per channel and category
+func (s *NotificationService) SetExpiryPolicy(policy domain.ExpiryPolicy) {
+	s.expiry = policy
+}
+
+// SetQuietHours sets the send windows new notifications are deferred to
+func (s *NotificationService) SetQuietHours(quietHours *domain.QuietHours) {
+	s.quietHours = quietHours
+}
+
+// SetSuppressions sets the suppression list checked when notifications are created
+func (s *NotificationService) SetSuppressions(repo domain.SuppressionRepository) {
+	s.suppressions = repo
+}
+
+// SetRecipients sets the profiles notifications addressed to a user ID are
+// resolved from
+func (s *NotificationService) SetRecipients(repo domain.RecipientRepository) {
+	s.recipients = repo
+}
+
+// SetDigests sets the digest rules that hold matching notifications when
+// they are created
+func (s *NotificationService) SetDigests(repo domain.DigestRuleRepository) {
+	s.digests = repo
+}
+
+// SetPhoneRegion sets the region SMS recipients given in national format
+// are read in. Without one, phone numbers must be in international format.
+func (s *NotificationService) SetPhoneRegion(region string) {
+	s.phoneRegion = region
+}
+
+// CreateRequest represents a request to create a notification. It is
+// addressed either to Recipient or to the user with UserID, whose address
+// on Channel is looked up in the user's profile.
 type CreateRequest struct {
-	Recipient      string            `json:"recipient" validate:"required"`
+	Recipient      string            `json:"recipient" validate:"required_without=UserID"`
+	UserID         *string           `json:"user_id,omitempty"`
 	Channel        domain.Channel    `json:"channel" validate:"required"`
 	Content        string            `json:"content"`
 	Priority       domain.Priority   `json:"priority"`
@@ -56,6 +106,25 @@ type CreateRequest struct {
 	Metadata       map[string]any    `json:"metadata,omitempty"`
 	TemplateName   *string           `json:"template_name,omitempty"`
 	TemplateVars   map[string]string `json:"template_vars,omitempty"`
+	Category       string            `json:"category,omitempty"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	// TTL is the time to live in seconds, counted from the scheduled time or creation
+	TTL *int `json:"ttl,omitempty"`
+	// TimeZone is the recipient's IANA time zone, inferred from the phone number when empty
+	TimeZone string `json:"timezone,omitempty"`
+}
+
+// PatchRequest represents a change to a notification that has not started
+// processing. Content and TemplateName are mutually exclusive, as are
+// ScheduledAt and SendNow.
+type PatchRequest struct {
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	// SendNow queues a scheduled notification for immediate delivery
+	SendNow      bool              `json:"send_now,omitempty"`
+	Priority     *domain.Priority  `json:"priority,omitempty"`
+	Content      *string           `json:"content,omitempty"`
+	TemplateName *string           `json:"template_name,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
 }
 
 // BatchCreateRequest represents a request to create multiple notifications
@@ -81,8 +150,18 @@ func (s *NotificationService) Create(ctx context.Context, req CreateRequest) (*d
 		return nil, domain.NewValidationError("channel", "invalid channel")
 	}
 
+	// Resolve the recipient's address from the user's profile
+	profiles, err := s.lookupRecipients(ctx, []CreateRequest{req})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveRecipient(&req, profiles); err != nil {
+		return nil, err
+	}
+
 	// Get content from template if specified
 	content := req.Content
+	trackingDisabled := false
 	if req.TemplateName != nil {
 		template, err := s.templateRepo.GetByName(ctx, *req.TemplateName)
 		if err != nil {
@@ -99,6 +178,7 @@ func (s *NotificationService) Create(ctx context.Context, req CreateRequest) (*d
 		}
 
 		content = template.Render(req.TemplateVars)
+		trackingDisabled = template.TrackingDisabled
 	}
 
 	// Validate content
@@ -122,15 +202,52 @@ func (s *NotificationService) Create(ctx context.Context, req CreateRequest) (*d
 		if req.ScheduledAt.Before(time.Now()) {
 			return nil, domain.NewValidationError("scheduled_at", "scheduled time must be in the future")
 		}
-		notification.ScheduledAt = req.ScheduledAt
-		notification.Status = domain.StatusScheduled
+		if err := notification.MarkAsScheduled(*req.ScheduledAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := domain.ValidateCategory(req.Category); err != nil {
+		return nil, err
+	}
+	notification.Category = req.Category
+	if err := s.applyExpiry(notification, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.applySendWindow(notification, req.TimeZone); err != nil {
+		return nil, err
 	}
 
 	notification.IdempotencyKey = req.IdempotencyKey
 	notification.Metadata = req.Metadata
+	if trackingDisabled {
+		notification.DisableTracking()
+	}
+
+	if s.suppressions != nil {
+		if err := applySuppressions(ctx, s.suppressions, []*domain.Notification{notification}, s.phoneRegion); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.digests != nil {
+		if err := applyDigests(ctx, s.digests, []*domain.Notification{notification}); err != nil {
+			return nil, err
+		}
+	}
+
+	dedup, err := s.applyDedup(ctx, []*domain.Notification{notification})
+	if err != nil {
+		return nil, err
+	}
+	if len(dedup.create) == 0 {
+		return dedup.results[0], nil
+	}
 
 	// Save to database
 	if err := s.repo.Create(ctx, notification); err != nil {
+		s.releaseDedup(ctx, dedup.claimed)
 		if errors.Is(err, domain.ErrIdempotencyConflict) {
 			// Another request with same key was created, return existing
 			return s.repo.GetByIdempotencyKey(ctx, *req.IdempotencyKey)
@@ -164,24 +281,53 @@ func (s *NotificationService) CreateBatch(ctx context.Context, req BatchCreateRe
 		return nil, domain.ErrBatchSizeExceeded
 	}
 
-	batchID := uuid.New()
-	notifications := make([]*domain.Notification, 0, len(req.Notifications))
-	queueItems := make([]*domain.QueueItem, 0, len(req.Notifications))
+	return s.createBatch(ctx, uuid.New(), req.Notifications)
+}
+
+// createBatch creates the notifications of reqs in a single transaction
+// under batchID
+func (s *NotificationService) createBatch(ctx context.Context, batchID uuid.UUID, reqs []CreateRequest) ([]*domain.Notification, error) {
+	notifications := make([]*domain.Notification, 0, len(reqs))
+	queueItems := make([]*domain.QueueItem, 0, len(reqs))
+	templates := make(map[string]*domain.Template)
 
-	for i, createReq := range req.Notifications {
+	profiles, err := s.lookupRecipients(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalid recipients are reported together, one error per notification
+	var recipientErrs domain.ValidationErrors
+	for i, createReq := range reqs {
 		// Validate channel
 		if !createReq.Channel.IsValid() {
 			return nil, fmt.Errorf("notification %d: %w", i, domain.NewValidationError("channel", "invalid channel"))
 		}
 
+		if err := s.resolveRecipient(&createReq, profiles); err != nil {
+			var validationErr domain.ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr.Field = fmt.Sprintf("notifications[%d].%s", i, validationErr.Field)
+				recipientErrs.Errors = append(recipientErrs.Errors, validationErr)
+				continue
+			}
+			return nil, fmt.Errorf("notification %d: %w", i, err)
+		}
+
 		// Get content
 		content := createReq.Content
+		trackingDisabled := false
 		if createReq.TemplateName != nil {
-			template, err := s.templateRepo.GetByName(ctx, *createReq.TemplateName)
-			if err != nil {
-				return nil, fmt.Errorf("notification %d: %w", i, domain.ErrTemplateNotFound)
+			template, ok := templates[*createReq.TemplateName]
+			if !ok {
+				template, err = s.templateRepo.GetByName(ctx, *createReq.TemplateName)
+				if err != nil {
+					return nil, fmt.Errorf("notification %d: %w", i, domain.ErrTemplateNotFound)
+				}
+				templates[*createReq.TemplateName] = template
 			}
 			content = template.Render(createReq.TemplateVars)
+			trackingDisabled = template.TrackingDisabled
 		}
 
 		if content == "" {
@@ -197,16 +343,55 @@ func (s *NotificationService) CreateBatch(ctx context.Context, req BatchCreateRe
 		}
 
 		if createReq.ScheduledAt != nil {
-			notification.ScheduledAt = createReq.ScheduledAt
-			notification.Status = domain.StatusScheduled
+			if err := notification.MarkAsScheduled(*createReq.ScheduledAt); err != nil {
+				return nil, fmt.Errorf("notification %d: %w", i, err)
+			}
+		}
+
+		if err := domain.ValidateCategory(createReq.Category); err != nil {
+			return nil, fmt.Errorf("notification %d: %w", i, err)
+		}
+		notification.Category = createReq.Category
+		if err := s.applyExpiry(notification, createReq); err != nil {
+			return nil, fmt.Errorf("notification %d: %w", i, err)
+		}
+
+		if err := s.applySendWindow(notification, createReq.TimeZone); err != nil {
+			return nil, fmt.Errorf("notification %d: %w", i, err)
 		}
 
 		notification.IdempotencyKey = createReq.IdempotencyKey
 		notification.Metadata = createReq.Metadata
+		if trackingDisabled {
+			notification.DisableTracking()
+		}
 
 		notifications = append(notifications, notification)
+	}
+	if len(recipientErrs.Errors) > 0 {
+		return nil, recipientErrs
+	}
 
-		// Prepare queue item if not scheduled
+	if s.suppressions != nil {
+		if err := applySuppressions(ctx, s.suppressions, notifications, s.phoneRegion); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.digests != nil {
+		if err := applyDigests(ctx, s.digests, notifications); err != nil {
+			return nil, err
+		}
+	}
+
+	dedup, err := s.applyDedup(ctx, notifications)
+	if err != nil {
+		return nil, err
+	}
+	notifications = dedup.create
+
+	// Prepare queue items for notifications that are not scheduled, suppressed or held
+	for _, notification := range notifications {
 		if notification.Status == domain.StatusPending {
 			queueItems = append(queueItems, &domain.QueueItem{
 				NotificationID: notification.ID,
@@ -219,6 +404,7 @@ func (s *NotificationService) CreateBatch(ctx context.Context, req BatchCreateRe
 
 	// Save batch to database
 	if err := s.repo.CreateBatch(ctx, notifications); err != nil {
+		s.releaseDedup(ctx, dedup.claimed)
 		return nil, fmt.Errorf("failed to create batch: %w", err)
 	}
 
@@ -233,7 +419,9 @@ func (s *NotificationService) CreateBatch(ctx context.Context, req BatchCreateRe
 			// Update status to queued
 			for _, n := range notifications {
 				if n.Status == domain.StatusPending {
-					n.Status = domain.StatusQueued
+					if err := n.MarkAsQueued(); err != nil {
+						return nil, err
+					}
 				}
 			}
 		}
@@ -242,9 +430,10 @@ func (s *NotificationService) CreateBatch(ctx context.Context, req BatchCreateRe
 	s.logger.Info("batch created",
 		"batch_id", batchID,
 		"count", len(notifications),
+		"duplicates", len(dedup.results)-len(notifications),
 	)
 
-	return notifications, nil
+	return dedup.results, nil
 }
 
 // GetByID retrieves a notification by ID
@@ -252,6 +441,25 @@ func (s *NotificationService) GetByID(ctx context.Context, id uuid.UUID) (*domai
 	return s.repo.GetByID(ctx, id)
 }
 
+// GetEvents retrieves the status history of a notification
+func (s *NotificationService) GetEvents(ctx context.Context, id uuid.UUID) ([]*domain.StatusEvent, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusEvents(ctx, id)
+}
+
+// GetAttempts retrieves the provider requests made for a notification
+func (s *NotificationService) GetAttempts(ctx context.Context, id uuid.UUID) ([]*domain.DeliveryAttempt, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if s.attempts == nil {
+		return []*domain.DeliveryAttempt{}, nil
+	}
+	return s.attempts.ListByNotification(ctx, id)
+}
+
 // GetByBatchID retrieves all notifications in a batch
 func (s *NotificationService) GetByBatchID(ctx context.Context, batchID uuid.UUID) ([]*domain.Notification, error) {
 	return s.repo.GetByBatchID(ctx, batchID)
@@ -264,12 +472,10 @@ func (s *NotificationService) Cancel(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	if !notification.CanCancel() {
-		return domain.ErrCannotCancel
+	if err := notification.MarkAsCancelled(); err != nil {
+		return err
 	}
 
-	notification.MarkAsCancelled()
-
 	if err := s.repo.Update(ctx, notification); err != nil {
 		return fmt.Errorf("failed to cancel notification: %w", err)
 	}
@@ -283,6 +489,304 @@ func (s *NotificationService) Cancel(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// CancelBatch cancels every notification of a batch that has not started
+// processing
+func (s *NotificationService) CancelBatch(ctx context.Context, batchID uuid.UUID) (*domain.BulkCancelResult, error) {
+	return s.cancelMatching(ctx, domain.NotificationFilter{BatchID: &batchID})
+}
+
+// CancelMatching cancels every notification matching filter that has not
+// started processing. The filter must select something, so a mistaken
+// request cannot cancel every notification.
+func (s *NotificationService) CancelMatching(ctx context.Context, filter domain.NotificationFilter) (*domain.BulkCancelResult, error) {
+	if filter.IsEmpty() {
+		return nil, domain.NewValidationError("filter", "at least one filter is required")
+	}
+	return s.cancelMatching(ctx, filter)
+}
+
+func (s *NotificationService) cancelMatching(ctx context.Context, filter domain.NotificationFilter) (*domain.BulkCancelResult, error) {
+	result, err := s.repo.CancelMatching(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Workers skip cancelled notifications, so a failure here only leaves
+	// entries that are discarded when dequeued
+	dequeued, err := s.queue.RemoveBatch(ctx, result.QueueItems)
+	if err != nil {
+		s.logger.Error("failed to remove cancelled notifications from queue",
+			"count", len(result.QueueItems),
+			"error", err,
+		)
+	}
+	result.Dequeued = dequeued
+
+	s.logger.Info("notifications cancelled",
+		"cancelled", result.Cancelled,
+		"in_flight", result.InFlight,
+		"dequeued", result.Dequeued,
+	)
+
+	return result, nil
+}
+
+// Retry queues a failed notification again with its retry count reset. A
+// non-nil priority overrides the notification's priority. The failed ->
+// queued transition is recorded in the notification's history. The
+// notification is stored as queued before it is enqueued, so a worker never
+// dequeues it while it is still failed; if enqueueing fails it is moved back
+// to failed and can be retried again.
+func (s *NotificationService) Retry(ctx context.Context, id uuid.UUID, priority *domain.Priority) (*domain.Notification, error) {
+	if priority != nil && !priority.IsValid() {
+		return nil, domain.NewValidationError("priority", "invalid priority")
+	}
+
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := notification.Retry(priority, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, notification); err != nil {
+		return nil, fmt.Errorf("failed to retry notification: %w", err)
+	}
+
+	if err := s.queue.Enqueue(ctx, queueItem(notification)); err != nil {
+		s.failUnqueued(ctx, notification, retryEnqueueError(err))
+		return nil, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	s.broadcastStatus(notification)
+
+	s.logger.Info("notification retried",
+		"notification_id", id,
+		"priority", notification.Priority,
+	)
+
+	return notification, nil
+}
+
+// RetryMatching queues every failed notification matching filter again.
+// Like CancelMatching, the filter must select something.
+func (s *NotificationService) RetryMatching(ctx context.Context, filter domain.RetryFilter, priority *domain.Priority) (*domain.BulkRetryResult, error) {
+	if filter.IsEmpty() {
+		return nil, domain.NewValidationError("filter", "at least one filter is required")
+	}
+	if priority != nil && !priority.IsValid() {
+		return nil, domain.NewValidationError("priority", "invalid priority")
+	}
+
+	result, err := s.repo.RetryMatching(ctx, filter, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.QueueItems) > 0 {
+		if err := s.queue.EnqueueBatch(ctx, result.QueueItems); err != nil {
+			ids := make([]uuid.UUID, 0, len(result.QueueItems))
+			for _, item := range result.QueueItems {
+				ids = append(ids, item.NotificationID)
+			}
+			if _, revertErr := s.repo.RevertRetry(ctx, ids, retryEnqueueError(err)); revertErr != nil {
+				s.logger.Error("failed to revert retried notifications",
+					"count", len(ids),
+					"error", revertErr,
+				)
+			}
+			return nil, fmt.Errorf("failed to enqueue %d retried notifications: %w", len(result.QueueItems), err)
+		}
+	}
+
+	s.logger.Info("notifications retried",
+		"retried", result.Retried,
+		"expired", result.Expired,
+	)
+
+	return result, nil
+}
+
+// failUnqueued moves a notification recorded as queued that could not be
+// enqueued to failed. A version conflict means something else already moved
+// it on.
+func (s *NotificationService) failUnqueued(ctx context.Context, notification *domain.Notification, message string) {
+	if err := notification.MarkAsFailed(message); err != nil {
+		s.logger.Error("failed to fail unqueued notification",
+			"notification_id", notification.ID,
+			"error", err,
+		)
+		return
+	}
+	if err := s.repo.Update(ctx, notification); err != nil && !errors.Is(err, domain.ErrVersionConflict) {
+		s.logger.Error("failed to fail unqueued notification",
+			"notification_id", notification.ID,
+			"error", err,
+		)
+	}
+}
+
+// retryEnqueueError is the error message of a retry that could not be enqueued
+func retryEnqueueError(err error) string {
+	return "retry could not be queued: " + err.Error()
+}
+
+// Patch changes the send time, priority or content of a notification that is
+// still pending, scheduled or queued. A queued notification is taken out of
+// the queue first, which fails once a worker has dequeued it, and is queued
+// again with its new priority unless it was moved to a later time.
+func (s *NotificationService) Patch(ctx context.Context, id uuid.UUID, req PatchRequest) (*domain.Notification, error) {
+	if err := validatePatch(req); err != nil {
+		return nil, err
+	}
+
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !notification.CanEdit() {
+		return nil, fmt.Errorf("%w: cannot edit a %s notification", domain.ErrInvalidStatus, notification.Status)
+	}
+
+	originalStatus := notification.Status
+	queuedItem := queueItem(notification)
+
+	if err := s.applyPatch(ctx, notification, req); err != nil {
+		return nil, err
+	}
+
+	// Claim the queue entry so no worker sends the old version
+	removed := false
+	if originalStatus == domain.StatusPending || originalStatus == domain.StatusQueued {
+		removed, err = s.queue.Remove(ctx, queuedItem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove notification from queue: %w", err)
+		}
+		// A pending notification may not have reached the queue yet
+		if !removed && originalStatus == domain.StatusQueued {
+			return nil, fmt.Errorf("%w: notification is already being processed", domain.ErrInvalidStatus)
+		}
+	}
+
+	if notification.Status != domain.StatusScheduled && notification.Status != domain.StatusQueued {
+		if err := notification.MarkAsQueued(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, notification); err != nil {
+		if removed {
+			if qErr := s.queue.Enqueue(ctx, queuedItem); qErr != nil {
+				s.logger.Error("failed to restore queue entry",
+					"notification_id", id,
+					"error", qErr,
+				)
+			}
+		}
+		return nil, fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	if notification.Status == domain.StatusQueued {
+		if err := s.queue.Enqueue(ctx, queueItem(notification)); err != nil {
+			s.logger.Error("failed to enqueue notification",
+				"notification_id", id,
+				"error", err,
+			)
+		}
+	}
+
+	s.broadcastStatus(notification)
+
+	s.logger.Info("notification updated",
+		"notification_id", id,
+		"status", notification.Status,
+		"priority", notification.Priority,
+	)
+
+	return notification, nil
+}
+
+// applyPatch applies the requested changes to the notification
+func (s *NotificationService) applyPatch(ctx context.Context, notification *domain.Notification, req PatchRequest) error {
+	if req.Content != nil || req.TemplateName != nil {
+		content := ""
+		if req.Content != nil {
+			content = *req.Content
+		} else {
+			template, err := s.templateRepo.GetByName(ctx, *req.TemplateName)
+			if err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return domain.ErrTemplateNotFound
+				}
+				return fmt.Errorf("failed to get template: %w", err)
+			}
+			if missing := template.Validate(req.TemplateVars); len(missing) > 0 {
+				return fmt.Errorf("%w: %v", domain.ErrMissingVariables, missing)
+			}
+			content = template.Render(req.TemplateVars)
+			if template.TrackingDisabled {
+				notification.DisableTracking()
+			}
+		}
+
+		if content == "" {
+			return domain.NewValidationError("content", "content is required")
+		}
+		if err := validateContentLength(notification.Channel, content); err != nil {
+			return err
+		}
+		notification.Content = content
+	}
+
+	if req.Priority != nil {
+		notification.Priority = *req.Priority
+	}
+
+	switch {
+	case req.ScheduledAt != nil:
+		if req.ScheduledAt.Before(time.Now()) {
+			return domain.NewValidationError("scheduled_at", "scheduled time must be in the future")
+		}
+		if notification.IsExpired(*req.ScheduledAt) {
+			return domain.NewValidationError("scheduled_at", "scheduled time must be before expires_at")
+		}
+		// The new send time may fall into the recipient's quiet hours
+		sendAt, _ := s.quietHours.NextAllowed(notification, req.ScheduledAt.UTC())
+		return notification.ScheduleFor(sendAt)
+	case req.SendNow && notification.Status == domain.StatusScheduled:
+		if next, deferred := s.quietHours.NextAllowed(notification, time.Now().UTC()); deferred {
+			return notification.Reschedule(next)
+		}
+		notification.ScheduledAt = nil
+		return notification.MarkAsQueued()
+	}
+
+	return nil
+}
+
+// validatePatch rejects empty and contradictory patches
+func validatePatch(req PatchRequest) error {
+	if req.ScheduledAt == nil && !req.SendNow && req.Priority == nil && req.Content == nil && req.TemplateName == nil {
+		return domain.NewValidationError("body", "no changes requested")
+	}
+	if req.ScheduledAt != nil && req.SendNow {
+		return domain.NewValidationError("send_now", "set either scheduled_at or send_now, not both")
+	}
+	if req.Content != nil && req.TemplateName != nil {
+		return domain.NewValidationError("content", "set either content or template_name, not both")
+	}
+	if req.TemplateVars != nil && req.TemplateName == nil {
+		return domain.NewValidationError("template_name", "template_name is required with template_vars")
+	}
+	if req.Priority != nil && !req.Priority.IsValid() {
+		return domain.NewValidationError("priority", "invalid priority")
+	}
+	return nil
+}
+
 // List lists notifications with filters
 func (s *NotificationService) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationListResult, error) {
 	return s.repo.List(ctx, filter)
@@ -297,21 +801,43 @@ func (s *NotificationService) UpdateStatus(ctx context.Context, notification *do
 	return nil
 }
 
-// enqueueNotification adds a notification to the processing queue
-func (s *NotificationService) enqueueNotification(ctx context.Context, notification *domain.Notification) error {
-	item := &domain.QueueItem{
+// queueItem returns the queue entry of a notification
+func queueItem(notification *domain.Notification) *domain.QueueItem {
+	return &domain.QueueItem{
 		NotificationID: notification.ID,
 		Channel:        notification.Channel,
 		Priority:       notification.Priority,
 		RetryCount:     notification.RetryCount,
 	}
+}
+
+// enqueueNotification adds a notification to the processing queue
+func (s *NotificationService) enqueueNotification(ctx context.Context, notification *domain.Notification) error {
+	if err := s.queue.Enqueue(ctx, queueItem(notification)); err != nil {
+		return err
+	}
 
-	if err := s.queue.Enqueue(ctx, item); err != nil {
+	if err := notification.MarkAsQueued(); err != nil {
 		return err
 	}
+	if err := s.repo.Update(ctx, notification); err != nil {
+		// A worker already picked the notification up and moved it on
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
 
-	notification.MarkAsQueued()
-	return s.repo.Update(ctx, notification)
+// isPermanentCreateError reports whether creating a notification failed
+// because of the request itself, so repeating it cannot succeed
+func isPermanentCreateError(err error) bool {
+	var validationErr domain.ValidationError
+	var validationErrs domain.ValidationErrors
+	return errors.As(err, &validationErr) || errors.As(err, &validationErrs) ||
+		errors.Is(err, domain.ErrTemplateNotFound) || errors.Is(err, domain.ErrMissingVariables) ||
+		errors.Is(err, domain.ErrRecipientNotFound) || errors.Is(err, domain.ErrNoAddress)
 }
 
 // broadcastStatus broadcasts status update via WebSocket
@@ -321,18 +847,57 @@ func (s *NotificationService) broadcastStatus(notification *domain.Notification)
 	}
 }
 
-// validateContentLength validates content length based on channel
-func validateContentLength(channel domain.Channel, content string) error {
-	var maxLen int
+// applyExpiry sets the notification's expiry from the request or the
+// channel and category defaults
+func (s *NotificationService) applyExpiry(notification *domain.Notification, req CreateRequest) error {
+	var ttl time.Duration
+	if req.TTL != nil {
+		if *req.TTL <= 0 {
+			return domain.NewValidationError("ttl", "ttl must be a positive number of seconds")
+		}
+		ttl = time.Duration(*req.TTL) * time.Second
+	}
+	return notification.SetExpiry(req.ExpiresAt, ttl, s.expiry)
+}
+
+// applySendWindow records the recipient's time zone and schedules a
+// notification that would be sent during quiet hours for the end of them
+func (s *NotificationService) applySendWindow(notification *domain.Notification, timeZone string) error {
+	if timeZone != "" {
+		if err := domain.ValidateTimeZone(timeZone); err != nil {
+			return err
+		}
+		notification.TimeZone = timeZone
+	}
+
+	sendAt := time.Now().UTC()
+	if notification.ScheduledAt != nil {
+		sendAt = *notification.ScheduledAt
+	}
+
+	next, deferred := s.quietHours.NextAllowed(notification, sendAt)
+	if !deferred {
+		return nil
+	}
+	return notification.ScheduleFor(next)
+}
+
+// maxContentLength returns the maximum content length of a channel
+func maxContentLength(channel domain.Channel) int {
 	switch channel {
 	case domain.ChannelSMS:
-		maxLen = 160 * 4 // Allow up to 4 SMS segments
+		return 160 * 4 // Allow up to 4 SMS segments
 	case domain.ChannelEmail:
-		maxLen = 100000 // 100KB
+		return 100000 // 100KB
 	case domain.ChannelPush:
-		maxLen = 4096 // 4KB
+		return 4096 // 4KB
 	}
+	return 0
+}
 
+// validateContentLength validates content length based on channel
+func validateContentLength(channel domain.Channel, content string) error {
+	maxLen := maxContentLength(channel)
 	if len(content) > maxLen {
 		return domain.NewValidationError("content",
 			fmt.Sprintf("content exceeds maximum length of %d characters for %s channel", maxLen, channel))
@@ -2,14 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/insider-one/notification-service/internal/domain"
 )
@@ -79,11 +82,64 @@ func (m *MockNotificationRepository) GetScheduledNotifications(ctx context.Conte
 	return args.Get(0).([]*domain.Notification), args.Error(1)
 }
 
-func (m *MockNotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.Status) error {
-	args := m.Called(ctx, id, status)
+func (m *MockNotificationRepository) ListStatusEvents(ctx context.Context, notificationID uuid.UUID) ([]*domain.StatusEvent, error) {
+	args := m.Called(ctx, notificationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StatusEvent), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Notification, error) {
+	args := m.Called(ctx, provider, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CancelMatching(ctx context.Context, filter domain.NotificationFilter) (*domain.BulkCancelResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BulkCancelResult), args.Error(1)
+}
+
+func (m *MockNotificationRepository) RetryMatching(ctx context.Context, filter domain.RetryFilter, priority *domain.Priority) (*domain.BulkRetryResult, error) {
+	args := m.Called(ctx, filter, priority)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BulkRetryResult), args.Error(1)
+}
+
+func (m *MockNotificationRepository) RevertRetry(ctx context.Context, ids []uuid.UUID, errorMsg string) (int64, error) {
+	args := m.Called(ctx, ids, errorMsg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListHeld(ctx context.Context, group domain.DigestGroup, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, group, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Digest(ctx context.Context, digest *domain.Notification, originals []*domain.Notification) error {
+	args := m.Called(ctx, digest, originals)
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) ClaimStatusChecks(ctx context.Context, providers []string, sentAfter, checkedBefore time.Time, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, providers, sentAfter, checkedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
 // MockTemplateRepository is a mock implementation of domain.TemplateRepository
 type MockTemplateRepository struct {
 	mock.Mock
@@ -151,6 +207,16 @@ func (m *MockQueue) Dequeue(ctx context.Context, channel domain.Channel) (*domai
 	return args.Get(0).(*domain.QueueItem), args.Error(1)
 }
 
+func (m *MockQueue) Remove(ctx context.Context, item *domain.QueueItem) (bool, error) {
+	args := m.Called(ctx, item)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQueue) RemoveBatch(ctx context.Context, items []*domain.QueueItem) (int64, error) {
+	args := m.Called(ctx, items)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockQueue) GetQueueDepth(ctx context.Context, channel domain.Channel) (int64, error) {
 	args := m.Called(ctx, channel)
 	return args.Get(0).(int64), args.Error(1)
@@ -237,6 +303,101 @@ func TestNotificationService_Create(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, notification)
 	})
+
+	t.Run("create notification with a category too long", func(t *testing.T) {
+		req := CreateRequest{
+			Recipient: "+905551234567",
+			Channel:   domain.ChannelSMS,
+			Content:   "Test message",
+			Category:  strings.Repeat("c", domain.MaxCategoryLength+1),
+		}
+
+		_, err := service.Create(ctx, req)
+
+		var validationErr domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "category", validationErr.Field)
+	})
+
+	t.Run("create notification applies category expiry", func(t *testing.T) {
+		service.SetExpiryPolicy(domain.ExpiryPolicy{
+			Channels:   map[domain.Channel]time.Duration{domain.ChannelSMS: time.Hour},
+			Categories: map[string]time.Duration{"otp": 10 * time.Minute},
+		})
+		defer service.SetExpiryPolicy(domain.ExpiryPolicy{})
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+
+		notification, err := service.Create(ctx, CreateRequest{
+			Recipient: "+905551234567",
+			Channel:   domain.ChannelSMS,
+			Content:   "Your code is 4821",
+			Category:  "otp",
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, notification.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *notification.ExpiresAt, 5*time.Second)
+	})
+
+	t.Run("create notification during quiet hours is scheduled", func(t *testing.T) {
+		// A window that always covers the current time
+		now := time.Now().UTC()
+		start := (now.Hour()*60 + now.Minute() + 23*60) % (24 * 60)
+		end := (start + 2*60) % (24 * 60)
+		service.SetQuietHours(domain.NewQuietHours(
+			[]domain.QuietHoursRule{{Channel: domain.ChannelPush, Start: start, End: end}}, time.UTC, nil))
+		defer service.SetQuietHours(nil)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+
+		notification, err := service.Create(ctx, CreateRequest{
+			Recipient: "device-token",
+			Channel:   domain.ChannelPush,
+			Content:   "Sale!",
+			TimeZone:  "UTC",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusScheduled, notification.Status)
+		require.NotNil(t, notification.ScheduledAt)
+		assert.True(t, notification.ScheduledAt.After(now))
+		mockQueue.AssertNotCalled(t, "Enqueue", ctx, mock.MatchedBy(func(item *domain.QueueItem) bool {
+			return item.NotificationID == notification.ID
+		}))
+	})
+
+	t.Run("create notification rejects unknown time zone", func(t *testing.T) {
+		notification, err := service.Create(ctx, CreateRequest{
+			Recipient: "+905551234567",
+			Channel:   domain.ChannelSMS,
+			Content:   "Test message",
+			TimeZone:  "Mars/Olympus",
+		})
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Nil(t, notification)
+	})
+
+	t.Run("create notification rejects ttl and expires_at together", func(t *testing.T) {
+		ttl := 60
+		expiresAt := time.Now().Add(time.Hour)
+
+		notification, err := service.Create(ctx, CreateRequest{
+			Recipient: "+905551234567",
+			Channel:   domain.ChannelSMS,
+			Content:   "Test message",
+			TTL:       &ttl,
+			ExpiresAt: &expiresAt,
+		})
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Nil(t, notification)
+	})
 }
 
 func TestNotificationService_Cancel(t *testing.T) {
@@ -277,6 +438,20 @@ func TestNotificationService_Cancel(t *testing.T) {
 		assert.Equal(t, domain.ErrCannotCancel, err)
 	})
 
+	t.Run("cancel loses to a concurrent worker", func(t *testing.T) {
+		id := uuid.New()
+		notification := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		notification.ID = id
+		notification.Status = domain.StatusQueued
+
+		mockRepo.On("GetByID", ctx, id).Return(notification, nil).Once()
+		mockRepo.On("Update", ctx, notification).Return(domain.ErrVersionConflict).Once()
+
+		err := service.Cancel(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	})
+
 	t.Run("cancel non-existent notification", func(t *testing.T) {
 		id := uuid.New()
 
@@ -288,3 +463,347 @@ func TestNotificationService_Cancel(t *testing.T) {
 		assert.Equal(t, domain.ErrNotFound, err)
 	})
 }
+
+func TestNotificationService_BulkCancel(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("cancels a batch and removes its queue entries", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		batchID := uuid.New()
+		items := []*domain.QueueItem{
+			{NotificationID: uuid.New(), Channel: domain.ChannelSMS, Priority: domain.PriorityNormal},
+			{NotificationID: uuid.New(), Channel: domain.ChannelSMS, Priority: domain.PriorityNormal},
+		}
+		mockRepo.On("CancelMatching", ctx, domain.NotificationFilter{BatchID: &batchID}).
+			Return(&domain.BulkCancelResult{Cancelled: 3, InFlight: 1, QueueItems: items}, nil).Once()
+		mockQueue.On("RemoveBatch", ctx, items).Return(int64(2), nil).Once()
+
+		result, err := service.CancelBatch(ctx, batchID)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.Cancelled)
+		assert.Equal(t, int64(1), result.InFlight)
+		assert.Equal(t, int64(2), result.Dequeued)
+	})
+
+	t.Run("filter by metadata", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		filter := domain.NotificationFilter{Metadata: map[string]any{"campaign": "spring"}}
+		mockRepo.On("CancelMatching", ctx, filter).
+			Return(&domain.BulkCancelResult{QueueItems: []*domain.QueueItem{}}, nil).Once()
+		mockQueue.On("RemoveBatch", ctx, []*domain.QueueItem{}).Return(int64(0), nil).Once()
+
+		_, err := service.CancelMatching(ctx, filter)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an empty filter", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+
+		_, err := service.CancelMatching(ctx, domain.NotificationFilter{Page: 2})
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		mockRepo.AssertNotCalled(t, "CancelMatching", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_Retry(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("requeues a failed notification at an overridden priority", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		notification := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		notification.Status = domain.StatusFailed
+		notification.RetryCount = 3
+
+		high := domain.PriorityHigh
+		mockRepo.On("GetByID", ctx, notification.ID).Return(notification, nil).Once()
+		mockRepo.On("Update", ctx, notification).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.MatchedBy(func(item *domain.QueueItem) bool {
+			return item.NotificationID == notification.ID && item.Priority == domain.PriorityHigh && item.RetryCount == 0
+		})).Return(nil).Once()
+
+		result, err := service.Retry(ctx, notification.ID, &high)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusQueued, result.Status)
+		assert.Equal(t, 0, result.RetryCount)
+		mockQueue.AssertExpectations(t)
+
+		events := result.PendingEvents()
+		require.NotEmpty(t, events)
+		require.NotNil(t, events[len(events)-1].Reason)
+		assert.Equal(t, domain.RetryReasonManual, *events[len(events)-1].Reason)
+	})
+
+	t.Run("moves the notification back to failed when it cannot be enqueued", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		notification := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		notification.Status = domain.StatusFailed
+		mockRepo.On("GetByID", ctx, notification.ID).Return(notification, nil).Once()
+		mockRepo.On("Update", ctx, notification).Return(nil).Twice()
+		mockQueue.On("Enqueue", ctx, mock.Anything).Return(errors.New("redis unavailable")).Once()
+
+		_, err := service.Retry(ctx, notification.ID, nil)
+
+		require.Error(t, err)
+		assert.Equal(t, domain.StatusFailed, notification.Status)
+		require.NotNil(t, notification.ErrorMessage)
+		assert.Contains(t, *notification.ErrorMessage, "redis unavailable")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("refuses a notification that has not failed", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		notification := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		notification.Status = domain.StatusSent
+		mockRepo.On("GetByID", ctx, notification.ID).Return(notification, nil).Once()
+
+		_, err := service.Retry(ctx, notification.ID, nil)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("bulk retry enqueues the retried notifications", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		sms := domain.ChannelSMS
+		status := 503
+		filter := domain.RetryFilter{Channel: &sms, ProviderStatus: &status}
+		items := []*domain.QueueItem{
+			{NotificationID: uuid.New(), Channel: domain.ChannelSMS, Priority: domain.PriorityNormal},
+		}
+		mockRepo.On("RetryMatching", ctx, filter, (*domain.Priority)(nil)).
+			Return(&domain.BulkRetryResult{Retried: 1, Expired: 2, QueueItems: items}, nil).Once()
+		mockQueue.On("EnqueueBatch", ctx, items).Return(nil).Once()
+
+		result, err := service.RetryMatching(ctx, filter, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Retried)
+		assert.Equal(t, int64(2), result.Expired)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("bulk retry moves the notifications back to failed when they cannot be enqueued", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		sms := domain.ChannelSMS
+		filter := domain.RetryFilter{Channel: &sms}
+		items := []*domain.QueueItem{
+			{NotificationID: uuid.New(), Channel: domain.ChannelSMS, Priority: domain.PriorityNormal},
+			{NotificationID: uuid.New(), Channel: domain.ChannelSMS, Priority: domain.PriorityNormal},
+		}
+		mockRepo.On("RetryMatching", ctx, filter, (*domain.Priority)(nil)).
+			Return(&domain.BulkRetryResult{Retried: 2, QueueItems: items}, nil).Once()
+		mockQueue.On("EnqueueBatch", ctx, items).Return(errors.New("redis unavailable")).Once()
+		mockRepo.On("RevertRetry", ctx, []uuid.UUID{items[0].NotificationID, items[1].NotificationID}, mock.Anything).
+			Return(int64(2), nil).Once()
+
+		_, err := service.RetryMatching(ctx, filter, nil)
+
+		require.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("bulk retry rejects an empty filter", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+
+		_, err := service.RetryMatching(ctx, domain.RetryFilter{}, nil)
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		mockRepo.AssertNotCalled(t, "RetryMatching", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_Patch(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	newQueued := func() *domain.Notification {
+		n := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		require.NoError(t, n.MarkAsQueued())
+		return n
+	}
+
+	t.Run("reprioritises a queued notification", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		n := newQueued()
+		high := domain.PriorityHigh
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+		mockQueue.On("Remove", ctx, &domain.QueueItem{
+			NotificationID: n.ID, Channel: domain.ChannelSMS, Priority: domain.PriorityNormal,
+		}).Return(true, nil).Once()
+		mockRepo.On("Update", ctx, n).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, &domain.QueueItem{
+			NotificationID: n.ID, Channel: domain.ChannelSMS, Priority: domain.PriorityHigh,
+		}).Return(nil).Once()
+
+		result, err := service.Patch(ctx, n.ID, PatchRequest{Priority: &high})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PriorityHigh, result.Priority)
+		assert.Equal(t, domain.StatusQueued, result.Status)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("moves a queued notification to a later time", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		n := newQueued()
+		at := time.Now().Add(time.Hour).UTC()
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+		mockQueue.On("Remove", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(true, nil).Once()
+		mockRepo.On("Update", ctx, n).Return(nil).Once()
+
+		result, err := service.Patch(ctx, n.ID, PatchRequest{ScheduledAt: &at})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusScheduled, result.Status)
+		assert.Equal(t, at, *result.ScheduledAt)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends a scheduled notification now", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		n := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		require.NoError(t, n.MarkAsScheduled(time.Now().Add(time.Hour)))
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+		mockRepo.On("Update", ctx, n).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+
+		result, err := service.Patch(ctx, n.ID, PatchRequest{SendNow: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusQueued, result.Status)
+		assert.Nil(t, result.ScheduledAt)
+		mockQueue.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refused once a worker dequeued the notification", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		n := newQueued()
+		content := "Updated"
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+		mockQueue.On("Remove", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(false, nil).Once()
+
+		_, err := service.Patch(ctx, n.ID, PatchRequest{Content: &content})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refused while processing", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+
+		n := newQueued()
+		require.NoError(t, n.MarkAsProcessing())
+		content := "Updated"
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+
+		_, err := service.Patch(ctx, n.ID, PatchRequest{Content: &content})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+	})
+
+	t.Run("restores the queue entry when the update conflicts", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		service := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+
+		n := newQueued()
+		content := "Updated"
+		item := &domain.QueueItem{NotificationID: n.ID, Channel: domain.ChannelSMS, Priority: domain.PriorityNormal}
+		mockRepo.On("GetByID", ctx, n.ID).Return(n, nil).Once()
+		mockQueue.On("Remove", ctx, item).Return(true, nil).Once()
+		mockRepo.On("Update", ctx, n).Return(domain.ErrVersionConflict).Once()
+		mockQueue.On("Enqueue", ctx, item).Return(nil).Once()
+
+		_, err := service.Patch(ctx, n.ID, PatchRequest{Content: &content})
+
+		assert.ErrorIs(t, err, domain.ErrVersionConflict)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("rejects contradictory changes", func(t *testing.T) {
+		service := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+
+		at := time.Now().Add(time.Hour)
+		_, err := service.Patch(ctx, uuid.New(), PatchRequest{ScheduledAt: &at, SendNow: true})
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestNotificationService_GetEvents(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mockRepo := new(MockNotificationRepository)
+	service := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+
+	t.Run("returns the status history", func(t *testing.T) {
+		notification := domain.NewNotification("+905551234567", domain.ChannelSMS, "Test")
+		events := notification.PendingEvents()
+
+		mockRepo.On("GetByID", ctx, notification.ID).Return(notification, nil).Once()
+		mockRepo.On("ListStatusEvents", ctx, notification.ID).Return(events, nil).Once()
+
+		result, err := service.GetEvents(ctx, notification.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, events, result)
+	})
+
+	t.Run("unknown notification", func(t *testing.T) {
+		id := uuid.New()
+		mockRepo.On("GetByID", ctx, id).Return(nil, domain.ErrNotFound).Once()
+
+		_, err := service.GetEvents(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "ListStatusEvents", ctx, id)
+	})
+}
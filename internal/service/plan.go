@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// maxPlanSteps is the maximum number of steps of a fallback plan
+const maxPlanSteps = 5
+
+// PlanService handles multi-channel fallback plans
+type PlanService struct {
+	repo          domain.PlanRepository
+	templateRepo  domain.TemplateRepository
+	notifications *NotificationService
+	logger        *slog.Logger
+	batchSize     int
+}
+
+// NewPlanService creates a new PlanService
+func NewPlanService(
+	repo domain.PlanRepository,
+	templateRepo domain.TemplateRepository,
+	notifications *NotificationService,
+	logger *slog.Logger,
+) *PlanService {
+	return &PlanService{
+		repo:          repo,
+		templateRepo:  templateRepo,
+		notifications: notifications,
+		logger:        logger,
+		batchSize:     100,
+	}
+}
+
+// PlanStepRequest describes one step of a fallback plan. Content and
+// TemplateName override the plan's content for the step.
+type PlanStepRequest struct {
+	Channel      domain.Channel `json:"channel"`
+	Recipient    string         `json:"recipient"`
+	Content      *string        `json:"content,omitempty"`
+	TemplateName *string        `json:"template_name,omitempty"`
+	// Timeout is how long, in seconds, the step may take to be delivered
+	Timeout int `json:"timeout"`
+}
+
+// CreatePlanRequest represents a request to create a fallback plan. Every
+// step needs content, either its own or the plan's.
+type CreatePlanRequest struct {
+	Content      string            `json:"content,omitempty"`
+	TemplateName *string           `json:"template_name,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	Steps        []PlanStepRequest `json:"steps"`
+}
+
+// Create creates a fallback plan and starts its first step. If the first
+// notification cannot be created the plan is still returned; the scheduler
+// starts the step on its next tick.
+func (s *PlanService) Create(ctx context.Context, req CreatePlanRequest) (*domain.Plan, error) {
+	steps := make([]*domain.PlanStep, 0, len(req.Steps))
+	for _, step := range req.Steps {
+		steps = append(steps, &domain.PlanStep{
+			Channel:      step.Channel,
+			Recipient:    step.Recipient,
+			Content:      step.Content,
+			TemplateName: step.TemplateName,
+			Timeout:      step.Timeout,
+		})
+	}
+
+	plan := domain.NewPlan(steps)
+	plan.Content = req.Content
+	plan.TemplateName = req.TemplateName
+	plan.TemplateVars = req.TemplateVars
+	if req.Priority != "" {
+		plan.Priority = req.Priority
+	}
+	plan.Category = req.Category
+	plan.Metadata = req.Metadata
+
+	if err := s.validate(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create plan: %w", err)
+	}
+
+	if err := s.startStep(ctx, plan, time.Now()); err != nil {
+		s.logger.Error("failed to start plan step",
+			"plan_id", plan.ID,
+			"error", err,
+		)
+		return plan, nil
+	}
+
+	if err := s.repo.Update(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	s.logger.Info("plan created",
+		"plan_id", plan.ID,
+		"steps", len(plan.Steps),
+	)
+
+	return plan, nil
+}
+
+// GetByID retrieves a plan with the current status of its started steps
+func (s *PlanService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	plan, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored step statuses are only refreshed when the plan is evaluated
+	if plan.Status == domain.PlanActive {
+		if statuses, _, err := s.stepStatuses(ctx, plan); err == nil {
+			for i, status := range statuses {
+				plan.Steps[i].Status = status
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Cancel stops a plan and cancels its current notification if it has not
+// been picked up yet
+func (s *PlanService) Cancel(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	plan, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Cancel(); err != nil {
+		return nil, err
+	}
+
+	if id := plan.Step().NotificationID; id != nil {
+		s.stopStep(ctx, *id)
+	}
+
+	if err := s.repo.Update(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to cancel plan: %w", err)
+	}
+
+	s.logger.Info("plan cancelled", "plan_id", plan.ID)
+
+	return plan, nil
+}
+
+// EvaluateDue moves the plans that are due at now forward: a delivered
+// step completes its plan, and a failed or timed-out step falls back to
+// the next one. Step notifications are created with an idempotency key
+// derived from the plan and step, and plans are updated with versioned
+// updates, so replicas running concurrently start every step once.
+func (s *PlanService) EvaluateDue(ctx context.Context, now time.Time) int {
+	plans, err := s.repo.ListDue(ctx, now, s.batchSize)
+	if err != nil {
+		s.logger.Error("failed to get due plans", "error", err)
+		return 0
+	}
+
+	evaluated := 0
+	for _, plan := range plans {
+		if err := s.evaluate(ctx, plan, now); err != nil {
+			// Another replica evaluated the plan first
+			if errors.Is(err, domain.ErrVersionConflict) {
+				continue
+			}
+			s.logger.Error("failed to evaluate plan",
+				"plan_id", plan.ID,
+				"error", err,
+			)
+			continue
+		}
+		evaluated++
+	}
+
+	return evaluated
+}
+
+func (s *PlanService) evaluate(ctx context.Context, plan *domain.Plan, now time.Time) error {
+	step := plan.Step()
+	if step.NotificationID == nil {
+		if err := s.startStep(ctx, plan, now); err != nil {
+			return s.failIfPermanent(ctx, plan, err, now)
+		}
+		return s.repo.Update(ctx, plan)
+	}
+
+	statuses, notification, err := s.stepStatuses(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	if !plan.Observe(statuses, now) {
+		if plan.Status.IsFinal() {
+			if notification.CanCancel() {
+				s.stopStep(ctx, notification.ID)
+			}
+			s.logger.Info("plan completed",
+				"plan_id", plan.ID,
+				"status", plan.Status,
+				"step", plan.CurrentStep,
+			)
+		}
+		return s.repo.Update(ctx, plan)
+	}
+
+	// A step that was already sent cannot be stopped and may still arrive
+	if notification.CanCancel() {
+		s.stopStep(ctx, notification.ID)
+	}
+	if err := plan.Advance(); err != nil {
+		return err
+	}
+	if err := s.startStep(ctx, plan, now); err != nil {
+		return s.failIfPermanent(ctx, plan, err, now)
+	}
+
+	s.logger.Info("plan fell back to next step",
+		"plan_id", plan.ID,
+		"step", plan.CurrentStep,
+		"channel", plan.Step().Channel,
+		"previous_status", notification.Status,
+	)
+
+	return s.repo.Update(ctx, plan)
+}
+
+// failIfPermanent fails the plan when its current step's notification can
+// never be created, such as when its template was deleted, so it is not
+// retried on every tick. Other errors are returned to retry on the next one.
+func (s *PlanService) failIfPermanent(ctx context.Context, plan *domain.Plan, err error, now time.Time) error {
+	if !isPermanentCreateError(err) {
+		return err
+	}
+	if failErr := plan.Fail(err.Error(), now); failErr != nil {
+		return failErr
+	}
+	if updateErr := s.repo.Update(ctx, plan); updateErr != nil {
+		return updateErr
+	}
+	s.logger.Warn("plan failed, step notification cannot be created",
+		"plan_id", plan.ID,
+		"step", plan.CurrentStep,
+		"error", err,
+	)
+	return nil
+}
+
+// stepStatuses returns the statuses of the plan's started steps'
+// notifications, indexed by step, and the current step's notification.
+// Steps whose stored status is final are not read again.
+func (s *PlanService) stepStatuses(ctx context.Context, plan *domain.Plan) ([]domain.Status, *domain.Notification, error) {
+	statuses := make([]domain.Status, 0, plan.CurrentStep+1)
+	var current *domain.Notification
+	for i, step := range plan.Steps[:plan.CurrentStep+1] {
+		if step.NotificationID == nil || (i < plan.CurrentStep && step.Status.IsFinal()) {
+			statuses = append(statuses, step.Status)
+			continue
+		}
+		notification, err := s.notifications.GetByID(ctx, *step.NotificationID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get step notification: %w", err)
+		}
+		statuses = append(statuses, notification.Status)
+		current = notification
+	}
+	return statuses, current, nil
+}
+
+// startStep creates the notification of the plan's current step
+func (s *PlanService) startStep(ctx context.Context, plan *domain.Plan, now time.Time) error {
+	step := plan.Step()
+
+	metadata := make(map[string]any, len(plan.Metadata)+2)
+	for k, v := range plan.Metadata {
+		metadata[k] = v
+	}
+	metadata[domain.PlanMetadataKey] = plan.ID.String()
+	metadata[domain.PlanStepMetadataKey] = plan.CurrentStep
+
+	content, templateName := stepContent(plan, step)
+	key := plan.StepKey()
+	notification, err := s.notifications.Create(ctx, CreateRequest{
+		Recipient:      step.Recipient,
+		Channel:        step.Channel,
+		Content:        content,
+		Priority:       plan.Priority,
+		IdempotencyKey: &key,
+		Metadata:       metadata,
+		TemplateName:   templateName,
+		TemplateVars:   plan.TemplateVars,
+		Category:       plan.Category,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	plan.StartStep(notification.ID, notification.Status, now)
+	return nil
+}
+
+// stopStep cancels a step's notification that no worker has picked up
+func (s *PlanService) stopStep(ctx context.Context, notificationID uuid.UUID) {
+	err := s.notifications.Cancel(ctx, notificationID)
+	if err != nil && !errors.Is(err, domain.ErrCannotCancel) {
+		s.logger.Warn("failed to cancel plan step notification",
+			"notification_id", notificationID,
+			"error", err,
+		)
+	}
+}
+
+// stepContent returns the content or template of a step, falling back to
+// the plan's
+func stepContent(plan *domain.Plan, step *domain.PlanStep) (string, *string) {
+	switch {
+	case step.Content != nil:
+		return *step.Content, nil
+	case step.TemplateName != nil:
+		return "", step.TemplateName
+	}
+	return plan.Content, plan.TemplateName
+}
+
+// validate checks the plan's steps and the content each of them sends, and
+// normalizes the steps' recipients
+func (s *PlanService) validate(ctx context.Context, plan *domain.Plan) error {
+	if len(plan.Steps) == 0 {
+		return domain.NewValidationError("steps", "at least one step is required")
+	}
+	if len(plan.Steps) > maxPlanSteps {
+		return domain.NewValidationError("steps", fmt.Sprintf("a plan has at most %d steps", maxPlanSteps))
+	}
+	if !plan.Priority.IsValid() {
+		return domain.NewValidationError("priority", "invalid priority")
+	}
+	if err := domain.ValidateCategory(plan.Category); err != nil {
+		return err
+	}
+
+	for i, step := range plan.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		if !step.Channel.IsValid() {
+			return domain.NewValidationError(field+".channel", "invalid channel")
+		}
+		if step.Recipient == "" {
+			return domain.NewValidationError(field+".recipient", "recipient is required")
+		}
+		recipient, err := s.notifications.normalizeRecipient(step.Channel, step.Recipient)
+		if err != nil {
+			var validationErr domain.ValidationError
+			if errors.As(err, &validationErr) {
+				return domain.NewValidationError(field+"."+validationErr.Field, validationErr.Message)
+			}
+			return err
+		}
+		step.Recipient = recipient
+		if step.Timeout < 1 {
+			return domain.NewValidationError(field+".timeout", "timeout must be at least 1 second")
+		}
+
+		content, templateName := stepContent(plan, step)
+		if templateName != nil {
+			template, err := s.templateRepo.GetByName(ctx, *templateName)
+			if err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return domain.ErrTemplateNotFound
+				}
+				return fmt.Errorf("failed to get template: %w", err)
+			}
+			if missing := template.Validate(plan.TemplateVars); len(missing) > 0 {
+				return fmt.Errorf("%w: %v", domain.ErrMissingVariables, missing)
+			}
+			content = template.Render(plan.TemplateVars)
+		}
+
+		if content == "" {
+			return domain.NewValidationError(field+".content", "content is required")
+		}
+		if err := validateContentLength(step.Channel, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
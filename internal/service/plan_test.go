@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockPlanRepository is a mock implementation of domain.PlanRepository
+type MockPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlanRepository) Create(ctx context.Context, p *domain.Plan) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Plan), args.Error(1)
+}
+
+func (m *MockPlanRepository) Update(ctx context.Context, p *domain.Plan) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPlanRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.Plan, error) {
+	args := m.Called(ctx, t, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Plan), args.Error(1)
+}
+
+func TestPlanService_Create(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("creates the plan and starts its first step", func(t *testing.T) {
+		mockRepo := new(MockPlanRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		notifications := NewNotificationService(mockNotificationRepo, new(MockTemplateRepository), mockQueue, logger)
+		service := NewPlanService(mockRepo, new(MockTemplateRepository), notifications, logger)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Plan")).Return(nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, mock.AnythingOfType("string")).Return(nil, domain.ErrNotFound).Once()
+		mockNotificationRepo.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Channel == domain.ChannelPush && n.Metadata[domain.PlanStepMetadataKey] == 0
+		})).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockNotificationRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Plan")).Return(nil).Once()
+
+		plan, err := service.Create(ctx, CreatePlanRequest{
+			Content:  "Server db-1 is down",
+			Priority: domain.PriorityHigh,
+			Steps: []PlanStepRequest{
+				{Channel: domain.ChannelPush, Recipient: "device-token", Timeout: 300},
+				{Channel: domain.ChannelSMS, Recipient: "+905551234567", Timeout: 600},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PlanActive, plan.Status)
+		assert.Equal(t, 0, plan.CurrentStep)
+		require.NotNil(t, plan.Step().NotificationID)
+		require.NotNil(t, plan.StepDeadline)
+		mockNotificationRepo.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("every step needs content", func(t *testing.T) {
+		notifications := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+		service := NewPlanService(new(MockPlanRepository), new(MockTemplateRepository), notifications, logger)
+
+		_, err := service.Create(ctx, CreatePlanRequest{
+			Steps: []PlanStepRequest{
+				{Channel: domain.ChannelPush, Recipient: "device-token", Timeout: 300},
+			},
+		})
+
+		var validationErr domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "steps[0].content", validationErr.Field)
+	})
+
+	t.Run("category too long", func(t *testing.T) {
+		notifications := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+		service := NewPlanService(new(MockPlanRepository), new(MockTemplateRepository), notifications, logger)
+
+		_, err := service.Create(ctx, CreatePlanRequest{
+			Content:  "Server db-1 is down",
+			Category: strings.Repeat("c", domain.MaxCategoryLength+1),
+			Steps: []PlanStepRequest{
+				{Channel: domain.ChannelPush, Recipient: "device-token", Timeout: 300},
+			},
+		})
+
+		var validationErr domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "category", validationErr.Field)
+	})
+}
+
+func TestPlanService_EvaluateDue(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	newStartedPlan := func(now time.Time, first *domain.Notification) *domain.Plan {
+		plan := domain.NewPlan([]*domain.PlanStep{
+			{Channel: domain.ChannelPush, Recipient: "device-token", Timeout: 300},
+			{Channel: domain.ChannelSMS, Recipient: "+905551234567", Timeout: 600},
+		})
+		plan.Content = "Server db-1 is down"
+		plan.StartStep(first.ID, first.Status, now.Add(-time.Minute))
+		return plan
+	}
+
+	t.Run("falls back to the next step when a step fails", func(t *testing.T) {
+		mockRepo := new(MockPlanRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		notifications := NewNotificationService(mockNotificationRepo, new(MockTemplateRepository), mockQueue, logger)
+		service := NewPlanService(mockRepo, new(MockTemplateRepository), notifications, logger)
+
+		now := time.Now().UTC()
+		push := domain.NewNotification("device-token", domain.ChannelPush, "Server db-1 is down")
+		push.Status = domain.StatusFailed
+		plan := newStartedPlan(now, push)
+
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Plan{plan}, nil).Once()
+		mockNotificationRepo.On("GetByID", ctx, push.ID).Return(push, nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, "plan:"+plan.ID.String()+":1").Return(nil, domain.ErrNotFound).Once()
+		mockNotificationRepo.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Channel == domain.ChannelSMS && n.Metadata[domain.PlanMetadataKey] == plan.ID.String()
+		})).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockNotificationRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+		mockRepo.On("Update", ctx, plan).Return(nil).Once()
+
+		evaluated := service.EvaluateDue(ctx, now)
+
+		assert.Equal(t, 1, evaluated)
+		assert.Equal(t, 1, plan.CurrentStep)
+		assert.Equal(t, domain.StatusFailed, plan.Steps[0].Status)
+		assert.NotNil(t, plan.Step().NotificationID)
+		assert.Equal(t, now.Add(10*time.Minute), *plan.StepDeadline)
+		mockNotificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("completes the plan when a step is delivered", func(t *testing.T) {
+		mockRepo := new(MockPlanRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		notifications := NewNotificationService(mockNotificationRepo, new(MockTemplateRepository), new(MockQueue), logger)
+		service := NewPlanService(mockRepo, new(MockTemplateRepository), notifications, logger)
+
+		now := time.Now().UTC()
+		push := domain.NewNotification("device-token", domain.ChannelPush, "Server db-1 is down")
+		push.Status = domain.StatusDelivered
+		plan := newStartedPlan(now, push)
+
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Plan{plan}, nil).Once()
+		mockNotificationRepo.On("GetByID", ctx, push.ID).Return(push, nil).Once()
+		mockRepo.On("Update", ctx, plan).Return(nil).Once()
+
+		service.EvaluateDue(ctx, now)
+
+		assert.Equal(t, domain.PlanDelivered, plan.Status)
+		assert.Equal(t, 0, plan.CurrentStep)
+		mockNotificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fails the plan when a step cannot be created", func(t *testing.T) {
+		mockRepo := new(MockPlanRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		mockTemplateRepo := new(MockTemplateRepository)
+		notifications := NewNotificationService(mockNotificationRepo, mockTemplateRepo, new(MockQueue), logger)
+		service := NewPlanService(mockRepo, mockTemplateRepo, notifications, logger)
+
+		now := time.Now().UTC()
+		templateName := "outage"
+		plan := domain.NewPlan([]*domain.PlanStep{
+			{Channel: domain.ChannelPush, Recipient: "device-token", Timeout: 300},
+		})
+		plan.TemplateName = &templateName
+
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Plan{plan}, nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, plan.StepKey()).Return(nil, domain.ErrNotFound).Once()
+		mockTemplateRepo.On("GetByName", ctx, templateName).Return(nil, domain.ErrNotFound).Once()
+		mockRepo.On("Update", ctx, plan).Return(nil).Once()
+
+		assert.Equal(t, 1, service.EvaluateDue(ctx, now))
+
+		assert.Equal(t, domain.PlanFailed, plan.Status)
+		require.NotNil(t, plan.Error)
+		assert.Contains(t, *plan.Error, "template not found")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("completes the plan when an earlier step is delivered late", func(t *testing.T) {
+		mockRepo := new(MockPlanRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		notifications := NewNotificationService(mockNotificationRepo, new(MockTemplateRepository), mockQueue, logger)
+		service := NewPlanService(mockRepo, new(MockTemplateRepository), notifications, logger)
+
+		now := time.Now().UTC()
+		push := domain.NewNotification("device-token", domain.ChannelPush, "Server db-1 is down")
+		push.Status = domain.StatusSent
+		plan := newStartedPlan(now, push)
+		require.NoError(t, plan.Advance())
+		sms := domain.NewNotification("+905551234567", domain.ChannelSMS, "Server db-1 is down")
+		sms.Status = domain.StatusQueued
+		plan.StartStep(sms.ID, sms.Status, now.Add(-time.Minute))
+		push.Status = domain.StatusDelivered
+
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Plan{plan}, nil).Once()
+		mockNotificationRepo.On("GetByID", ctx, push.ID).Return(push, nil).Once()
+		mockNotificationRepo.On("GetByID", ctx, sms.ID).Return(sms, nil)
+		mockQueue.On("Remove", ctx, mock.Anything).Return(nil)
+		mockNotificationRepo.On("Update", ctx, sms).Return(nil)
+		mockRepo.On("Update", ctx, plan).Return(nil).Once()
+
+		service.EvaluateDue(ctx, now)
+
+		assert.Equal(t, domain.PlanDelivered, plan.Status)
+		assert.Equal(t, domain.StatusDelivered, plan.Steps[0].Status)
+		assert.Equal(t, domain.StatusCancelled, sms.Status)
+		mockRepo.AssertExpectations(t)
+	})
+}
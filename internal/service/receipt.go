@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// ReceiptService applies provider delivery receipts to notifications
+type ReceiptService struct {
+	repo             domain.NotificationRepository
+	logger           *slog.Logger
+	statusBroadcast  func(notification *domain.Notification)
+	deliveryObserver func(notification *domain.Notification)
+	suppressions     domain.SuppressionRepository
+}
+
+// NewReceiptService creates a new ReceiptService
+func NewReceiptService(repo domain.NotificationRepository, logger *slog.Logger) *ReceiptService {
+	return &ReceiptService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SetStatusBroadcast sets the function to broadcast status updates
+func (s *ReceiptService) SetStatusBroadcast(fn func(notification *domain.Notification)) {
+	s.statusBroadcast = fn
+}
+
+// SetDeliveryObserver sets a function called for every notification marked delivered
+func (s *ReceiptService) SetDeliveryObserver(fn func(notification *domain.Notification)) {
+	s.deliveryObserver = fn
+}
+
+// SetSuppressions sets the suppression list that undeliverable emails are
+// added to as bounces
+func (s *ReceiptService) SetSuppressions(repo domain.SuppressionRepository) {
+	s.suppressions = repo
+}
+
+// ReceiptResult summarizes how a batch of receipts was applied
+type ReceiptResult struct {
+	Received int `json:"received"`
+	Updated  int `json:"updated"`
+	Ignored  int `json:"ignored"`
+	Unknown  int `json:"unknown"`
+}
+
+// Process applies receipts reported by provider. Receipts for unknown
+// messages are counted and skipped, and receipts that arrive after a final
+// status (duplicates or out of order) are ignored.
+func (s *ReceiptService) Process(ctx context.Context, provider string, receipts []*domain.DeliveryReceipt) (*ReceiptResult, error) {
+	result := &ReceiptResult{Received: len(receipts)}
+
+	for _, receipt := range receipts {
+		n, err := s.repo.GetByExternalID(ctx, provider, receipt.ExternalID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				s.logger.Warn("receipt for unknown message",
+					"provider", provider,
+					"external_id", receipt.ExternalID,
+				)
+				result.Unknown++
+				continue
+			}
+			return nil, err
+		}
+
+		if !applyReceipt(n, receipt) {
+			result.Ignored++
+			continue
+		}
+
+		if err := s.repo.Update(ctx, n); err != nil {
+			// A concurrent receipt already applied a final status
+			if errors.Is(err, domain.ErrVersionConflict) {
+				result.Ignored++
+				continue
+			}
+			return nil, err
+		}
+		result.Updated++
+
+		s.logger.Info("delivery receipt applied",
+			"notification_id", n.ID,
+			"provider", provider,
+			"status", n.Status,
+		)
+
+		if s.statusBroadcast != nil {
+			s.statusBroadcast(n)
+		}
+		if n.Status == domain.StatusDelivered && s.deliveryObserver != nil {
+			s.deliveryObserver(n)
+		}
+		if n.Status == domain.StatusUndeliverable {
+			s.suppressBounce(ctx, n, receipt.Reason)
+		}
+	}
+
+	return result, nil
+}
+
+// suppressBounce adds the recipient of an undeliverable email to the
+// suppression list so later sends to the address are not attempted
+func (s *ReceiptService) suppressBounce(ctx context.Context, n *domain.Notification, reason string) {
+	if s.suppressions == nil || n.Channel != domain.ChannelEmail {
+		return
+	}
+
+	suppression := domain.NewSuppression(domain.SuppressionRecipient(n.Channel, n.Recipient, ""), n.Channel, domain.SuppressionBounce)
+	if reason != "" {
+		suppression.Note = &reason
+	}
+	if err := s.suppressions.Upsert(ctx, suppression); err != nil {
+		s.logger.Error("failed to suppress bounced recipient",
+			"notification_id", n.ID,
+			"error", err,
+		)
+	}
+}
+
+// applyReceipt moves a sent notification to its final delivery status
+func applyReceipt(n *domain.Notification, receipt *domain.DeliveryReceipt) bool {
+	switch receipt.Status {
+	case domain.ReceiptDelivered:
+		deliveredAt := time.Now().UTC()
+		if receipt.OccurredAt != nil {
+			deliveredAt = *receipt.OccurredAt
+		}
+		return n.MarkAsDelivered(deliveredAt) == nil
+	case domain.ReceiptUndeliverable:
+		return n.MarkAsUndeliverable(receipt.Reason) == nil
+	}
+
+	return false
+}
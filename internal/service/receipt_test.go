@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+func TestReceiptService_Process(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	sentAt := time.Now().UTC().Add(-time.Minute)
+	newSent := func() *domain.Notification {
+		n := domain.NewNotification("+905551234567", domain.ChannelSMS, "Hi")
+		n.Status = domain.StatusSent
+		n.SentAt = &sentAt
+		return n
+	}
+
+	delivered := newSent()
+	undeliverable := newSent()
+	alreadyDelivered := newSent()
+	alreadyDelivered.MarkAsDelivered(time.Now())
+
+	repo := new(MockNotificationRepository)
+	repo.On("GetByExternalID", ctx, "acme", "a").Return(delivered, nil)
+	repo.On("GetByExternalID", ctx, "acme", "b").Return(undeliverable, nil)
+	repo.On("GetByExternalID", ctx, "acme", "c").Return(alreadyDelivered, nil)
+	repo.On("GetByExternalID", ctx, "acme", "missing").Return(nil, domain.ErrNotFound)
+	repo.On("Update", ctx, mock.Anything).Return(nil)
+
+	svc := NewReceiptService(repo, logger)
+	var broadcast, observed []*domain.Notification
+	svc.SetStatusBroadcast(func(n *domain.Notification) { broadcast = append(broadcast, n) })
+	svc.SetDeliveryObserver(func(n *domain.Notification) { observed = append(observed, n) })
+
+	occurredAt := time.Now().UTC()
+	result, err := svc.Process(ctx, "acme", []*domain.DeliveryReceipt{
+		{ExternalID: "a", Status: domain.ReceiptDelivered, OccurredAt: &occurredAt},
+		{ExternalID: "b", Status: domain.ReceiptUndeliverable, Reason: "absent subscriber"},
+		{ExternalID: "c", Status: domain.ReceiptDelivered},
+		{ExternalID: "missing", Status: domain.ReceiptDelivered},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &ReceiptResult{Received: 4, Updated: 2, Ignored: 1, Unknown: 1}, result)
+
+	assert.Equal(t, domain.StatusDelivered, delivered.Status)
+	assert.Equal(t, occurredAt, *delivered.DeliveredAt)
+	assert.Equal(t, domain.StatusUndeliverable, undeliverable.Status)
+	assert.Equal(t, "absent subscriber", *undeliverable.ErrorMessage)
+
+	assert.Len(t, broadcast, 2)
+	assert.Equal(t, []*domain.Notification{delivered}, observed)
+	repo.AssertNumberOfCalls(t, "Update", 2)
+}
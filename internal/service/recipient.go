@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const maxRecipientImportSize = 10000
+
+// RecipientService manages the contact profiles notifications can be
+// addressed to by user ID
+type RecipientService struct {
+	repo        domain.RecipientRepository
+	logger      *slog.Logger
+	phoneRegion string
+}
+
+// NewRecipientService creates a new RecipientService
+func NewRecipientService(repo domain.RecipientRepository, logger *slog.Logger) *RecipientService {
+	return &RecipientService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SetPhoneRegion sets the region phone numbers given in national format are
+// read in
+func (s *RecipientService) SetPhoneRegion(region string) {
+	s.phoneRegion = region
+}
+
+// RecipientRequest represents the profile of a user
+type RecipientRequest struct {
+	UserID       string   `json:"user_id" validate:"required,max=255"`
+	Phone        *string  `json:"phone,omitempty"`
+	Email        *string  `json:"email,omitempty"`
+	DeviceTokens []string `json:"device_tokens,omitempty"`
+	Locale       string   `json:"locale,omitempty"`
+	TimeZone     string   `json:"timezone,omitempty"`
+}
+
+// ImportRecipientsRequest represents a bulk upsert of recipients
+type ImportRecipientsRequest struct {
+	Recipients []RecipientRequest `json:"recipients" validate:"required,min=1,max=10000,dive"`
+}
+
+// Create creates the profile of a user, returning domain.ErrAlreadyExists
+// when the user already has one
+func (s *RecipientService) Create(ctx context.Context, req RecipientRequest) (*domain.Recipient, error) {
+	recipient, err := newRecipient(req, s.phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, recipient); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("recipient created", "user_id", recipient.UserID)
+
+	return recipient, nil
+}
+
+// Update replaces the profile of a user
+func (s *RecipientService) Update(ctx context.Context, req RecipientRequest) (*domain.Recipient, error) {
+	existing, err := s.repo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := newRecipient(req, s.phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+	recipient.CreatedAt = existing.CreatedAt
+
+	if err := s.repo.Upsert(ctx, recipient); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("recipient updated", "user_id", recipient.UserID)
+
+	return recipient, nil
+}
+
+// Import creates or replaces many profiles at once. Nothing is stored if any
+// entry is invalid.
+func (s *RecipientService) Import(ctx context.Context, req ImportRecipientsRequest) (*ImportResult, error) {
+	if len(req.Recipients) > maxRecipientImportSize {
+		return nil, domain.NewValidationError("recipients", fmt.Sprintf("at most %d recipients can be imported at once", maxRecipientImportSize))
+	}
+
+	recipients := make([]*domain.Recipient, 0, len(req.Recipients))
+	var errs domain.ValidationErrors
+	for i, item := range req.Recipients {
+		recipient, err := newRecipient(item, s.phoneRegion)
+		if err != nil {
+			var validationErr domain.ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr.Field = fmt.Sprintf("recipients[%d].%s", i, validationErr.Field)
+				errs.Errors = append(errs.Errors, validationErr)
+				continue
+			}
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(errs.Errors) > 0 {
+		return nil, errs
+	}
+
+	if err := s.repo.UpsertBatch(ctx, recipients); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("recipients imported", "count", len(recipients))
+
+	return &ImportResult{Imported: len(recipients)}, nil
+}
+
+// Get retrieves the profile of a user
+func (s *RecipientService) Get(ctx context.Context, userID string) (*domain.Recipient, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// List lists profiles with pagination
+func (s *RecipientService) List(ctx context.Context, filter domain.RecipientFilter) (*domain.RecipientListResult, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Delete removes the profile of a user
+func (s *RecipientService) Delete(ctx context.Context, userID string) error {
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("recipient deleted", "user_id", userID)
+
+	return nil
+}
+
+// newRecipient builds the profile of req with its addresses normalized
+func newRecipient(req RecipientRequest, phoneRegion string) (*domain.Recipient, error) {
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, domain.NewValidationError("user_id", "user_id is required")
+	}
+	if len(userID) > 255 {
+		return nil, domain.NewValidationError("user_id", "user_id must be at most 255 characters")
+	}
+	if req.TimeZone != "" {
+		if err := domain.ValidateTimeZone(req.TimeZone); err != nil {
+			return nil, err
+		}
+	}
+
+	recipient := domain.NewRecipient(userID)
+	phone, err := normalizeAddress(domain.ChannelSMS, req.Phone, "phone", phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+	email, err := normalizeAddress(domain.ChannelEmail, req.Email, "email", phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+	recipient.Phone = phone
+	recipient.Email = email
+	for i := range req.DeviceTokens {
+		token, err := normalizeAddress(domain.ChannelPush, &req.DeviceTokens[i], fmt.Sprintf("device_tokens[%d]", i), phoneRegion)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			recipient.DeviceTokens = append(recipient.DeviceTokens, *token)
+		}
+	}
+	recipient.Locale = req.Locale
+	recipient.TimeZone = req.TimeZone
+
+	return recipient, nil
+}
+
+// optionalAddress trims an address, treating a blank one as absent
+func optionalAddress(address *string) *string {
+	if address == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*address)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// normalizeAddress normalizes an optional profile address on channel,
+// reporting an invalid one against field
+func normalizeAddress(channel domain.Channel, address *string, field, phoneRegion string) (*string, error) {
+	trimmed := optionalAddress(address)
+	if trimmed == nil {
+		return nil, nil
+	}
+
+	normalized, err := domain.NormalizeRecipient(channel, *trimmed, phoneRegion)
+	if err != nil {
+		var validationErr domain.ValidationError
+		if errors.As(err, &validationErr) {
+			validationErr.Field = field
+			return nil, validationErr
+		}
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+// applyRecipient addresses a request that names a user ID to the user's
+// address on the request's channel, taken from profiles. The profile's time
+// zone is used when the request has none.
+func applyRecipient(req *CreateRequest, profiles map[string]*domain.Recipient) error {
+	if req.UserID == nil {
+		if req.Recipient == "" {
+			return domain.NewValidationError("recipient", "recipient or user_id is required")
+		}
+		return nil
+	}
+	if req.Recipient != "" {
+		return domain.NewValidationError("user_id", "recipient and user_id are mutually exclusive")
+	}
+
+	profile, ok := profiles[*req.UserID]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrRecipientNotFound, *req.UserID)
+	}
+
+	address, err := profile.Address(req.Channel)
+	if err != nil {
+		return err
+	}
+	req.Recipient = address
+	if req.TimeZone == "" {
+		req.TimeZone = profile.TimeZone
+	}
+
+	return nil
+}
+
+// resolveRecipient addresses req like applyRecipient and normalizes the
+// address for req's channel
+func (s *NotificationService) resolveRecipient(req *CreateRequest, profiles map[string]*domain.Recipient) error {
+	if err := applyRecipient(req, profiles); err != nil {
+		return err
+	}
+
+	recipient, err := s.normalizeRecipient(req.Channel, req.Recipient)
+	if err != nil {
+		return err
+	}
+	req.Recipient = recipient
+	return nil
+}
+
+// normalizeRecipient validates and normalizes an address on channel
+func (s *NotificationService) normalizeRecipient(channel domain.Channel, recipient string) (string, error) {
+	return domain.NormalizeRecipient(channel, recipient, s.phoneRegion)
+}
+
+// lookupRecipients returns the profiles of the users requests are addressed
+// to, keyed by user ID
+func (s *NotificationService) lookupRecipients(ctx context.Context, reqs []CreateRequest) (map[string]*domain.Recipient, error) {
+	userIDs := make([]string, 0)
+	for _, req := range reqs {
+		if req.UserID != nil {
+			userIDs = append(userIDs, *req.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	if s.recipients == nil {
+		return nil, domain.NewValidationError("user_id", "recipient profiles are not enabled")
+	}
+
+	profiles, err := s.recipients.FindByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+	return profiles, nil
+}
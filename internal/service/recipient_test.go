@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockRecipientRepository is a mock implementation of domain.RecipientRepository
+type MockRecipientRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecipientRepository) Create(ctx context.Context, r *domain.Recipient) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRecipientRepository) Upsert(ctx context.Context, r *domain.Recipient) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRecipientRepository) UpsertBatch(ctx context.Context, recipients []*domain.Recipient) error {
+	args := m.Called(ctx, recipients)
+	return args.Error(0)
+}
+
+func (m *MockRecipientRepository) GetByUserID(ctx context.Context, userID string) (*domain.Recipient, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Recipient), args.Error(1)
+}
+
+func (m *MockRecipientRepository) List(ctx context.Context, filter domain.RecipientFilter) (*domain.RecipientListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RecipientListResult), args.Error(1)
+}
+
+func (m *MockRecipientRepository) Delete(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRecipientRepository) FindByUserIDs(ctx context.Context, userIDs []string) (map[string]*domain.Recipient, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*domain.Recipient), args.Error(1)
+}
+
+func TestRecipientService_Import(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("imports profiles", func(t *testing.T) {
+		repo := new(MockRecipientRepository)
+		repo.On("UpsertBatch", ctx, mock.MatchedBy(func(r []*domain.Recipient) bool {
+			return len(r) == 2 && r[0].UserID == "user-1" && r[0].Email == nil && *r[1].Email == "b@example.com"
+		})).Return(nil).Once()
+
+		svc := NewRecipientService(repo, logger)
+		blank := " "
+		email := "b@example.com"
+		result, err := svc.Import(ctx, ImportRecipientsRequest{Recipients: []RecipientRequest{
+			{UserID: " user-1", Email: &blank, DeviceTokens: []string{"device-token"}},
+			{UserID: "user-2", Email: &email},
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects the whole import when an entry is invalid", func(t *testing.T) {
+		repo := new(MockRecipientRepository)
+		svc := NewRecipientService(repo, logger)
+		phone := "555 123"
+
+		_, err := svc.Import(ctx, ImportRecipientsRequest{Recipients: []RecipientRequest{
+			{UserID: "user-1"},
+			{UserID: ""},
+			{UserID: "user-3", TimeZone: "Mars/Olympus"},
+			{UserID: "user-4", Phone: &phone},
+		}})
+
+		var validationErrs domain.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		require.Len(t, validationErrs.Errors, 3)
+		assert.Equal(t, "recipients[1].user_id", validationErrs.Errors[0].Field)
+		assert.Equal(t, "recipients[2].timezone", validationErrs.Errors[1].Field)
+		assert.Equal(t, "recipients[3].phone", validationErrs.Errors[2].Field)
+		repo.AssertNotCalled(t, "UpsertBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_CreateForUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	phone := "+905551234567"
+	profile := domain.NewRecipient("user-42")
+	profile.Phone = &phone
+	profile.TimeZone = "Europe/Istanbul"
+	userID := "user-42"
+
+	t.Run("resolves the address for the channel", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		recipients := new(MockRecipientRepository)
+		svc := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+		svc.SetRecipients(recipients)
+
+		recipients.On("FindByUserIDs", ctx, []string{userID}).Return(map[string]*domain.Recipient{userID: profile}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+
+		notification, err := svc.Create(ctx, CreateRequest{UserID: &userID, Channel: domain.ChannelSMS, Content: "Hi"})
+
+		require.NoError(t, err)
+		assert.Equal(t, phone, notification.Recipient)
+		assert.Equal(t, "Europe/Istanbul", notification.TimeZone)
+	})
+
+	t.Run("fails when the user has no address on the channel", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		recipients := new(MockRecipientRepository)
+		svc := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+		svc.SetRecipients(recipients)
+
+		recipients.On("FindByUserIDs", ctx, []string{userID}).Return(map[string]*domain.Recipient{userID: profile}, nil).Once()
+
+		_, err := svc.Create(ctx, CreateRequest{UserID: &userID, Channel: domain.ChannelEmail, Content: "Hi"})
+
+		assert.ErrorIs(t, err, domain.ErrNoAddress)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fails for an unknown user in a batch", func(t *testing.T) {
+		recipients := new(MockRecipientRepository)
+		svc := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+		svc.SetRecipients(recipients)
+
+		unknown := "user-7"
+		recipients.On("FindByUserIDs", ctx, []string{userID, unknown}).Return(map[string]*domain.Recipient{userID: profile}, nil).Once()
+
+		_, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: []CreateRequest{
+			{UserID: &userID, Channel: domain.ChannelSMS, Content: "Hi"},
+			{UserID: &unknown, Channel: domain.ChannelSMS, Content: "Hi"},
+		}})
+
+		assert.ErrorIs(t, err, domain.ErrRecipientNotFound)
+		assert.Contains(t, err.Error(), "notification 1")
+	})
+
+	t.Run("rejects both recipient and user_id", func(t *testing.T) {
+		recipients := new(MockRecipientRepository)
+		svc := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+		svc.SetRecipients(recipients)
+
+		recipients.On("FindByUserIDs", ctx, []string{userID}).Return(map[string]*domain.Recipient{userID: profile}, nil).Once()
+
+		_, err := svc.Create(ctx, CreateRequest{Recipient: phone, UserID: &userID, Channel: domain.ChannelSMS, Content: "Hi"})
+
+		var validationErr domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "user_id", validationErr.Field)
+	})
+}
+
+func TestNotificationService_NormalizesRecipients(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("stores the normalized recipient", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		svc := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+		svc.SetPhoneRegion("TR")
+
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Recipient == "+905551234567"
+		})).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+
+		notification, err := svc.Create(ctx, CreateRequest{Recipient: "0555 123 45 67", Channel: domain.ChannelSMS, Content: "Hi"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "+905551234567", notification.Recipient)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports every invalid recipient in a batch", func(t *testing.T) {
+		mockRepo := new(MockNotificationRepository)
+		svc := NewNotificationService(mockRepo, new(MockTemplateRepository), new(MockQueue), logger)
+
+		_, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: []CreateRequest{
+			{Recipient: "+905551234567", Channel: domain.ChannelSMS, Content: "Hi"},
+			{Recipient: "0555 123 45 67", Channel: domain.ChannelSMS, Content: "Hi"},
+			{Recipient: "user@example", Channel: domain.ChannelEmail, Content: "Hi"},
+			{Channel: domain.ChannelPush, Content: "Hi"},
+		}})
+
+		var validationErrs domain.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		require.Len(t, validationErrs.Errors, 3)
+		assert.Equal(t, "notifications[1].recipient", validationErrs.Errors[0].Field)
+		assert.Equal(t, "notifications[2].recipient", validationErrs.Errors[1].Field)
+		assert.Equal(t, "notifications[3].recipient", validationErrs.Errors[2].Field)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+}
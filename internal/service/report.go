@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// ReportService builds aggregate reports over sent notifications
+type ReportService struct {
+	reporter         domain.SpendReporter
+	providerReporter domain.ProviderReporter
+}
+
+// NewReportService creates a new ReportService
+func NewReportService(reporter domain.SpendReporter) *ReportService {
+	return &ReportService{reporter: reporter}
+}
+
+// SetProviderReporter sets the source of provider performance reports
+func (s *ReportService) SetProviderReporter(reporter domain.ProviderReporter) {
+	s.providerReporter = reporter
+}
+
+// SpendReport is the recorded spend for a period
+type SpendReport struct {
+	Rows   []*domain.SpendReportRow `json:"rows"`
+	Totals map[string]float64       `json:"totals"`
+}
+
+// Spend returns recorded costs aggregated by day, channel and the requested grouping,
+// with a total per currency
+func (s *ReportService) Spend(ctx context.Context, filter domain.SpendReportFilter) (*SpendReport, error) {
+	switch filter.GroupBy {
+	case domain.SpendGroupByNone, domain.SpendGroupByBatch, domain.SpendGroupByCampaign:
+	default:
+		return nil, domain.NewValidationError("group_by", "must be one of: batch, campaign")
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, domain.NewValidationError("end_date", "must not be before start_date")
+	}
+
+	rows, err := s.reporter.SpendReport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	for _, row := range rows {
+		totals[row.Currency] += row.Cost
+	}
+
+	return &SpendReport{Rows: rows, Totals: totals}, nil
+}
+
+// ProviderReport is the performance of each provider over a period
+type ProviderReport struct {
+	Rows []*domain.ProviderReportRow `json:"rows"`
+}
+
+// Providers returns attempt counts, error rates and latency per provider and
+// channel, computed from the delivery log
+func (s *ReportService) Providers(ctx context.Context, filter domain.ProviderReportFilter) (*ProviderReport, error) {
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, domain.NewValidationError("end_date", "must not be before start_date")
+	}
+
+	if s.providerReporter == nil {
+		return &ProviderReport{Rows: []*domain.ProviderReportRow{}}, nil
+	}
+
+	rows, err := s.providerReporter.ProviderReport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProviderReport{Rows: rows}, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// routingStatsWindow is the period of the delivery log summarised in the
+// routing overview
+const routingStatsWindow = time.Hour
+
+// RoutingService manages provider traffic splits and keeps every instance's
+// router in sync with the weights stored in the shared RoutingStore
+type RoutingService struct {
+	router   domain.ProviderRouter
+	store    domain.RoutingStore
+	reporter domain.ProviderReporter
+	logger   *slog.Logger
+	interval time.Duration
+
+	mu       sync.Mutex
+	running  bool
+	stopChan chan struct{}
+}
+
+// NewRoutingService creates a new RoutingService
+func NewRoutingService(
+	router domain.ProviderRouter,
+	store domain.RoutingStore,
+	logger *slog.Logger,
+	interval time.Duration,
+) *RoutingService {
+	return &RoutingService{
+		router:   router,
+		store:    store,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// SetProviderReporter sets the delivery log the overview's statistics are
+// computed from
+func (s *RoutingService) SetProviderReporter(reporter domain.ProviderReporter) {
+	s.reporter = reporter
+}
+
+// RoutingOverview describes current routes and per-provider performance
+type RoutingOverview struct {
+	Providers []string                                `json:"providers"`
+	Routes    map[domain.Channel][]domain.RouteWeight `json:"routes"`
+	// Stats summarises the attempts of every instance since StatsSince
+	Stats      []*domain.ProviderReportRow `json:"stats"`
+	StatsSince time.Time                   `json:"stats_since"`
+}
+
+// Overview returns the current routes and the providers' performance over
+// the last routingStatsWindow, aggregated from the delivery log
+func (s *RoutingService) Overview(ctx context.Context) (*RoutingOverview, error) {
+	since := time.Now().UTC().Add(-routingStatsWindow)
+	overview := &RoutingOverview{
+		Providers:  s.router.Providers(),
+		Routes:     s.router.Routes(),
+		Stats:      []*domain.ProviderReportRow{},
+		StatsSince: since,
+	}
+
+	if s.reporter != nil {
+		stats, err := s.reporter.ProviderReport(ctx, domain.ProviderReportFilter{StartDate: &since})
+		if err != nil {
+			return nil, err
+		}
+		overview.Stats = stats
+	}
+
+	return overview, nil
+}
+
+// SetRoutes validates and applies new weights for a channel and persists
+// them so other instances pick them up on their next sync
+func (s *RoutingService) SetRoutes(ctx context.Context, channel domain.Channel, weights []domain.RouteWeight) error {
+	if err := s.router.SetRoutes(channel, weights); err != nil {
+		return err
+	}
+
+	if err := s.store.SetRoutes(ctx, channel, weights); err != nil {
+		return fmt.Errorf("failed to persist routes: %w", err)
+	}
+
+	s.logger.Info("provider routes updated",
+		"channel", channel,
+		"weights", weights,
+	)
+
+	return nil
+}
+
+// Start loads stored routes and keeps syncing them in the background
+func (s *RoutingService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = true
+	s.stopChan = make(chan struct{})
+	s.mu.Unlock()
+
+	s.sync(ctx)
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop stops background syncing
+func (s *RoutingService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
+	}
+
+	close(s.stopChan)
+	s.running = false
+}
+
+func (s *RoutingService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.sync(ctx)
+		}
+	}
+}
+
+// sync applies stored routes over the configured defaults
+func (s *RoutingService) sync(ctx context.Context) {
+	routes, err := s.store.GetRoutes(ctx)
+	if err != nil {
+		s.logger.Error("failed to load provider routes", "error", err)
+		return
+	}
+
+	for channel, weights := range routes {
+		if err := s.router.SetRoutes(channel, weights); err != nil {
+			s.logger.Warn("ignoring invalid stored provider routes",
+				"channel", channel,
+				"error", err,
+			)
+		}
+	}
+}
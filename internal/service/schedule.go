@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// ScheduleService handles recurring schedule business logic
+type ScheduleService struct {
+	repo          domain.ScheduleRepository
+	templateRepo  domain.TemplateRepository
+	notifications *NotificationService
+	logger        *slog.Logger
+	batchSize     int
+}
+
+// NewScheduleService creates a new ScheduleService
+func NewScheduleService(
+	repo domain.ScheduleRepository,
+	templateRepo domain.TemplateRepository,
+	notifications *NotificationService,
+	logger *slog.Logger,
+) *ScheduleService {
+	return &ScheduleService{
+		repo:          repo,
+		templateRepo:  templateRepo,
+		notifications: notifications,
+		logger:        logger,
+		batchSize:     100,
+	}
+}
+
+// CreateScheduleRequest represents a request to create a recurring schedule
+type CreateScheduleRequest struct {
+	Name         string            `json:"name" validate:"required,max=255"`
+	Recipient    string            `json:"recipient" validate:"required"`
+	Channel      domain.Channel    `json:"channel" validate:"required"`
+	TemplateName string            `json:"template_name" validate:"required"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	// Exactly one of Cron and RRule is required
+	Cron     *string `json:"cron,omitempty"`
+	RRule    *string `json:"rrule,omitempty"`
+	TimeZone string  `json:"timezone,omitempty"`
+	// StartsAt defaults to now
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	MaxOccurrences *int       `json:"max_occurrences,omitempty"`
+}
+
+// UpdateScheduleRequest represents a request to update a recurring schedule.
+// Setting Cron clears RRule and vice versa.
+type UpdateScheduleRequest struct {
+	Name           *string           `json:"name,omitempty"`
+	Recipient      *string           `json:"recipient,omitempty"`
+	Channel        *domain.Channel   `json:"channel,omitempty"`
+	TemplateName   *string           `json:"template_name,omitempty"`
+	TemplateVars   map[string]string `json:"template_vars,omitempty"`
+	Priority       *domain.Priority  `json:"priority,omitempty"`
+	Category       *string           `json:"category,omitempty"`
+	Metadata       map[string]any    `json:"metadata,omitempty"`
+	Cron           *string           `json:"cron,omitempty"`
+	RRule          *string           `json:"rrule,omitempty"`
+	TimeZone       *string           `json:"timezone,omitempty"`
+	StartsAt       *time.Time        `json:"starts_at,omitempty"`
+	EndsAt         *time.Time        `json:"ends_at,omitempty"`
+	MaxOccurrences *int              `json:"max_occurrences,omitempty"`
+}
+
+// Create creates a new recurring schedule
+func (s *ScheduleService) Create(ctx context.Context, req CreateScheduleRequest) (*domain.Schedule, error) {
+	schedule := domain.NewSchedule(req.Name, req.Recipient, req.Channel, req.TemplateName)
+	schedule.TemplateVars = req.TemplateVars
+	if req.Priority != "" {
+		schedule.Priority = req.Priority
+	}
+	schedule.Category = req.Category
+	schedule.Metadata = req.Metadata
+	schedule.Cron = req.Cron
+	schedule.RRule = req.RRule
+	if req.TimeZone != "" {
+		schedule.TimeZone = req.TimeZone
+	}
+	if req.StartsAt != nil {
+		schedule.StartsAt = req.StartsAt.UTC()
+	}
+	schedule.EndsAt = req.EndsAt
+	schedule.MaxOccurrences = req.MaxOccurrences
+
+	if err := s.validate(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	if err := schedule.Start(time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if schedule.Status == domain.ScheduleCompleted {
+		return nil, domain.NewValidationError("ends_at", "schedule has no occurrences")
+	}
+
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	s.logger.Info("schedule created",
+		"schedule_id", schedule.ID,
+		"next_run_at", schedule.NextRunAt,
+	)
+
+	return schedule, nil
+}
+
+// GetByID retrieves a schedule by ID
+func (s *ScheduleService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Schedule, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List retrieves schedules with filtering and pagination
+func (s *ScheduleService) List(ctx context.Context, filter domain.ScheduleFilter) (*domain.ScheduleListResult, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Update updates an existing schedule. Changing the recurrence recomputes
+// the next run from now.
+func (s *ScheduleService) Update(ctx context.Context, id uuid.UUID, req UpdateScheduleRequest) (*domain.Schedule, error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		schedule.Name = *req.Name
+	}
+	if req.Recipient != nil {
+		schedule.Recipient = *req.Recipient
+	}
+	if req.Channel != nil {
+		schedule.Channel = *req.Channel
+	}
+	if req.TemplateName != nil {
+		schedule.TemplateName = *req.TemplateName
+	}
+	if req.TemplateVars != nil {
+		schedule.TemplateVars = req.TemplateVars
+	}
+	if req.Priority != nil {
+		schedule.Priority = *req.Priority
+	}
+	if req.Category != nil {
+		schedule.Category = *req.Category
+	}
+	if req.Metadata != nil {
+		schedule.Metadata = req.Metadata
+	}
+	if req.Cron != nil {
+		schedule.Cron, schedule.RRule = req.Cron, nil
+	}
+	if req.RRule != nil {
+		schedule.RRule, schedule.Cron = req.RRule, nil
+	}
+	if req.TimeZone != nil {
+		schedule.TimeZone = *req.TimeZone
+	}
+	if req.StartsAt != nil {
+		schedule.StartsAt = req.StartsAt.UTC()
+	}
+	if req.EndsAt != nil {
+		schedule.EndsAt = req.EndsAt
+	}
+	if req.MaxOccurrences != nil {
+		schedule.MaxOccurrences = req.MaxOccurrences
+	}
+
+	if err := s.validate(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	// A paused schedule keeps its status and is replanned when resumed
+	if schedule.Status != domain.SchedulePaused {
+		if err := schedule.Start(time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	s.logger.Info("schedule updated",
+		"schedule_id", schedule.ID,
+		"next_run_at", schedule.NextRunAt,
+	)
+
+	return schedule, nil
+}
+
+// Delete deletes a schedule. Notifications it already created are kept.
+func (s *ScheduleService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("schedule deleted",
+		"schedule_id", id,
+	)
+
+	return nil
+}
+
+// Pause stops a schedule from creating notifications
+func (s *ScheduleService) Pause(ctx context.Context, id uuid.UUID) (*domain.Schedule, error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := schedule.Pause(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to pause schedule: %w", err)
+	}
+
+	s.logger.Info("schedule paused", "schedule_id", schedule.ID)
+
+	return schedule, nil
+}
+
+// Resume reactivates a paused schedule from its next occurrence
+func (s *ScheduleService) Resume(ctx context.Context, id uuid.UUID) (*domain.Schedule, error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := schedule.Resume(time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to resume schedule: %w", err)
+	}
+
+	s.logger.Info("schedule resumed",
+		"schedule_id", schedule.ID,
+		"next_run_at", schedule.NextRunAt,
+	)
+
+	return schedule, nil
+}
+
+// MaterializeDue creates the notifications of schedules that are due at now
+// and advances them to their next occurrence. Each occurrence is created
+// with an idempotency key derived from the schedule and occurrence time,
+// and schedules are advanced with versioned updates, so replicas running
+// concurrently create every occurrence exactly once.
+func (s *ScheduleService) MaterializeDue(ctx context.Context, now time.Time) int {
+	schedules, err := s.repo.ListDue(ctx, now, s.batchSize)
+	if err != nil {
+		s.logger.Error("failed to get due schedules", "error", err)
+		return 0
+	}
+
+	created := 0
+	for _, schedule := range schedules {
+		if err := s.materialize(ctx, schedule, now); err != nil {
+			// Another replica advanced the schedule first, or the schedule
+			// was paused because its occurrence can never be created
+			if errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, errSchedulePaused) {
+				continue
+			}
+			s.logger.Error("failed to materialize schedule",
+				"schedule_id", schedule.ID,
+				"error", err,
+			)
+			continue
+		}
+		created++
+	}
+
+	if created > 0 {
+		s.logger.Info("scheduled occurrences created", "count", created)
+	}
+
+	return created
+}
+
+// errSchedulePaused reports that materialize paused a schedule instead of
+// creating its occurrence
+var errSchedulePaused = errors.New("schedule paused")
+
+// materialize creates the schedule's due occurrence and advances it. A
+// schedule whose occurrence fails for a reason retrying cannot fix, such as
+// a deleted template or an invalid recipient, is paused with the error
+// rather than retried on every tick.
+func (s *ScheduleService) materialize(ctx context.Context, schedule *domain.Schedule, now time.Time) error {
+	occurrence := *schedule.NextRunAt
+
+	metadata := make(map[string]any, len(schedule.Metadata)+1)
+	for k, v := range schedule.Metadata {
+		metadata[k] = v
+	}
+	metadata[domain.ScheduleMetadataKey] = schedule.ID.String()
+
+	key := schedule.OccurrenceKey(occurrence)
+	templateName := schedule.TemplateName
+	_, err := s.notifications.Create(ctx, CreateRequest{
+		Recipient:      schedule.Recipient,
+		Channel:        schedule.Channel,
+		Priority:       schedule.Priority,
+		IdempotencyKey: &key,
+		Metadata:       metadata,
+		TemplateName:   &templateName,
+		TemplateVars:   schedule.TemplateVars,
+		Category:       schedule.Category,
+		TimeZone:       schedule.TimeZone,
+	})
+	if err != nil {
+		if !isPermanentCreateError(err) {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+		if pauseErr := schedule.PauseWithError(err.Error()); pauseErr != nil {
+			return pauseErr
+		}
+		if updateErr := s.repo.Update(ctx, schedule); updateErr != nil {
+			return updateErr
+		}
+		s.logger.Warn("schedule paused, occurrence cannot be created",
+			"schedule_id", schedule.ID,
+			"error", err,
+		)
+		return errSchedulePaused
+	}
+
+	if err := schedule.Advance(now); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, schedule)
+}
+
+// validate checks the schedule's channel, recipient, template and
+// recurrence, and normalizes the recipient
+func (s *ScheduleService) validate(ctx context.Context, schedule *domain.Schedule) error {
+	if !schedule.Channel.IsValid() {
+		return domain.NewValidationError("channel", "invalid channel")
+	}
+	if err := domain.ValidateCategory(schedule.Category); err != nil {
+		return err
+	}
+	recipient, err := s.notifications.normalizeRecipient(schedule.Channel, schedule.Recipient)
+	if err != nil {
+		return err
+	}
+	schedule.Recipient = recipient
+	if !schedule.Priority.IsValid() {
+		return domain.NewValidationError("priority", "invalid priority")
+	}
+	if schedule.MaxOccurrences != nil && *schedule.MaxOccurrences < 1 {
+		return domain.NewValidationError("max_occurrences", "max_occurrences must be at least 1")
+	}
+	if schedule.EndsAt != nil && !schedule.EndsAt.After(schedule.StartsAt) {
+		return domain.NewValidationError("ends_at", "ends_at must be after starts_at")
+	}
+
+	if _, err := schedule.Recurrence(); err != nil {
+		return err
+	}
+
+	template, err := s.templateRepo.GetByName(ctx, schedule.TemplateName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrTemplateNotFound
+		}
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if missing := template.Validate(schedule.TemplateVars); len(missing) > 0 {
+		return fmt.Errorf("%w: %v", domain.ErrMissingVariables, missing)
+	}
+
+	return nil
+}
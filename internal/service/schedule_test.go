@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockScheduleRepository is a mock implementation of domain.ScheduleRepository
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) Create(ctx context.Context, s *domain.Schedule) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Schedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) List(ctx context.Context, filter domain.ScheduleFilter) (*domain.ScheduleListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ScheduleListResult), args.Error(1)
+}
+
+func (m *MockScheduleRepository) Update(ctx context.Context, s *domain.Schedule) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) ListDue(ctx context.Context, t time.Time, limit int) ([]*domain.Schedule, error) {
+	args := m.Called(ctx, t, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Schedule), args.Error(1)
+}
+
+func TestScheduleService_Create(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mockRepo := new(MockScheduleRepository)
+	mockTemplateRepo := new(MockTemplateRepository)
+	notifications := NewNotificationService(new(MockNotificationRepository), mockTemplateRepo, new(MockQueue), logger)
+	service := NewScheduleService(mockRepo, mockTemplateRepo, notifications, logger)
+
+	template := domain.NewTemplate("reminder", domain.ChannelSMS, "Hi {{name}}, your appointment is tomorrow")
+	mockTemplateRepo.On("GetByName", ctx, "reminder").Return(template, nil)
+
+	t.Run("create schedule successfully", func(t *testing.T) {
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Schedule")).Return(nil).Once()
+
+		cron := "0 9 * * *"
+		schedule, err := service.Create(ctx, CreateScheduleRequest{
+			Name:         "daily reminder",
+			Recipient:    "+905551234567",
+			Channel:      domain.ChannelSMS,
+			TemplateName: "reminder",
+			TemplateVars: map[string]string{"name": "Ada"},
+			Cron:         &cron,
+			TimeZone:     "Europe/Istanbul",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ScheduleActive, schedule.Status)
+		require.NotNil(t, schedule.NextRunAt)
+		assert.True(t, schedule.NextRunAt.After(time.Now()))
+	})
+
+	t.Run("missing template variables", func(t *testing.T) {
+		cron := "0 9 * * *"
+		_, err := service.Create(ctx, CreateScheduleRequest{
+			Name:         "daily reminder",
+			Recipient:    "+905551234567",
+			Channel:      domain.ChannelSMS,
+			TemplateName: "reminder",
+			Cron:         &cron,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrMissingVariables)
+	})
+
+	t.Run("invalid cron expression", func(t *testing.T) {
+		cron := "0 25 * * *"
+		_, err := service.Create(ctx, CreateScheduleRequest{
+			Name:         "daily reminder",
+			Recipient:    "+905551234567",
+			Channel:      domain.ChannelSMS,
+			TemplateName: "reminder",
+			TemplateVars: map[string]string{"name": "Ada"},
+			Cron:         &cron,
+		})
+
+		var validationErr domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestScheduleService_MaterializeDue(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	newDueSchedule := func(t *testing.T, now time.Time) *domain.Schedule {
+		schedule := domain.NewSchedule("hourly", "+905551234567", domain.ChannelSMS, "reminder")
+		cron := "@hourly"
+		schedule.Cron = &cron
+		schedule.TemplateVars = map[string]string{"name": "Ada"}
+		schedule.StartsAt = now.Add(-2 * time.Hour)
+		require.NoError(t, schedule.Start(now.Add(-90*time.Minute)))
+		return schedule
+	}
+
+	t.Run("creates the occurrence and advances the schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		mockTemplateRepo := new(MockTemplateRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		mockQueue := new(MockQueue)
+		notifications := NewNotificationService(mockNotificationRepo, mockTemplateRepo, mockQueue, logger)
+		service := NewScheduleService(mockRepo, mockTemplateRepo, notifications, logger)
+
+		now := time.Now().UTC()
+		schedule := newDueSchedule(t, now)
+		occurrence := *schedule.NextRunAt
+		key := schedule.OccurrenceKey(occurrence)
+
+		template := domain.NewTemplate("reminder", domain.ChannelSMS, "Hi {{name}}")
+		mockTemplateRepo.On("GetByName", ctx, "reminder").Return(template, nil)
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Schedule{schedule}, nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, key).Return(nil, domain.ErrNotFound).Once()
+		mockNotificationRepo.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.Content == "Hi Ada" && n.Metadata[domain.ScheduleMetadataKey] == schedule.ID.String()
+		})).Return(nil).Once()
+		mockQueue.On("Enqueue", ctx, mock.AnythingOfType("*domain.QueueItem")).Return(nil).Once()
+		mockNotificationRepo.On("Update", ctx, mock.AnythingOfType("*domain.Notification")).Return(nil).Once()
+		mockRepo.On("Update", ctx, schedule).Return(nil).Once()
+
+		created := service.MaterializeDue(ctx, now)
+
+		assert.Equal(t, 1, created)
+		assert.Equal(t, 1, schedule.Occurrences)
+		assert.True(t, schedule.NextRunAt.After(now))
+		mockNotificationRepo.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips a schedule another replica advanced", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		mockTemplateRepo := new(MockTemplateRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		notifications := NewNotificationService(mockNotificationRepo, mockTemplateRepo, new(MockQueue), logger)
+		service := NewScheduleService(mockRepo, mockTemplateRepo, notifications, logger)
+
+		now := time.Now().UTC()
+		schedule := newDueSchedule(t, now)
+		key := schedule.OccurrenceKey(*schedule.NextRunAt)
+
+		// The other replica already created the occurrence
+		existing := domain.NewNotification(schedule.Recipient, schedule.Channel, "Hi Ada")
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Schedule{schedule}, nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, key).Return(existing, nil).Once()
+		mockRepo.On("Update", ctx, schedule).Return(domain.ErrVersionConflict).Once()
+
+		created := service.MaterializeDue(ctx, now)
+
+		assert.Equal(t, 0, created)
+		mockNotificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("pauses a schedule whose template was deleted", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+		mockTemplateRepo := new(MockTemplateRepository)
+		mockNotificationRepo := new(MockNotificationRepository)
+		notifications := NewNotificationService(mockNotificationRepo, mockTemplateRepo, new(MockQueue), logger)
+		service := NewScheduleService(mockRepo, mockTemplateRepo, notifications, logger)
+
+		now := time.Now().UTC()
+		schedule := newDueSchedule(t, now)
+		occurrence := *schedule.NextRunAt
+		key := schedule.OccurrenceKey(occurrence)
+
+		mockRepo.On("ListDue", ctx, now, 100).Return([]*domain.Schedule{schedule}, nil).Once()
+		mockNotificationRepo.On("GetByIdempotencyKey", ctx, key).Return(nil, domain.ErrNotFound).Once()
+		mockTemplateRepo.On("GetByName", ctx, "reminder").Return(nil, domain.ErrNotFound).Once()
+		mockRepo.On("Update", ctx, schedule).Return(nil).Once()
+
+		created := service.MaterializeDue(ctx, now)
+
+		assert.Equal(t, 0, created)
+		assert.Equal(t, domain.SchedulePaused, schedule.Status)
+		require.NotNil(t, schedule.LastError)
+		assert.Contains(t, *schedule.LastError, "template not found")
+		assert.Equal(t, occurrence, *schedule.NextRunAt)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestScheduleService_CreateRejectsLongCategory(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	notifications := NewNotificationService(new(MockNotificationRepository), new(MockTemplateRepository), new(MockQueue), logger)
+	service := NewScheduleService(new(MockScheduleRepository), new(MockTemplateRepository), notifications, logger)
+
+	cron := "0 9 * * *"
+	_, err := service.Create(context.Background(), CreateScheduleRequest{
+		Name:         "daily reminder",
+		Recipient:    "+905551234567",
+		Channel:      domain.ChannelSMS,
+		TemplateName: "reminder",
+		Category:     strings.Repeat("c", domain.MaxCategoryLength+1),
+		Cron:         &cron,
+	})
+
+	var validationErr domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "category", validationErr.Field)
+}
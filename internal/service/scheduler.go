@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 	"time"
@@ -16,6 +17,11 @@ type SchedulerService struct {
 	logger           *slog.Logger
 	interval         time.Duration
 	batchSize        int
+	quietHours       *domain.QuietHours
+	schedules        *ScheduleService
+	plans            *PlanService
+	topics           *TopicService
+	digests          *DigestService
 
 	mu       sync.Mutex
 	running  bool
@@ -38,6 +44,33 @@ func NewSchedulerService(
 	}
 }
 
+// SetQuietHours sets the send windows that due notifications are deferred to
+func (s *SchedulerService) SetQuietHours(quietHours *domain.QuietHours) {
+	s.quietHours = quietHours
+}
+
+// SetSchedules sets the recurring schedules whose due occurrences are
+// created on every tick
+func (s *SchedulerService) SetSchedules(schedules *ScheduleService) {
+	s.schedules = schedules
+}
+
+// SetPlans sets the fallback plans that are evaluated on every tick
+func (s *SchedulerService) SetPlans(plans *PlanService) {
+	s.plans = plans
+}
+
+// SetTopics sets the topics whose pending publications are fanned out on
+// every tick
+func (s *SchedulerService) SetTopics(topics *TopicService) {
+	s.topics = topics
+}
+
+// SetDigests sets the digest rules whose due digests are sent on every tick
+func (s *SchedulerService) SetDigests(digests *DigestService) {
+	s.digests = digests
+}
+
 // Start starts the scheduler
 func (s *SchedulerService) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -71,6 +104,7 @@ func (s *SchedulerService) Stop() {
 
 // run is the main scheduler loop
 func (s *SchedulerService) run(ctx context.Context) {
+	ctx = domain.WithActor(ctx, "scheduler")
 	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
@@ -93,6 +127,19 @@ func (s *SchedulerService) run(ctx context.Context) {
 func (s *SchedulerService) processScheduledNotifications(ctx context.Context) {
 	now := time.Now().UTC()
 
+	if s.schedules != nil {
+		s.schedules.MaterializeDue(ctx, now)
+	}
+	if s.plans != nil {
+		s.plans.EvaluateDue(ctx, now)
+	}
+	if s.topics != nil {
+		s.topics.PublishDue(ctx, now)
+	}
+	if s.digests != nil {
+		s.digests.FlushDue(ctx, now)
+	}
+
 	notifications, err := s.notificationRepo.GetScheduledNotifications(ctx, now, s.batchSize)
 	if err != nil {
 		s.logger.Error("failed to get scheduled notifications", "error", err)
@@ -105,6 +152,24 @@ func (s *SchedulerService) processScheduledNotifications(ctx context.Context) {
 
 	s.logger.Info("processing scheduled notifications", "count", len(notifications))
 
+	due := make([]*domain.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if n.IsExpired(now) {
+			s.expire(ctx, n)
+			continue
+		}
+		// Quiet hours may have started since the notification was scheduled
+		if next, deferred := s.quietHours.NextAllowed(n, now); deferred {
+			s.postpone(ctx, n, next)
+			continue
+		}
+		due = append(due, n)
+	}
+	notifications = due
+	if len(notifications) == 0 {
+		return
+	}
+
 	queueItems := make([]*domain.QueueItem, 0, len(notifications))
 	for _, n := range notifications {
 		queueItems = append(queueItems, &domain.QueueItem{
@@ -123,8 +188,18 @@ func (s *SchedulerService) processScheduledNotifications(ctx context.Context) {
 
 	// Update status to queued
 	for _, n := range notifications {
-		n.MarkAsQueued()
+		if err := n.MarkAsQueued(); err != nil {
+			s.logger.Error("failed to mark notification queued",
+				"notification_id", n.ID,
+				"error", err,
+			)
+			continue
+		}
 		if err := s.notificationRepo.Update(ctx, n); err != nil {
+			// A worker already picked the notification up and moved it on
+			if errors.Is(err, domain.ErrVersionConflict) {
+				continue
+			}
 			s.logger.Error("failed to update notification status",
 				"notification_id", n.ID,
 				"error", err,
@@ -134,3 +209,51 @@ func (s *SchedulerService) processScheduledNotifications(ctx context.Context) {
 
 	s.logger.Info("scheduled notifications queued", "count", len(notifications))
 }
+
+// postpone moves a due notification to the end of its recipient's quiet hours
+func (s *SchedulerService) postpone(ctx context.Context, n *domain.Notification, next time.Time) {
+	if err := n.Reschedule(next); err != nil {
+		s.logger.Error("failed to reschedule notification",
+			"notification_id", n.ID,
+			"error", err,
+		)
+		return
+	}
+	if err := s.notificationRepo.Update(ctx, n); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return
+		}
+		s.logger.Error("failed to update notification schedule",
+			"notification_id", n.ID,
+			"error", err,
+		)
+		return
+	}
+	s.logger.Info("scheduled notification deferred for quiet hours",
+		"notification_id", n.ID,
+		"scheduled_at", next,
+	)
+}
+
+// expire moves a scheduled notification whose expiry passed before it was
+// due to expired instead of queueing it
+func (s *SchedulerService) expire(ctx context.Context, n *domain.Notification) {
+	if err := n.MarkAsExpired(); err != nil {
+		s.logger.Error("failed to mark notification expired",
+			"notification_id", n.ID,
+			"error", err,
+		)
+		return
+	}
+	if err := s.notificationRepo.Update(ctx, n); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return
+		}
+		s.logger.Error("failed to update notification status",
+			"notification_id", n.ID,
+			"error", err,
+		)
+		return
+	}
+	s.logger.Info("scheduled notification expired", "notification_id", n.ID)
+}
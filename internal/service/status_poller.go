@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// StatusPollerService polls providers that implement domain.StatusChecker
+// for the delivery status of recently sent notifications
+type StatusPollerService struct {
+	notificationRepo domain.NotificationRepository
+	router           domain.ProviderRouter
+	receipts         *ReceiptService
+	logger           *slog.Logger
+	config           config.PollerConfig
+
+	mu       sync.Mutex
+	running  bool
+	stopChan chan struct{}
+}
+
+// NewStatusPollerService creates a new StatusPollerService
+func NewStatusPollerService(
+	notificationRepo domain.NotificationRepository,
+	router domain.ProviderRouter,
+	receipts *ReceiptService,
+	logger *slog.Logger,
+	cfg config.PollerConfig,
+) *StatusPollerService {
+	return &StatusPollerService{
+		notificationRepo: notificationRepo,
+		router:           router,
+		receipts:         receipts,
+		logger:           logger,
+		config:           cfg,
+	}
+}
+
+// Start starts polling. It is a no-op when no provider supports status checks.
+func (s *StatusPollerService) Start(ctx context.Context) error {
+	if len(s.router.StatusCheckers()) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = true
+	s.stopChan = make(chan struct{})
+	s.mu.Unlock()
+
+	s.logger.Info("status poller started", "interval", s.config.Interval, "max_age", s.config.MaxAge)
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop stops polling
+func (s *StatusPollerService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
+	}
+
+	close(s.stopChan)
+	s.running = false
+	s.logger.Info("status poller stopped")
+}
+
+func (s *StatusPollerService) run(ctx context.Context) {
+	ctx = domain.WithActor(ctx, "status-poller")
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.Poll(ctx)
+		}
+	}
+}
+
+// Poll claims sent notifications that are due for a check and queries their
+// providers in batches, applying the results like delivery receipts
+func (s *StatusPollerService) Poll(ctx context.Context) {
+	checkers := s.router.StatusCheckers()
+	if len(checkers) == 0 {
+		return
+	}
+
+	providers := make([]string, 0, len(checkers))
+	for name := range checkers {
+		providers = append(providers, name)
+	}
+
+	now := time.Now().UTC()
+	notifications, err := s.notificationRepo.ClaimStatusChecks(ctx,
+		providers,
+		now.Add(-s.config.MaxAge),
+		now.Add(-s.config.CheckInterval),
+		s.config.BatchLimit,
+	)
+	if err != nil {
+		s.logger.Error("failed to claim notifications for status check", "error", err)
+		return
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	byProvider := make(map[string][]string)
+	for _, n := range notifications {
+		if n.Provider == nil || n.ExternalID == nil {
+			continue
+		}
+		byProvider[*n.Provider] = append(byProvider[*n.Provider], *n.ExternalID)
+	}
+
+	var wg sync.WaitGroup
+	for name, ids := range byProvider {
+		wg.Add(1)
+		go func(name string, checker domain.StatusChecker, ids []string) {
+			defer wg.Done()
+			s.pollProvider(ctx, name, checker, ids)
+		}(name, checkers[name], ids)
+	}
+	wg.Wait()
+}
+
+// pollProvider checks ids in batches, pacing requests to the configured rate
+// and giving up for this cycle when the provider signals rate limiting
+func (s *StatusPollerService) pollProvider(ctx context.Context, name string, checker domain.StatusChecker, ids []string) {
+	logger := s.logger.With("provider", name)
+
+	var gap time.Duration
+	if s.config.RatePerSec > 0 {
+		gap = time.Second / time.Duration(s.config.RatePerSec)
+	}
+
+	batchSize := checker.StatusBatchSize()
+	for start := 0; start < len(ids); start += batchSize {
+		if start > 0 && gap > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-time.After(gap):
+			}
+		}
+
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		receipts, err := checker.CheckStatus(ctx, ids[start:end])
+		if err != nil {
+			var providerErr domain.ProviderError
+			if errors.As(err, &providerErr) && providerErr.StatusCode == http.StatusTooManyRequests {
+				logger.Warn("provider rate limited status checks, resuming next cycle",
+					"remaining", len(ids)-start,
+				)
+				return
+			}
+			logger.Error("status check failed", "error", err, "batch_size", end-start)
+			continue
+		}
+
+		result, err := s.receipts.Process(ctx, name, receipts)
+		if err != nil {
+			logger.Error("failed to apply polled statuses", "error", err)
+			continue
+		}
+		if result.Updated > 0 {
+			logger.Info("polled statuses applied", "checked", end-start, "updated", result.Updated)
+		}
+	}
+}
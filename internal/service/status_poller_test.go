@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+type stubRouter struct {
+	checkers map[string]domain.StatusChecker
+}
+
+func (r *stubRouter) SetRoutes(domain.Channel, []domain.RouteWeight) error { return nil }
+func (r *stubRouter) Routes() map[domain.Channel][]domain.RouteWeight      { return nil }
+func (r *stubRouter) Providers() []string                                  { return nil }
+func (r *stubRouter) StatusCheckers() map[string]domain.StatusChecker      { return r.checkers }
+
+type stubStatusChecker struct {
+	batchSize int
+	calls     [][]string
+	err       error
+}
+
+func (c *stubStatusChecker) StatusBatchSize() int { return c.batchSize }
+
+func (c *stubStatusChecker) CheckStatus(_ context.Context, ids []string) ([]*domain.DeliveryReceipt, error) {
+	c.calls = append(c.calls, ids)
+	if c.err != nil {
+		return nil, c.err
+	}
+	receipts := make([]*domain.DeliveryReceipt, 0, len(ids))
+	for _, id := range ids {
+		receipts = append(receipts, &domain.DeliveryReceipt{ExternalID: id, Status: domain.ReceiptDelivered})
+	}
+	return receipts, nil
+}
+
+func TestStatusPollerService_Poll(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	newSent := func(provider, externalID string) *domain.Notification {
+		n := domain.NewNotification("+905551234567", domain.ChannelSMS, "Hi")
+		n.Status = domain.StatusProcessing
+		require.NoError(t, n.MarkAsSent(externalID))
+		n.Provider = &provider
+		return n
+	}
+
+	sent := []*domain.Notification{
+		newSent("acme", "a1"), newSent("acme", "a2"), newSent("acme", "a3"),
+		newSent("limited", "l1"), newSent("limited", "l2"),
+	}
+
+	acme := &stubStatusChecker{batchSize: 2}
+	limited := &stubStatusChecker{batchSize: 1, err: domain.NewProviderError(http.StatusTooManyRequests, "slow down", true)}
+
+	repo := new(MockNotificationRepository)
+	repo.On("ClaimStatusChecks", ctx, mock.Anything, mock.Anything, mock.Anything, 100).Return(sent, nil)
+	for _, n := range sent[:3] {
+		repo.On("GetByExternalID", ctx, "acme", *n.ExternalID).Return(n, nil)
+	}
+	repo.On("Update", ctx, mock.Anything).Return(nil)
+
+	poller := NewStatusPollerService(repo,
+		&stubRouter{checkers: map[string]domain.StatusChecker{"acme": acme, "limited": limited}},
+		NewReceiptService(repo, logger),
+		logger,
+		config.PollerConfig{MaxAge: time.Hour, CheckInterval: time.Minute, BatchLimit: 100, RatePerSec: 1000},
+	)
+	poller.Poll(ctx)
+
+	assert.Equal(t, [][]string{{"a1", "a2"}, {"a3"}}, acme.calls)
+	// Rate limited providers are not hammered for the rest of the cycle
+	assert.Len(t, limited.calls, 1)
+	for _, n := range sent[:3] {
+		assert.Equal(t, domain.StatusDelivered, n.Status)
+	}
+	repo.AssertNumberOfCalls(t, "Update", 3)
+}
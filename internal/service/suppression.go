@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+const (
+	maxSuppressionImportSize = 10000
+	rekeyBatchSize           = 500
+)
+
+// SuppressionService manages the recipients notifications must not be sent to
+type SuppressionService struct {
+	repo        domain.SuppressionRepository
+	logger      *slog.Logger
+	phoneRegion string
+}
+
+// NewSuppressionService creates a new SuppressionService
+func NewSuppressionService(repo domain.SuppressionRepository, logger *slog.Logger) *SuppressionService {
+	return &SuppressionService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SetPhoneRegion sets the region phone numbers given in national format are
+// read in
+func (s *SuppressionService) SetPhoneRegion(region string) {
+	s.phoneRegion = region
+}
+
+// SuppressRequest represents a request to suppress a recipient on a channel
+type SuppressRequest struct {
+	Recipient string                   `json:"recipient" validate:"required,max=255"`
+	Channel   domain.Channel           `json:"channel" validate:"required"`
+	Reason    domain.SuppressionReason `json:"reason" validate:"required"`
+	Note      *string                  `json:"note,omitempty"`
+	ExpiresAt *time.Time               `json:"expires_at,omitempty"`
+}
+
+// ImportSuppressionsRequest represents a bulk import of suppressions
+type ImportSuppressionsRequest struct {
+	Suppressions []SuppressRequest `json:"suppressions" validate:"required,min=1,max=10000,dive"`
+}
+
+// ImportResult summarizes a bulk import
+type ImportResult struct {
+	Imported int `json:"imported"`
+}
+
+// RekeyResult summarizes a rekey of stored suppressions
+type RekeyResult struct {
+	Rekeyed int `json:"rekeyed"`
+}
+
+// Suppress adds a recipient to the suppression list, replacing any existing
+// entry for the same recipient and channel
+func (s *SuppressionService) Suppress(ctx context.Context, req SuppressRequest) (*domain.Suppression, error) {
+	suppression, err := newSuppression(req, s.phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Upsert(ctx, suppression); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("recipient suppressed",
+		"channel", suppression.Channel,
+		"reason", suppression.Reason,
+	)
+
+	return suppression, nil
+}
+
+// Import adds many suppressions at once. Nothing is stored if any entry is invalid.
+func (s *SuppressionService) Import(ctx context.Context, req ImportSuppressionsRequest) (*ImportResult, error) {
+	if len(req.Suppressions) > maxSuppressionImportSize {
+		return nil, domain.NewValidationError("suppressions", fmt.Sprintf("at most %d suppressions can be imported at once", maxSuppressionImportSize))
+	}
+
+	suppressions := make([]*domain.Suppression, 0, len(req.Suppressions))
+	var errs domain.ValidationErrors
+	for i, item := range req.Suppressions {
+		suppression, err := newSuppression(item, s.phoneRegion)
+		if err != nil {
+			var validationErr domain.ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr.Field = fmt.Sprintf("suppressions[%d].%s", i, validationErr.Field)
+				errs.Errors = append(errs.Errors, validationErr)
+				continue
+			}
+			return nil, err
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	if len(errs.Errors) > 0 {
+		return nil, errs
+	}
+
+	if err := s.repo.UpsertBatch(ctx, suppressions); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("suppressions imported", "count", len(suppressions))
+
+	return &ImportResult{Imported: len(suppressions)}, nil
+}
+
+// Get retrieves the suppression for a recipient on a channel
+func (s *SuppressionService) Get(ctx context.Context, channel domain.Channel, recipient string) (*domain.Suppression, error) {
+	return s.repo.Get(ctx, channel, domain.SuppressionRecipient(channel, recipient, s.phoneRegion))
+}
+
+// List lists suppressions with filters
+func (s *SuppressionService) List(ctx context.Context, filter domain.SuppressionFilter) (*domain.SuppressionListResult, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Delete removes a recipient from the suppression list
+func (s *SuppressionService) Delete(ctx context.Context, channel domain.Channel, recipient string) error {
+	if err := s.repo.Delete(ctx, channel, domain.SuppressionRecipient(channel, recipient, s.phoneRegion)); err != nil {
+		return err
+	}
+
+	s.logger.Info("suppression removed", "channel", channel)
+
+	return nil
+}
+
+// RekeyPhones moves SMS suppressions stored before their recipients were
+// normalized to the E.164 number they are now looked up by, merging them
+// with any suppression already stored under that number. Numbers that
+// cannot be normalized in the phone region are left as they are. It is run
+// once by an operator after upgrading or changing the phone region.
+func (s *SuppressionService) RekeyPhones(ctx context.Context) (*RekeyResult, error) {
+	rekeyed := 0
+	after := ""
+	for {
+		suppressions, err := s.repo.ListUnnormalizedPhones(ctx, after, rekeyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, suppression := range suppressions {
+			recipient := domain.SuppressionRecipient(domain.ChannelSMS, suppression.Recipient, s.phoneRegion)
+			if recipient == suppression.Recipient {
+				continue
+			}
+			if err := s.repo.Rekey(ctx, domain.ChannelSMS, suppression.Recipient, recipient); err != nil {
+				return nil, err
+			}
+			rekeyed++
+		}
+
+		if len(suppressions) < rekeyBatchSize {
+			break
+		}
+		after = suppressions[len(suppressions)-1].Recipient
+	}
+
+	s.logger.Info("suppressions rekeyed", "count", rekeyed)
+
+	return &RekeyResult{Rekeyed: rekeyed}, nil
+}
+
+func newSuppression(req SuppressRequest, phoneRegion string) (*domain.Suppression, error) {
+	if !req.Channel.IsValid() {
+		return nil, domain.NewValidationError("channel", "invalid channel")
+	}
+	if !req.Reason.IsValid() {
+		return nil, domain.NewValidationError("reason", "reason must be one of bounce, complaint, unsubscribe, manual")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, domain.NewValidationError("expires_at", "expiry must be in the future")
+	}
+
+	suppression := domain.NewSuppression(domain.SuppressionRecipient(req.Channel, req.Recipient, phoneRegion), req.Channel, req.Reason)
+	if suppression.Recipient == "" {
+		return nil, domain.NewValidationError("recipient", "recipient is required")
+	}
+	suppression.Note = req.Note
+	suppression.ExpiresAt = req.ExpiresAt
+
+	return suppression, nil
+}
+
+// applySuppressions marks notifications whose recipients are suppressed on
+// their channel, looking recipients up once per channel
+func applySuppressions(ctx context.Context, repo domain.SuppressionRepository, notifications []*domain.Notification, phoneRegion string) error {
+	byChannel := make(map[domain.Channel][]string)
+	for _, n := range notifications {
+		byChannel[n.Channel] = append(byChannel[n.Channel], domain.SuppressionRecipient(n.Channel, n.Recipient, phoneRegion))
+	}
+
+	for channel, recipients := range byChannel {
+		suppressed, err := repo.FindActive(ctx, channel, recipients)
+		if err != nil {
+			return fmt.Errorf("failed to check suppression list: %w", err)
+		}
+		if len(suppressed) == 0 {
+			continue
+		}
+		for _, n := range notifications {
+			if n.Channel != channel {
+				continue
+			}
+			if suppression, ok := suppressed[domain.SuppressionRecipient(n.Channel, n.Recipient, phoneRegion)]; ok {
+				if err := n.MarkAsSuppressed(suppression.Reason); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockSuppressionRepository is a mock implementation of domain.SuppressionRepository
+type MockSuppressionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSuppressionRepository) Upsert(ctx context.Context, s *domain.Suppression) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) UpsertBatch(ctx context.Context, suppressions []*domain.Suppression) error {
+	args := m.Called(ctx, suppressions)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) Get(ctx context.Context, channel domain.Channel, recipient string) (*domain.Suppression, error) {
+	args := m.Called(ctx, channel, recipient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) List(ctx context.Context, filter domain.SuppressionFilter) (*domain.SuppressionListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SuppressionListResult), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) Delete(ctx context.Context, channel domain.Channel, recipient string) error {
+	args := m.Called(ctx, channel, recipient)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) FindActive(ctx context.Context, channel domain.Channel, recipients []string) (map[string]*domain.Suppression, error) {
+	args := m.Called(ctx, channel, recipients)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) ListUnnormalizedPhones(ctx context.Context, after string, limit int) ([]*domain.Suppression, error) {
+	args := m.Called(ctx, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) Rekey(ctx context.Context, channel domain.Channel, from, to string) error {
+	args := m.Called(ctx, channel, from, to)
+	return args.Error(0)
+}
+
+func TestSuppressionService_Import(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("imports normalized suppressions", func(t *testing.T) {
+		repo := new(MockSuppressionRepository)
+		repo.On("UpsertBatch", ctx, mock.MatchedBy(func(s []*domain.Suppression) bool {
+			return len(s) == 2 && s[0].Recipient == "user@example.com"
+		})).Return(nil).Once()
+
+		svc := NewSuppressionService(repo, logger)
+		result, err := svc.Import(ctx, ImportSuppressionsRequest{Suppressions: []SuppressRequest{
+			{Recipient: " User@Example.com", Channel: domain.ChannelEmail, Reason: domain.SuppressionBounce},
+			{Recipient: "+905551234567", Channel: domain.ChannelSMS, Reason: domain.SuppressionUnsubscribe},
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects the whole import when an entry is invalid", func(t *testing.T) {
+		repo := new(MockSuppressionRepository)
+		svc := NewSuppressionService(repo, logger)
+
+		past := time.Now().Add(-time.Hour)
+		_, err := svc.Import(ctx, ImportSuppressionsRequest{Suppressions: []SuppressRequest{
+			{Recipient: "user@example.com", Channel: domain.ChannelEmail, Reason: domain.SuppressionBounce},
+			{Recipient: "+905551234567", Channel: domain.ChannelSMS, Reason: "spam"},
+			{Recipient: "+905551234568", Channel: domain.ChannelSMS, Reason: domain.SuppressionManual, ExpiresAt: &past},
+		}})
+
+		var validationErrs domain.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		require.Len(t, validationErrs.Errors, 2)
+		assert.Equal(t, "suppressions[1].reason", validationErrs.Errors[0].Field)
+		assert.Equal(t, "suppressions[2].expires_at", validationErrs.Errors[1].Field)
+		repo.AssertNotCalled(t, "UpsertBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reads national numbers in the phone region", func(t *testing.T) {
+		repo := new(MockSuppressionRepository)
+		repo.On("UpsertBatch", ctx, mock.MatchedBy(func(s []*domain.Suppression) bool {
+			return len(s) == 1 && s[0].Recipient == "+905551234567"
+		})).Return(nil).Once()
+
+		svc := NewSuppressionService(repo, logger)
+		svc.SetPhoneRegion("TR")
+		_, err := svc.Import(ctx, ImportSuppressionsRequest{Suppressions: []SuppressRequest{
+			{Recipient: "0555 123 45 67", Channel: domain.ChannelSMS, Reason: domain.SuppressionUnsubscribe},
+		}})
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestSuppressionService_RekeyPhones(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	repo := new(MockSuppressionRepository)
+	repo.On("ListUnnormalizedPhones", ctx, "", rekeyBatchSize).Return([]*domain.Suppression{
+		domain.NewSuppression("+90 555 123 45 68", domain.ChannelSMS, domain.SuppressionManual),
+		domain.NewSuppression("0555 123 45 67", domain.ChannelSMS, domain.SuppressionUnsubscribe),
+		domain.NewSuppression("not a number", domain.ChannelSMS, domain.SuppressionManual),
+	}, nil).Once()
+	repo.On("Rekey", ctx, domain.ChannelSMS, "+90 555 123 45 68", "+905551234568").Return(nil).Once()
+	repo.On("Rekey", ctx, domain.ChannelSMS, "0555 123 45 67", "+905551234567").Return(nil).Once()
+
+	svc := NewSuppressionService(repo, logger)
+	svc.SetPhoneRegion("TR")
+	result, err := svc.RekeyPhones(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rekeyed)
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationService_CreateSuppressed(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mockRepo := new(MockNotificationRepository)
+	mockQueue := new(MockQueue)
+	suppressions := new(MockSuppressionRepository)
+
+	svc := NewNotificationService(mockRepo, new(MockTemplateRepository), mockQueue, logger)
+	svc.SetSuppressions(suppressions)
+
+	suppressions.On("FindActive", ctx, domain.ChannelEmail, []string{"bounced@example.com", "ok@example.com"}).Return(
+		map[string]*domain.Suppression{
+			"bounced@example.com": domain.NewSuppression("bounced@example.com", domain.ChannelEmail, domain.SuppressionBounce),
+		}, nil)
+	mockRepo.On("CreateBatch", ctx, mock.Anything).Return(nil)
+	mockQueue.On("EnqueueBatch", ctx, mock.MatchedBy(func(items []*domain.QueueItem) bool {
+		return len(items) == 1
+	})).Return(nil)
+
+	notifications, err := svc.CreateBatch(ctx, BatchCreateRequest{Notifications: []CreateRequest{
+		{Recipient: "Bounced@example.com", Channel: domain.ChannelEmail, Content: "Hi"},
+		{Recipient: "ok@example.com", Channel: domain.ChannelEmail, Content: "Hi"},
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuppressed, notifications[0].Status)
+	assert.Equal(t, "recipient suppressed: bounce", *notifications[0].ErrorMessage)
+	assert.Equal(t, domain.StatusQueued, notifications[1].Status)
+	mockQueue.AssertExpectations(t)
+}
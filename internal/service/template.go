@@ -30,6 +30,8 @@ type CreateTemplateRequest struct {
 	Name    string         `json:"name" validate:"required,min=1,max=100"`
 	Channel domain.Channel `json:"channel" validate:"required"`
 	Content string         `json:"content" validate:"required"`
+	// TrackingDisabled opts emails rendered from the template out of open and click tracking
+	TrackingDisabled bool `json:"tracking_disabled"`
 }
 
 // UpdateTemplateRequest represents a request to update a template
@@ -37,6 +39,8 @@ type UpdateTemplateRequest struct {
 	Name    *string         `json:"name,omitempty"`
 	Channel *domain.Channel `json:"channel,omitempty"`
 	Content *string         `json:"content,omitempty"`
+	// TrackingDisabled opts emails rendered from the template out of open and click tracking
+	TrackingDisabled *bool `json:"tracking_disabled,omitempty"`
 }
 
 // Create creates a new template
@@ -57,6 +61,7 @@ func (s *TemplateService) Create(ctx context.Context, req CreateTemplateRequest)
 
 	// Create template
 	template := domain.NewTemplate(req.Name, req.Channel, req.Content)
+	template.TrackingDisabled = req.TrackingDisabled
 
 	if err := s.repo.Create(ctx, template); err != nil {
 		return nil, fmt.Errorf("failed to create template: %w", err)
@@ -113,6 +118,10 @@ func (s *TemplateService) Update(ctx context.Context, id uuid.UUID, req UpdateTe
 		template.ExtractVariables()
 	}
 
+	if req.TrackingDisabled != nil {
+		template.TrackingDisabled = *req.TrackingDisabled
+	}
+
 	if err := s.repo.Update(ctx, template); err != nil {
 		return nil, fmt.Errorf("failed to update template: %w", err)
 	}
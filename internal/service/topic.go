@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// publishChunkSize is the number of subscriptions fanned out at once. A
+// subscriber gets at most one notification on each of the three channels,
+// so every chunk fits in a single batch.
+const publishChunkSize = maxBatchSize / 3
+
+// TopicService manages topics, their subscriptions and the fan-out of
+// messages published to them
+type TopicService struct {
+	topics        domain.TopicRepository
+	publications  domain.PublicationRepository
+	recipients    domain.RecipientRepository
+	templateRepo  domain.TemplateRepository
+	notifications *NotificationService
+	logger        *slog.Logger
+	batchSize     int
+	// budget bounds how long a scheduler tick spends fanning out
+	budget time.Duration
+}
+
+// NewTopicService creates a new TopicService
+func NewTopicService(
+	topics domain.TopicRepository,
+	publications domain.PublicationRepository,
+	recipients domain.RecipientRepository,
+	templateRepo domain.TemplateRepository,
+	notifications *NotificationService,
+	logger *slog.Logger,
+) *TopicService {
+	return &TopicService{
+		topics:        topics,
+		publications:  publications,
+		recipients:    recipients,
+		templateRepo:  templateRepo,
+		notifications: notifications,
+		logger:        logger,
+		batchSize:     10,
+		budget:        5 * time.Second,
+	}
+}
+
+// CreateTopicRequest represents a request to create a topic
+type CreateTopicRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PublishRequest represents a message published to every subscriber of a
+// topic. ExpiresAt and TTL bound how late the fan-out may still send it.
+type PublishRequest struct {
+	Content      string            `json:"content,omitempty"`
+	TemplateName *string           `json:"template_name,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Priority     domain.Priority   `json:"priority"`
+	Category     string            `json:"category,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	ExpiresAt    *time.Time        `json:"expires_at,omitempty"`
+	// TTL is the time to live in seconds, counted from publishing
+	TTL *int `json:"ttl,omitempty"`
+}
+
+// CreateTopic creates a topic
+func (s *TopicService) CreateTopic(ctx context.Context, req CreateTopicRequest) (*domain.Topic, error) {
+	if err := domain.ValidateTopicName(req.Name); err != nil {
+		return nil, err
+	}
+
+	topic := domain.NewTopic(req.Name)
+	topic.Description = req.Description
+
+	if err := s.topics.Create(ctx, topic); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("topic created", "topic", topic.Name)
+
+	return topic, nil
+}
+
+// GetTopic retrieves a topic by name
+func (s *TopicService) GetTopic(ctx context.Context, name string) (*domain.Topic, error) {
+	return s.topics.GetByName(ctx, name)
+}
+
+// ListTopics lists topics with pagination
+func (s *TopicService) ListTopics(ctx context.Context, filter domain.TopicFilter) (*domain.TopicListResult, error) {
+	return s.topics.List(ctx, filter)
+}
+
+// DeleteTopic deletes a topic with its subscriptions and publications.
+// Notifications already created by its publications are kept.
+func (s *TopicService) DeleteTopic(ctx context.Context, name string) error {
+	if err := s.topics.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.logger.Info("topic deleted", "topic", name)
+
+	return nil
+}
+
+// Subscribe subscribes a recipient to a topic on channels, replacing the
+// channels of an existing subscription
+func (s *TopicService) Subscribe(ctx context.Context, topic, userID string, channels []domain.Channel) (*domain.Subscription, error) {
+	if userID == "" {
+		return nil, domain.NewValidationError("user_id", "user_id is required")
+	}
+	if len(channels) == 0 {
+		return nil, domain.NewValidationError("channels", "at least one channel is required")
+	}
+
+	unique := make([]domain.Channel, 0, len(channels))
+	seen := make(map[domain.Channel]bool, len(channels))
+	for _, channel := range channels {
+		if !channel.IsValid() {
+			return nil, domain.NewValidationError("channels", fmt.Sprintf("invalid channel %q", channel))
+		}
+		if !seen[channel] {
+			seen[channel] = true
+			unique = append(unique, channel)
+		}
+	}
+
+	if _, err := s.topics.GetByName(ctx, topic); err != nil {
+		return nil, err
+	}
+
+	subscription := domain.NewSubscription(topic, userID, unique)
+	if err := s.topics.Subscribe(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("recipient subscribed",
+		"topic", topic,
+		"user_id", userID,
+		"channels", unique,
+	)
+
+	return subscription, nil
+}
+
+// Unsubscribe removes a recipient's subscription to a topic
+func (s *TopicService) Unsubscribe(ctx context.Context, topic, userID string) error {
+	if err := s.topics.Unsubscribe(ctx, topic, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("recipient unsubscribed", "topic", topic, "user_id", userID)
+
+	return nil
+}
+
+// ListSubscriptions lists the subscriptions to a topic with pagination
+func (s *TopicService) ListSubscriptions(ctx context.Context, topic string, filter domain.SubscriptionFilter) (*domain.SubscriptionListResult, error) {
+	if _, err := s.topics.GetByName(ctx, topic); err != nil {
+		return nil, err
+	}
+	return s.topics.ListSubscriptions(ctx, topic, filter)
+}
+
+// Publish records a message for every subscriber of a topic. The scheduler
+// fans it out in chunks; the returned publication reports its progress.
+func (s *TopicService) Publish(ctx context.Context, topic string, req PublishRequest) (*domain.Publication, error) {
+	if _, err := s.topics.GetByName(ctx, topic); err != nil {
+		return nil, err
+	}
+
+	publication := domain.NewPublication(topic)
+	publication.Content = req.Content
+	publication.TemplateName = req.TemplateName
+	publication.TemplateVars = req.TemplateVars
+	if req.Priority != "" {
+		publication.Priority = req.Priority
+	}
+	publication.Category = req.Category
+	publication.Metadata = req.Metadata
+
+	if err := s.validatePublication(ctx, publication); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case req.ExpiresAt != nil && req.TTL != nil:
+		return nil, domain.NewValidationError("ttl", "set either ttl or expires_at, not both")
+	case req.TTL != nil:
+		if *req.TTL <= 0 {
+			return nil, domain.NewValidationError("ttl", "ttl must be a positive number of seconds")
+		}
+		expiresAt := now.Add(time.Duration(*req.TTL) * time.Second)
+		publication.ExpiresAt = &expiresAt
+	case req.ExpiresAt != nil:
+		if !req.ExpiresAt.After(now) {
+			return nil, domain.NewValidationError("expires_at", "expiry must be in the future")
+		}
+		publication.ExpiresAt = req.ExpiresAt
+	}
+
+	if err := s.publications.Create(ctx, publication); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("message published",
+		"topic", topic,
+		"publication_id", publication.ID,
+	)
+
+	return publication, nil
+}
+
+// GetPublication retrieves a publication to a topic
+func (s *TopicService) GetPublication(ctx context.Context, topic string, id uuid.UUID) (*domain.Publication, error) {
+	publication, err := s.publications.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if publication.Topic != topic {
+		return nil, domain.ErrNotFound
+	}
+	return publication, nil
+}
+
+// PublishDue fans out pending publications, oldest first, one chunk of
+// subscribers at a time until the tick's budget is spent. Progress is
+// stored after every chunk with a versioned update, and notifications are
+// created with an idempotency key per subscription and channel, so a chunk
+// interrupted by a crash or raced by another replica is never sent twice.
+// It returns the number of chunks fanned out.
+func (s *TopicService) PublishDue(ctx context.Context, now time.Time) int {
+	publications, err := s.publications.ListDue(ctx, s.batchSize)
+	if err != nil {
+		s.logger.Error("failed to get due publications", "error", err)
+		return 0
+	}
+
+	deadline := time.Now().Add(s.budget)
+	chunks := 0
+	for _, publication := range publications {
+		for !publication.Status.IsFinal() && time.Now().Before(deadline) {
+			if err := s.publishChunk(ctx, publication, now); err != nil {
+				// Another replica fanned out the chunk first
+				if !errors.Is(err, domain.ErrVersionConflict) {
+					s.logger.Error("failed to fan out publication",
+						"publication_id", publication.ID,
+						"error", err,
+					)
+				}
+				break
+			}
+			chunks++
+		}
+	}
+
+	return chunks
+}
+
+// publishChunk creates the notifications of the next chunk of subscribers
+// and records the publication's progress
+func (s *TopicService) publishChunk(ctx context.Context, publication *domain.Publication, now time.Time) error {
+	if publication.ExpiresAt != nil && !now.Before(*publication.ExpiresAt) {
+		publication.Fail("publication expired before reaching every subscriber", now)
+		return s.publications.Update(ctx, publication)
+	}
+
+	subscriptions, err := s.topics.ListSubscriptionsAfter(ctx, publication.Topic, publication.LastSubscriptionID, publishChunkSize)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		publication.Complete(now)
+		if err := s.publications.Update(ctx, publication); err != nil {
+			return err
+		}
+		s.logger.Info("publication completed",
+			"publication_id", publication.ID,
+			"topic", publication.Topic,
+			"notifications", publication.Notifications,
+			"skipped", publication.Skipped,
+		)
+		return nil
+	}
+
+	userIDs := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		userIDs = append(userIDs, subscription.UserID)
+	}
+	profiles, err := s.recipients.FindByUserIDs(ctx, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get recipients: %w", err)
+	}
+
+	reqs, skipped := publicationRequests(publication, subscriptions, profiles, s.notifications.normalizeRecipient)
+	if len(reqs) > 0 {
+		_, err := s.notifications.createBatch(ctx, publication.ID, reqs)
+		switch {
+		case errors.Is(err, domain.ErrIdempotencyConflict):
+			// An earlier attempt created the chunk but did not record it
+		case isPermanentCreateError(err):
+			publication.Fail(err.Error(), now)
+			return s.publications.Update(ctx, publication)
+		case err != nil:
+			return err
+		}
+	}
+
+	publication.Advance(subscriptions[len(subscriptions)-1].ID, int64(len(subscriptions)), int64(len(reqs)), skipped)
+	return s.publications.Update(ctx, publication)
+}
+
+// publicationRequests builds a notification for each subscribed channel on
+// which the subscriber has a valid address, counting the others as skipped
+func publicationRequests(
+	publication *domain.Publication,
+	subscriptions []*domain.Subscription,
+	profiles map[string]*domain.Recipient,
+	normalize func(domain.Channel, string) (string, error),
+) ([]CreateRequest, int64) {
+	metadata := make(map[string]any, len(publication.Metadata)+1)
+	for k, v := range publication.Metadata {
+		metadata[k] = v
+	}
+	metadata[domain.TopicMetadataKey] = publication.Topic
+
+	reqs := make([]CreateRequest, 0, len(subscriptions))
+	var skipped int64
+	for _, subscription := range subscriptions {
+		profile, ok := profiles[subscription.UserID]
+		for _, channel := range subscription.Channels {
+			if !ok {
+				skipped++
+				continue
+			}
+			address, err := profile.Address(channel)
+			if err != nil {
+				skipped++
+				continue
+			}
+			// Profiles stored before addresses were validated may hold
+			// invalid ones, which would fail the whole chunk
+			if address, err = normalize(channel, address); err != nil {
+				skipped++
+				continue
+			}
+
+			key := publication.SubscriptionKey(subscription.ID, channel)
+			reqs = append(reqs, CreateRequest{
+				Recipient:      address,
+				Channel:        channel,
+				Content:        publication.Content,
+				Priority:       publication.Priority,
+				IdempotencyKey: &key,
+				Metadata:       metadata,
+				TemplateName:   publication.TemplateName,
+				TemplateVars:   publication.TemplateVars,
+				Category:       publication.Category,
+				ExpiresAt:      publication.ExpiresAt,
+				TimeZone:       profile.TimeZone,
+			})
+		}
+	}
+
+	return reqs, skipped
+}
+
+// validatePublication checks the priority and content of a publication
+func (s *TopicService) validatePublication(ctx context.Context, publication *domain.Publication) error {
+	if !publication.Priority.IsValid() {
+		return domain.NewValidationError("priority", "invalid priority")
+	}
+	if err := domain.ValidateCategory(publication.Category); err != nil {
+		return err
+	}
+
+	content := publication.Content
+	if publication.TemplateName != nil {
+		template, err := s.templateRepo.GetByName(ctx, *publication.TemplateName)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.ErrTemplateNotFound
+			}
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		if missing := template.Validate(publication.TemplateVars); len(missing) > 0 {
+			return fmt.Errorf("%w: %v", domain.ErrMissingVariables, missing)
+		}
+		content = template.Render(publication.TemplateVars)
+	}
+
+	if content == "" {
+		return domain.NewValidationError("content", "content is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockTopicRepository is a mock implementation of domain.TopicRepository
+type MockTopicRepository struct {
+	mock.Mock
+}
+
+func (m *MockTopicRepository) Create(ctx context.Context, t *domain.Topic) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) GetByName(ctx context.Context, name string) (*domain.Topic, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Topic), args.Error(1)
+}
+
+func (m *MockTopicRepository) List(ctx context.Context, filter domain.TopicFilter) (*domain.TopicListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TopicListResult), args.Error(1)
+}
+
+func (m *MockTopicRepository) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) Subscribe(ctx context.Context, s *domain.Subscription) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) Unsubscribe(ctx context.Context, topic, userID string) error {
+	args := m.Called(ctx, topic, userID)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) ListSubscriptions(ctx context.Context, topic string, filter domain.SubscriptionFilter) (*domain.SubscriptionListResult, error) {
+	args := m.Called(ctx, topic, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SubscriptionListResult), args.Error(1)
+}
+
+func (m *MockTopicRepository) ListSubscriptionsAfter(ctx context.Context, topic string, afterID int64, limit int) ([]*domain.Subscription, error) {
+	args := m.Called(ctx, topic, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Subscription), args.Error(1)
+}
+
+// MockPublicationRepository is a mock implementation of domain.PublicationRepository
+type MockPublicationRepository struct {
+	mock.Mock
+}
+
+func (m *MockPublicationRepository) Create(ctx context.Context, p *domain.Publication) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPublicationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Publication, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Publication), args.Error(1)
+}
+
+func (m *MockPublicationRepository) Update(ctx context.Context, p *domain.Publication) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPublicationRepository) ListDue(ctx context.Context, limit int) ([]*domain.Publication, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Publication), args.Error(1)
+}
+
+func TestTopicService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("deduplicates channels", func(t *testing.T) {
+		topics := new(MockTopicRepository)
+		svc := NewTopicService(topics, new(MockPublicationRepository), new(MockRecipientRepository), new(MockTemplateRepository), nil, logger)
+
+		topics.On("GetByName", ctx, "deals").Return(domain.NewTopic("deals"), nil).Once()
+		topics.On("Subscribe", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+
+		subscription, err := svc.Subscribe(ctx, "deals", "user-42", []domain.Channel{domain.ChannelPush, domain.ChannelEmail, domain.ChannelPush})
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Channel{domain.ChannelPush, domain.ChannelEmail}, subscription.Channels)
+	})
+
+	t.Run("unknown topic", func(t *testing.T) {
+		topics := new(MockTopicRepository)
+		svc := NewTopicService(topics, new(MockPublicationRepository), new(MockRecipientRepository), new(MockTemplateRepository), nil, logger)
+
+		topics.On("GetByName", ctx, "missing").Return(nil, domain.ErrNotFound).Once()
+
+		_, err := svc.Subscribe(ctx, "missing", "user-42", []domain.Channel{domain.ChannelSMS})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		topics.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTopicService_PublishDue(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	now := time.Now().UTC()
+
+	phone := "+905551234567"
+	withPhone := domain.NewRecipient("user-1")
+	withPhone.Phone = &phone
+	withPhone.DeviceTokens = []string{"device-token-1"}
+	withPhone.TimeZone = "Europe/Istanbul"
+	tokenOnly := domain.NewRecipient("user-2")
+	tokenOnly.DeviceTokens = []string{"device-token-2"}
+
+	subscriptions := []*domain.Subscription{
+		{ID: 11, Topic: "deals", UserID: "user-1", Channels: []domain.Channel{domain.ChannelPush, domain.ChannelSMS}},
+		{ID: 12, Topic: "deals", UserID: "user-2", Channels: []domain.Channel{domain.ChannelPush, domain.ChannelSMS}},
+		{ID: 15, Topic: "deals", UserID: "user-3", Channels: []domain.Channel{domain.ChannelEmail}},
+	}
+
+	setup := func() (*TopicService, *MockTopicRepository, *MockPublicationRepository, *MockNotificationRepository, *MockRecipientRepository, *MockQueue) {
+		topics := new(MockTopicRepository)
+		publications := new(MockPublicationRepository)
+		recipients := new(MockRecipientRepository)
+		notificationRepo := new(MockNotificationRepository)
+		queue := new(MockQueue)
+		notifications := NewNotificationService(notificationRepo, new(MockTemplateRepository), queue, logger)
+		return NewTopicService(topics, publications, recipients, new(MockTemplateRepository), notifications, logger),
+			topics, publications, notificationRepo, recipients, queue
+	}
+
+	t.Run("fans out chunks under the publication's batch ID", func(t *testing.T) {
+		svc, topics, publications, notificationRepo, recipients, queue := setup()
+		publication := domain.NewPublication("deals")
+		publication.Content = "Product 123 is now 20% off"
+
+		publications.On("ListDue", ctx, 10).Return([]*domain.Publication{publication}, nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(0), publishChunkSize).Return(subscriptions, nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(15), publishChunkSize).Return([]*domain.Subscription{}, nil).Once()
+		recipients.On("FindByUserIDs", ctx, []string{"user-1", "user-2", "user-3"}).Return(map[string]*domain.Recipient{
+			"user-1": withPhone,
+			"user-2": tokenOnly,
+		}, nil).Once()
+		notificationRepo.On("CreateBatch", ctx, mock.MatchedBy(func(n []*domain.Notification) bool {
+			return len(n) == 3 &&
+				*n[0].BatchID == publication.ID &&
+				n[0].Recipient == "device-token-1" &&
+				*n[1].IdempotencyKey == publication.SubscriptionKey(11, domain.ChannelSMS) &&
+				n[1].TimeZone == "Europe/Istanbul" &&
+				n[2].Metadata[domain.TopicMetadataKey] == "deals"
+		})).Return(nil).Once()
+		queue.On("EnqueueBatch", ctx, mock.Anything).Return(nil).Once()
+		publications.On("Update", ctx, publication).Return(nil).Twice()
+
+		chunks := svc.PublishDue(ctx, now)
+
+		assert.Equal(t, 2, chunks)
+		assert.Equal(t, domain.PublicationCompleted, publication.Status)
+		assert.Equal(t, int64(3), publication.Subscribers)
+		assert.Equal(t, int64(3), publication.Notifications)
+		assert.Equal(t, int64(2), publication.Skipped)
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("records a chunk already created by an earlier attempt", func(t *testing.T) {
+		svc, topics, publications, notificationRepo, recipients, _ := setup()
+		publication := domain.NewPublication("deals")
+		publication.Content = "Product 123 is now 20% off"
+
+		publications.On("ListDue", ctx, 10).Return([]*domain.Publication{publication}, nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(0), publishChunkSize).Return(subscriptions[:1], nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(11), publishChunkSize).Return([]*domain.Subscription{}, nil).Once()
+		recipients.On("FindByUserIDs", ctx, []string{"user-1"}).Return(map[string]*domain.Recipient{"user-1": withPhone}, nil).Once()
+		notificationRepo.On("CreateBatch", ctx, mock.Anything).Return(domain.ErrIdempotencyConflict).Once()
+		publications.On("Update", ctx, publication).Return(nil).Twice()
+
+		svc.PublishDue(ctx, now)
+
+		assert.Equal(t, domain.PublicationCompleted, publication.Status)
+		assert.Equal(t, int64(2), publication.Notifications)
+	})
+
+	t.Run("stops on a version conflict", func(t *testing.T) {
+		svc, topics, publications, notificationRepo, recipients, queue := setup()
+		publication := domain.NewPublication("deals")
+		publication.Content = "Product 123 is now 20% off"
+
+		publications.On("ListDue", ctx, 10).Return([]*domain.Publication{publication}, nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(0), publishChunkSize).Return(subscriptions[:1], nil).Once()
+		recipients.On("FindByUserIDs", ctx, []string{"user-1"}).Return(map[string]*domain.Recipient{"user-1": withPhone}, nil).Once()
+		notificationRepo.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+		queue.On("EnqueueBatch", ctx, mock.Anything).Return(nil).Once()
+		publications.On("Update", ctx, publication).Return(domain.ErrVersionConflict).Once()
+
+		assert.Equal(t, 0, svc.PublishDue(ctx, now))
+		topics.AssertExpectations(t)
+	})
+
+	t.Run("fails a publication whose notifications cannot be created", func(t *testing.T) {
+		svc, topics, publications, notificationRepo, recipients, _ := setup()
+		publication := domain.NewPublication("deals")
+		publication.Content = "Product 123 is now 20% off"
+		// Stored before categories were limited to what notifications can hold
+		publication.Category = strings.Repeat("c", domain.MaxCategoryLength+1)
+
+		publications.On("ListDue", ctx, 10).Return([]*domain.Publication{publication}, nil).Once()
+		topics.On("ListSubscriptionsAfter", ctx, "deals", int64(0), publishChunkSize).Return(subscriptions[:1], nil).Once()
+		recipients.On("FindByUserIDs", ctx, []string{"user-1"}).Return(map[string]*domain.Recipient{"user-1": withPhone}, nil).Once()
+		publications.On("Update", ctx, publication).Return(nil).Once()
+
+		svc.PublishDue(ctx, now)
+
+		assert.Equal(t, domain.PublicationFailed, publication.Status)
+		require.NotNil(t, publication.Error)
+		assert.Contains(t, *publication.Error, "category")
+		notificationRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fails an expired publication", func(t *testing.T) {
+		svc, topics, publications, _, _, _ := setup()
+		publication := domain.NewPublication("deals")
+		expiresAt := now.Add(-time.Minute)
+		publication.ExpiresAt = &expiresAt
+
+		publications.On("ListDue", ctx, 10).Return([]*domain.Publication{publication}, nil).Once()
+		publications.On("Update", ctx, publication).Return(nil).Once()
+
+		svc.PublishDue(ctx, now)
+
+		assert.Equal(t, domain.PublicationFailed, publication.Status)
+		require.NotNil(t, publication.Error)
+		topics.AssertNotCalled(t, "ListSubscriptionsAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// trackingSignatureSize is the number of HMAC bytes kept in tracking links
+const trackingSignatureSize = 16
+
+var (
+	// linkPattern matches the href attribute of anchors
+	linkPattern = regexp.MustCompile(`(?i)(<a\s[^>]*?href\s*=\s*)(?:"([^"]*)"|'([^']*)')`)
+	// htmlPattern detects HTML email bodies; plain text bodies are not tracked
+	htmlPattern = regexp.MustCompile(`(?i)<(html|body|a|p|div|table|img|br|span)[\s>/]`)
+)
+
+// TrackingService rewrites email content for open and click tracking and
+// records the resulting engagement events
+type TrackingService struct {
+	repo    domain.TrackingRepository
+	baseURL string
+	key     []byte
+	logger  *slog.Logger
+}
+
+// NewTrackingService creates a new TrackingService
+func NewTrackingService(repo domain.TrackingRepository, cfg config.TrackingConfig, logger *slog.Logger) (*TrackingService, error) {
+	secret, err := cfg.ResolveSecret()
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("tracking: signing secret is required")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("tracking: base url is required")
+	}
+
+	return &TrackingService{
+		repo:    repo,
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		key:     []byte(secret),
+		logger:  logger,
+	}, nil
+}
+
+// Rewrite implements domain.ContentRewriter. Links in HTML emails are routed
+// through the signed click endpoint and a tracking pixel is added; other
+// content, and notifications that opted out of tracking, are returned as is.
+func (s *TrackingService) Rewrite(n *domain.Notification) string {
+	if n.Channel != domain.ChannelEmail || !n.TrackingEnabled() || !htmlPattern.MatchString(n.Content) {
+		return n.Content
+	}
+
+	content := linkPattern.ReplaceAllStringFunc(n.Content, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		quote, href := `"`, parts[2]
+		if parts[3] != "" {
+			quote, href = `'`, parts[3]
+		}
+
+		target := html.UnescapeString(strings.TrimSpace(href))
+		if !isTrackableURL(target) || strings.HasPrefix(target, s.baseURL) {
+			return match
+		}
+
+		return parts[1] + quote + html.EscapeString(s.ClickURL(n.ID, target)) + quote
+	})
+
+	pixel := `<img src="` + html.EscapeString(s.OpenURL(n.ID)) + `" width="1" height="1" alt="" style="display:none" />`
+	if i := strings.LastIndex(strings.ToLower(content), "</body>"); i >= 0 {
+		return content[:i] + pixel + content[i:]
+	}
+	return content + pixel
+}
+
+// OpenURL returns the signed tracking pixel URL for a notification
+func (s *TrackingService) OpenURL(id uuid.UUID) string {
+	return fmt.Sprintf("%s/api/v1/track/open/%s?s=%s", s.baseURL, id, s.sign(domain.TrackingEventOpen, id, ""))
+}
+
+// ClickURL returns the signed redirect URL for a link in a notification
+func (s *TrackingService) ClickURL(id uuid.UUID, target string) string {
+	return fmt.Sprintf("%s/api/v1/track/click/%s?u=%s&s=%s",
+		s.baseURL, id, url.QueryEscape(target), s.sign(domain.TrackingEventClick, id, target))
+}
+
+// RecordOpen records an open of a notification
+func (s *TrackingService) RecordOpen(ctx context.Context, id uuid.UUID, signature, userAgent, ip string) error {
+	if !s.verify(domain.TrackingEventOpen, id, "", signature) {
+		return domain.ErrInvalidSignature
+	}
+
+	event := domain.NewTrackingEvent(id, domain.TrackingEventOpen)
+	event.UserAgent = userAgent
+	event.IPAddress = ip
+
+	return s.record(ctx, event)
+}
+
+// RecordClick records a click of a link in a notification and returns the
+// URL to redirect to. Only signed links are accepted so the endpoint cannot
+// be used as an open redirect; a failure to store the event is logged but
+// does not block the redirect.
+func (s *TrackingService) RecordClick(ctx context.Context, id uuid.UUID, target, signature, userAgent, ip string) (string, error) {
+	if !isTrackableURL(target) || !s.verify(domain.TrackingEventClick, id, target, signature) {
+		return "", domain.ErrInvalidSignature
+	}
+
+	event := domain.NewTrackingEvent(id, domain.TrackingEventClick)
+	event.URL = &target
+	event.UserAgent = userAgent
+	event.IPAddress = ip
+
+	s.record(ctx, event)
+	return target, nil
+}
+
+// BatchEngagement returns the aggregated opens and clicks of a batch
+func (s *TrackingService) BatchEngagement(ctx context.Context, batchID uuid.UUID) (*domain.BatchEngagement, error) {
+	return s.repo.GetBatchEngagement(ctx, batchID)
+}
+
+func (s *TrackingService) record(ctx context.Context, event *domain.TrackingEvent) error {
+	if err := s.repo.RecordEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to record tracking event",
+			"notification_id", event.NotificationID,
+			"type", event.Type,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *TrackingService) sign(eventType domain.TrackingEventType, id uuid.UUID, target string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(string(eventType) + "\n" + id.String() + "\n" + target))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:trackingSignatureSize])
+}
+
+func (s *TrackingService) verify(eventType domain.TrackingEventType, id uuid.UUID, target, signature string) bool {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(s.sign(eventType, id, target))
+	return hmac.Equal(got, want)
+}
+
+// isTrackableURL reports whether target is an absolute http or https URL
+func isTrackableURL(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockTrackingRepository is a mock implementation of domain.TrackingRepository
+type MockTrackingRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrackingRepository) RecordEvent(ctx context.Context, event *domain.TrackingEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockTrackingRepository) GetBatchEngagement(ctx context.Context, batchID uuid.UUID) (*domain.BatchEngagement, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BatchEngagement), args.Error(1)
+}
+
+func newTestTrackingService(t *testing.T, repo domain.TrackingRepository) *TrackingService {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc, err := NewTrackingService(repo, config.TrackingConfig{
+		BaseURL: "https://notify.example.com/",
+		Secret:  "tracking-secret",
+	}, logger)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestNewTrackingService_RequiresSecret(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	_, err := NewTrackingService(new(MockTrackingRepository), config.TrackingConfig{BaseURL: "https://notify.example.com"}, logger)
+	assert.Error(t, err)
+}
+
+func TestTrackingService_Rewrite(t *testing.T) {
+	svc := newTestTrackingService(t, new(MockTrackingRepository))
+
+	original := `<html><body><p>Hi</p><a href="https://example.com/a?x=1&amp;y=2">A</a> ` +
+		`<a class='btn' href='http://example.com/b'>B</a> ` +
+		`<a href="mailto:help@example.com">Help</a></body></html>`
+	n := domain.NewNotification("user@example.com", domain.ChannelEmail, original)
+
+	content := svc.Rewrite(n)
+
+	hrefs := regexp.MustCompile(`href=["']([^"']+)["']`).FindAllStringSubmatch(content, -1)
+	require.Len(t, hrefs, 3)
+
+	first, err := url.Parse(html.UnescapeString(hrefs[0][1]))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/track/click/"+n.ID.String(), first.Path)
+	assert.Equal(t, "https://example.com/a?x=1&y=2", first.Query().Get("u"))
+	assert.Contains(t, hrefs[1][1], "/api/v1/track/click/")
+	assert.Equal(t, "mailto:help@example.com", hrefs[2][1])
+
+	assert.Contains(t, content, `<img src="https://notify.example.com/api/v1/track/open/`+n.ID.String())
+	assert.Regexp(t, `<img [^>]+/></body></html>$`, content)
+	assert.Equal(t, original, n.Content, "rewrite must not modify the stored content")
+}
+
+func TestTrackingService_RewriteSkipped(t *testing.T) {
+	svc := newTestTrackingService(t, new(MockTrackingRepository))
+
+	sms := domain.NewNotification("+905551234567", domain.ChannelSMS, `<a href="https://example.com">x</a>`)
+	assert.Equal(t, sms.Content, svc.Rewrite(sms))
+
+	plain := domain.NewNotification("user@example.com", domain.ChannelEmail, "Visit https://example.com")
+	assert.Equal(t, plain.Content, svc.Rewrite(plain))
+
+	optedOut := domain.NewNotification("user@example.com", domain.ChannelEmail, `<p><a href="https://example.com">x</a></p>`)
+	optedOut.Metadata = map[string]any{domain.TrackingMetadataKey: false}
+	assert.Equal(t, optedOut.Content, svc.Rewrite(optedOut))
+}
+
+func TestTrackingService_RecordClick(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTrackingRepository)
+	repo.On("RecordEvent", ctx, mock.MatchedBy(func(e *domain.TrackingEvent) bool {
+		return e.Type == domain.TrackingEventClick && *e.URL == "https://example.com/a"
+	})).Return(nil)
+
+	svc := newTestTrackingService(t, repo)
+	id := uuid.New()
+
+	link, err := url.Parse(svc.ClickURL(id, "https://example.com/a"))
+	require.NoError(t, err)
+	signature := link.Query().Get("s")
+
+	target, err := svc.RecordClick(ctx, id, "https://example.com/a", signature, "ua", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a", target)
+	repo.AssertNumberOfCalls(t, "RecordEvent", 1)
+
+	_, err = svc.RecordClick(ctx, id, "https://evil.example.com", signature, "ua", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidSignature)
+
+	_, err = svc.RecordClick(ctx, uuid.New(), "https://example.com/a", signature, "ua", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidSignature)
+	repo.AssertNumberOfCalls(t, "RecordEvent", 1)
+}
+
+func TestTrackingService_RecordOpen(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTrackingRepository)
+	repo.On("RecordEvent", ctx, mock.Anything).Return(nil)
+
+	svc := newTestTrackingService(t, repo)
+	id := uuid.New()
+
+	pixel, err := url.Parse(svc.OpenURL(id))
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RecordOpen(ctx, id, pixel.Query().Get("s"), "ua", "10.0.0.1"))
+	assert.ErrorIs(t, svc.RecordOpen(ctx, id, "forged", "ua", "10.0.0.1"), domain.ErrInvalidSignature)
+	repo.AssertNumberOfCalls(t, "RecordEvent", 1)
+}
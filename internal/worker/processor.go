@@ -3,11 +3,14 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"math"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/insider-one/notification-service/internal/config"
 	"github.com/insider-one/notification-service/internal/domain"
 )
@@ -22,6 +25,14 @@ type Processor struct {
 	config           config.RetryConfig
 	workerConfig     config.WorkerConfig
 	statusBroadcast  func(notification *domain.Notification)
+	pricing          *domain.PriceTable
+	rewriter         domain.ContentRewriter
+	suppressions     domain.SuppressionRepository
+	costObserver     func(notification *domain.Notification)
+	attempts         domain.DeliveryAttemptRepository
+	attemptBodyLimit int
+	attemptObserver  func(attempt *domain.DeliveryAttempt)
+	phoneRegion      string
 
 	mu         sync.Mutex
 	running    bool
@@ -55,6 +66,45 @@ func (p *Processor) SetStatusBroadcast(fn func(notification *domain.Notification
 	p.statusBroadcast = fn
 }
 
+// SetPricing sets the price table used to record the cost of sent notifications
+func (p *Processor) SetPricing(pricing *domain.PriceTable) {
+	p.pricing = pricing
+}
+
+// SetContentRewriter sets the rewriter applied to content right before sending,
+// e.g. to add email tracking
+func (p *Processor) SetContentRewriter(rewriter domain.ContentRewriter) {
+	p.rewriter = rewriter
+}
+
+// SetSuppressions sets the suppression list checked right before sending
+func (p *Processor) SetSuppressions(repo domain.SuppressionRepository) {
+	p.suppressions = repo
+}
+
+// SetPhoneRegion sets the region national phone numbers are read in when
+// looking up suppressions
+func (p *Processor) SetPhoneRegion(region string) {
+	p.phoneRegion = region
+}
+
+// SetCostObserver sets a function called for every notification whose cost was recorded
+func (p *Processor) SetCostObserver(fn func(notification *domain.Notification)) {
+	p.costObserver = fn
+}
+
+// SetAttempts sets the repository every provider request is logged to.
+// Response bodies are redacted and truncated to maxBodyBytes.
+func (p *Processor) SetAttempts(repo domain.DeliveryAttemptRepository, maxBodyBytes int) {
+	p.attempts = repo
+	p.attemptBodyLimit = maxBodyBytes
+}
+
+// SetAttemptObserver sets a function called after every provider request
+func (p *Processor) SetAttemptObserver(fn func(attempt *domain.DeliveryAttempt)) {
+	p.attemptObserver = fn
+}
+
 // Start starts the worker pool
 func (p *Processor) Start(ctx context.Context) error {
 	p.mu.Lock()
@@ -130,6 +180,7 @@ func (p *Processor) worker(ctx context.Context, channel domain.Channel, workerID
 		"channel", channel,
 		"worker_id", workerID,
 	)
+	ctx = domain.WithActor(ctx, fmt.Sprintf("worker %s-%d", channel, workerID))
 
 	logger.Info("worker started")
 
@@ -182,10 +233,15 @@ func (p *Processor) processNext(ctx context.Context, channel domain.Channel, log
 		return err
 	}
 
-	// Skip if already processed or cancelled
-	if notification.Status == domain.StatusSent ||
-		notification.Status == domain.StatusDelivered ||
-		notification.Status == domain.StatusCancelled {
+	// Skip if already processed, cancelled or suppressed
+	if !notification.Status.CanTransitionTo(domain.StatusProcessing) {
+		return nil
+	}
+
+	// Skip entries left behind by notifications rescheduled into the future;
+	// the scheduler queues them again when they become due
+	if notification.Status == domain.StatusScheduled && notification.ScheduledAt != nil &&
+		notification.ScheduledAt.After(time.Now()) {
 		return nil
 	}
 
@@ -195,48 +251,120 @@ func (p *Processor) processNext(ctx context.Context, channel domain.Channel, log
 
 // processNotification sends a notification to the provider
 func (p *Processor) processNotification(ctx context.Context, notification *domain.Notification, logger *slog.Logger) error {
-	logger = logger.With("notification_id", notification.ID)
+	// Each attempt gets its own correlation ID, shared by its status events and logs
+	correlationID := uuid.NewString()
+	ctx = domain.WithCorrelationID(ctx, correlationID)
+	logger = logger.With("notification_id", notification.ID, "correlation_id", correlationID)
+
+	// Time-sensitive messages that waited too long are not sent at all
+	if notification.IsExpired(time.Now()) {
+		return p.expire(ctx, notification, logger)
+	}
 
-	// Update status to processing
-	notification.MarkAsProcessing()
-	if err := p.notificationRepo.Update(ctx, notification); err != nil {
+	// Recipients may have been suppressed after the notification was created.
+	// The provider was never called, so a failed lookup does not use up a
+	// retry; the notification is queued again unchanged.
+	suppression, err := p.findSuppression(ctx, notification)
+	if err != nil {
+		if requeueErr := p.requeue(ctx, notification, p.config.BaseDelay); requeueErr != nil {
+			return requeueErr
+		}
 		return err
 	}
+	if suppression != nil {
+		if err := notification.MarkAsSuppressed(suppression.Reason); err != nil {
+			return err
+		}
+		if err := p.notificationRepo.Update(ctx, notification); err != nil {
+			return p.skipIfConflict(err, logger)
+		}
+		p.broadcastStatus(notification)
+		logger.Info("notification suppressed", "reason", suppression.Reason)
+		return nil
+	}
+
+	// Update status to processing. The version check makes this a claim, so
+	// a notification cancelled since it was read is not sent.
+	if err := notification.MarkAsProcessing(); err != nil {
+		return err
+	}
+	if err := p.notificationRepo.Update(ctx, notification); err != nil {
+		return p.skipIfConflict(err, logger)
+	}
 	p.broadcastStatus(notification)
 
+	content := notification.Content
+	if p.rewriter != nil {
+		content = p.rewriter.Rewrite(notification)
+	}
+
 	// Send to provider
 	req := &domain.ProviderRequest{
-		To:      notification.Recipient,
-		Channel: string(notification.Channel),
-		Content: notification.Content,
+		To:             notification.Recipient,
+		Channel:        string(notification.Channel),
+		Content:        content,
+		NotificationID: notification.ID,
+		Priority:       notification.Priority,
+		Metadata:       notification.Metadata,
+	}
+	if notification.Channel == domain.ChannelPush {
+		req.TTL = pushTTL(notification.RemainingTTL(time.Now()))
 	}
 
+	start := time.Now()
 	resp, err := p.provider.Send(ctx, req)
+	p.recordAttempt(ctx, notification, content, start, resp, err, logger)
 	if err != nil {
 		return p.handleSendError(ctx, notification, err, logger)
 	}
 
 	// Mark as sent
-	notification.MarkAsSent(resp.MessageID)
+	if err := notification.MarkAsSent(resp.MessageID); err != nil {
+		return err
+	}
+	if resp.Provider != "" {
+		notification.Provider = &resp.Provider
+	}
+	costed := p.recordCost(notification, resp.Provider)
 	if err := p.notificationRepo.Update(ctx, notification); err != nil {
 		return err
 	}
 	p.broadcastStatus(notification)
+	if costed && p.costObserver != nil {
+		p.costObserver(notification)
+	}
 
 	logger.Info("notification sent",
 		"external_id", resp.MessageID,
 	)
+	if resp.MessageID == "" {
+		logger.Warn("provider returned no message id; delivery receipts cannot be matched",
+			"provider", resp.Provider,
+		)
+	}
 
 	return nil
 }
 
+// skipIfConflict drops a notification another writer changed since it was
+// read; the other writer's status wins
+func (p *Processor) skipIfConflict(err error, logger *slog.Logger) error {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		logger.Info("notification changed concurrently, skipping")
+		return nil
+	}
+	return err
+}
+
 // handleSendError handles send errors and retries
 func (p *Processor) handleSendError(ctx context.Context, notification *domain.Notification, err error, logger *slog.Logger) error {
 	var providerErr domain.ProviderError
 	if errors.As(err, &providerErr) {
 		if !providerErr.Retryable {
 			// Non-retryable error, mark as failed
-			notification.MarkAsFailed(providerErr.Message)
+			if markErr := notification.MarkAsFailed(providerErr.Message); markErr != nil {
+				return markErr
+			}
 			if updateErr := p.notificationRepo.Update(ctx, notification); updateErr != nil {
 				return updateErr
 			}
@@ -251,7 +379,9 @@ func (p *Processor) handleSendError(ctx context.Context, notification *domain.No
 	// Check retry count
 	notification.IncrementRetry()
 	if notification.RetryCount >= p.config.MaxCount {
-		notification.MarkAsFailed("max retries exceeded")
+		if markErr := notification.MarkAsFailed("max retries exceeded"); markErr != nil {
+			return markErr
+		}
 		if updateErr := p.notificationRepo.Update(ctx, notification); updateErr != nil {
 			return updateErr
 		}
@@ -265,8 +395,15 @@ func (p *Processor) handleSendError(ctx context.Context, notification *domain.No
 	// Calculate backoff delay
 	delay := p.calculateBackoff(notification.RetryCount)
 
+	// Expire instead of retrying when the next attempt would be too late
+	if notification.IsExpired(time.Now().Add(delay)) {
+		return p.expire(ctx, notification, logger)
+	}
+
 	// Update notification and re-queue with delay
-	notification.Status = domain.StatusQueued
+	if markErr := notification.MarkForRetry(err.Error()); markErr != nil {
+		return markErr
+	}
 	if updateErr := p.notificationRepo.Update(ctx, notification); updateErr != nil {
 		return updateErr
 	}
@@ -278,14 +415,17 @@ func (p *Processor) handleSendError(ctx context.Context, notification *domain.No
 		"error", err,
 	)
 
-	// Wait for backoff delay before re-queueing
+	return p.requeue(ctx, notification, delay)
+}
+
+// requeue adds notification back to its queue after delay
+func (p *Processor) requeue(ctx context.Context, notification *domain.Notification, delay time.Duration) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-time.After(delay):
 	}
 
-	// Re-queue
 	item := &domain.QueueItem{
 		NotificationID: notification.ID,
 		Channel:        notification.Channel,
@@ -296,6 +436,66 @@ func (p *Processor) handleSendError(ctx context.Context, notification *domain.No
 	return p.queue.Enqueue(ctx, item)
 }
 
+// expire moves a notification that can no longer be sent in time to expired
+func (p *Processor) expire(ctx context.Context, notification *domain.Notification, logger *slog.Logger) error {
+	if err := notification.MarkAsExpired(); err != nil {
+		return err
+	}
+	if err := p.notificationRepo.Update(ctx, notification); err != nil {
+		return p.skipIfConflict(err, logger)
+	}
+	p.broadcastStatus(notification)
+	logger.Info("notification expired", "expires_at", notification.ExpiresAt)
+	return nil
+}
+
+// pushTTL converts the remaining time to live to whole seconds for push
+// providers, rounding up so a message is never cut short
+func pushTTL(remaining time.Duration) int {
+	if remaining <= 0 {
+		return 0
+	}
+	return int(math.Ceil(remaining.Seconds()))
+}
+
+// recordAttempt logs a provider request to the delivery log. Failing to
+// store it does not fail the send.
+func (p *Processor) recordAttempt(
+	ctx context.Context,
+	notification *domain.Notification,
+	content string,
+	start time.Time,
+	resp *domain.ProviderResponse,
+	sendErr error,
+	logger *slog.Logger,
+) {
+	attempt := domain.NewDeliveryAttempt(notification, start)
+	attempt.Finish(resp, sendErr)
+	attempt.Redact(p.attemptBodyLimit, notification.Recipient, content)
+	if correlationID := domain.CorrelationIDFrom(ctx); correlationID != "" {
+		attempt.CorrelationID = &correlationID
+	}
+
+	if p.attempts != nil {
+		if err := p.attempts.Create(ctx, attempt); err != nil {
+			logger.Error("failed to record delivery attempt", "error", err)
+		}
+	}
+	if p.attemptObserver != nil {
+		p.attemptObserver(attempt)
+	}
+}
+
+// recordCost prices a sent notification against the configured price table
+func (p *Processor) recordCost(notification *domain.Notification, provider string) bool {
+	quote, ok := p.pricing.Quote(provider, notification.Channel, notification.Recipient, notification.Content)
+	if !ok {
+		return false
+	}
+	notification.RecordCost(provider, quote)
+	return true
+}
+
 // calculateBackoff calculates exponential backoff delay
 func (p *Processor) calculateBackoff(retryCount int) time.Duration {
 	// Exponential backoff: baseDelay * 2^retryCount
@@ -317,3 +517,19 @@ func (p *Processor) broadcastStatus(notification *domain.Notification) {
 		p.statusBroadcast(notification)
 	}
 }
+
+// findSuppression returns the active suppression for the notification's
+// recipient, or nil when it may be sent
+func (p *Processor) findSuppression(ctx context.Context, notification *domain.Notification) (*domain.Suppression, error) {
+	if p.suppressions == nil {
+		return nil, nil
+	}
+
+	recipient := domain.SuppressionRecipient(notification.Channel, notification.Recipient, p.phoneRegion)
+	suppressed, err := p.suppressions.FindActive(ctx, notification.Channel, []string{recipient})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check suppression list: %w", err)
+	}
+
+	return suppressed[recipient], nil
+}
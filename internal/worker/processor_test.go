@@ -0,0 +1,399 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insider-one/notification-service/internal/config"
+	"github.com/insider-one/notification-service/internal/domain"
+)
+
+// MockNotificationRepository is a mock implementation of domain.NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) CreateBatch(ctx context.Context, notifications []*domain.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByBatchID(ctx context.Context, batchID uuid.UUID) ([]*domain.Notification, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Notification, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Update(ctx context.Context, n *domain.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, filter domain.NotificationFilter) (*domain.NotificationListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationListResult), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetScheduledNotifications(ctx context.Context, before time.Time, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListStatusEvents(ctx context.Context, notificationID uuid.UUID) ([]*domain.StatusEvent, error) {
+	args := m.Called(ctx, notificationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StatusEvent), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Notification, error) {
+	args := m.Called(ctx, provider, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CancelMatching(ctx context.Context, filter domain.NotificationFilter) (*domain.BulkCancelResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BulkCancelResult), args.Error(1)
+}
+
+func (m *MockNotificationRepository) RetryMatching(ctx context.Context, filter domain.RetryFilter, priority *domain.Priority) (*domain.BulkRetryResult, error) {
+	args := m.Called(ctx, filter, priority)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BulkRetryResult), args.Error(1)
+}
+
+func (m *MockNotificationRepository) RevertRetry(ctx context.Context, ids []uuid.UUID, errorMsg string) (int64, error) {
+	args := m.Called(ctx, ids, errorMsg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListHeld(ctx context.Context, group domain.DigestGroup, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, group, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Digest(ctx context.Context, digest *domain.Notification, originals []*domain.Notification) error {
+	args := m.Called(ctx, digest, originals)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ClaimStatusChecks(ctx context.Context, providers []string, sentAfter, checkedBefore time.Time, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, providers, sentAfter, checkedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+// MockQueue is a mock implementation of domain.Queue
+type MockQueue struct {
+	mock.Mock
+}
+
+func (m *MockQueue) Enqueue(ctx context.Context, item *domain.QueueItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockQueue) EnqueueBatch(ctx context.Context, items []*domain.QueueItem) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *MockQueue) Dequeue(ctx context.Context, channel domain.Channel) (*domain.QueueItem, error) {
+	args := m.Called(ctx, channel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.QueueItem), args.Error(1)
+}
+
+func (m *MockQueue) Remove(ctx context.Context, item *domain.QueueItem) (bool, error) {
+	args := m.Called(ctx, item)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQueue) RemoveBatch(ctx context.Context, items []*domain.QueueItem) (int64, error) {
+	args := m.Called(ctx, items)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueue) GetQueueDepth(ctx context.Context, channel domain.Channel) (int64, error) {
+	args := m.Called(ctx, channel)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueue) GetAllQueueDepths(ctx context.Context) (map[domain.Channel]int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[domain.Channel]int64), args.Error(1)
+}
+
+// MockSuppressionRepository is a mock implementation of domain.SuppressionRepository
+type MockSuppressionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSuppressionRepository) Upsert(ctx context.Context, s *domain.Suppression) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) UpsertBatch(ctx context.Context, suppressions []*domain.Suppression) error {
+	args := m.Called(ctx, suppressions)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) Get(ctx context.Context, channel domain.Channel, recipient string) (*domain.Suppression, error) {
+	args := m.Called(ctx, channel, recipient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) List(ctx context.Context, filter domain.SuppressionFilter) (*domain.SuppressionListResult, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SuppressionListResult), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) Delete(ctx context.Context, channel domain.Channel, recipient string) error {
+	args := m.Called(ctx, channel, recipient)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) FindActive(ctx context.Context, channel domain.Channel, recipients []string) (map[string]*domain.Suppression, error) {
+	args := m.Called(ctx, channel, recipients)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) ListUnnormalizedPhones(ctx context.Context, after string, limit int) ([]*domain.Suppression, error) {
+	args := m.Called(ctx, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) Rekey(ctx context.Context, channel domain.Channel, from, to string) error {
+	args := m.Called(ctx, channel, from, to)
+	return args.Error(0)
+}
+
+// MockProvider is a mock implementation of domain.NotificationProvider
+type MockProvider struct {
+	mock.Mock
+}
+
+func (m *MockProvider) Send(ctx context.Context, req *domain.ProviderRequest) (*domain.ProviderResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProviderResponse), args.Error(1)
+}
+
+// withStatus matches a notification whose status is status when the mock is called
+func withStatus(status domain.Status) any {
+	return mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Status == status
+	})
+}
+
+func TestProcessor_ProcessNotification(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	retryConfig := config.RetryConfig{MaxCount: 3, BaseDelay: time.Millisecond}
+
+	newProcessor := func(retry config.RetryConfig) (*Processor, *MockNotificationRepository, *MockQueue, *MockProvider, *MockSuppressionRepository) {
+		repo := new(MockNotificationRepository)
+		queue := new(MockQueue)
+		provider := new(MockProvider)
+		suppressions := new(MockSuppressionRepository)
+		p := NewProcessor(repo, queue, nil, provider, logger, retry, config.WorkerConfig{})
+		p.SetSuppressions(suppressions)
+		return p, repo, queue, provider, suppressions
+	}
+
+	newQueued := func(channel domain.Channel, recipient string) *domain.Notification {
+		n := domain.NewNotification(recipient, channel, "Your code is 4821")
+		n.Status = domain.StatusQueued
+		return n
+	}
+
+	t.Run("suppresses a recipient suppressed after creation", func(t *testing.T) {
+		p, repo, _, provider, suppressions := newProcessor(retryConfig)
+		n := newQueued(domain.ChannelEmail, "user@example.com")
+
+		suppression := domain.NewSuppression("user@example.com", domain.ChannelEmail, domain.SuppressionBounce)
+		suppressions.On("FindActive", mock.Anything, domain.ChannelEmail, []string{"user@example.com"}).
+			Return(map[string]*domain.Suppression{"user@example.com": suppression}, nil).Once()
+		repo.On("Update", mock.Anything, withStatus(domain.StatusSuppressed)).Return(nil).Once()
+
+		require.NoError(t, p.processNotification(ctx, n, logger))
+
+		assert.Equal(t, domain.StatusSuppressed, n.Status)
+		provider.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("requeues without using a retry when the suppression lookup fails", func(t *testing.T) {
+		p, repo, queue, provider, suppressions := newProcessor(retryConfig)
+		n := newQueued(domain.ChannelEmail, "user@example.com")
+
+		suppressions.On("FindActive", mock.Anything, domain.ChannelEmail, []string{"user@example.com"}).
+			Return(nil, errors.New("connection refused")).Once()
+		queue.On("Enqueue", mock.Anything, mock.MatchedBy(func(item *domain.QueueItem) bool {
+			return item.NotificationID == n.ID && item.RetryCount == 0
+		})).Return(nil).Once()
+
+		err := p.processNotification(ctx, n, logger)
+
+		assert.ErrorContains(t, err, "failed to check suppression list")
+		assert.Equal(t, domain.StatusQueued, n.Status)
+		assert.Equal(t, 0, n.RetryCount)
+		queue.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		provider.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expires a notification before claiming it", func(t *testing.T) {
+		p, repo, _, provider, suppressions := newProcessor(retryConfig)
+		n := newQueued(domain.ChannelSMS, "+905551234567")
+		expiresAt := time.Now().Add(-time.Minute)
+		n.ExpiresAt = &expiresAt
+
+		repo.On("Update", mock.Anything, withStatus(domain.StatusExpired)).Return(nil).Once()
+
+		require.NoError(t, p.processNotification(ctx, n, logger))
+
+		assert.Equal(t, domain.StatusExpired, n.Status)
+		repo.AssertExpectations(t)
+		suppressions.AssertNotCalled(t, "FindActive", mock.Anything, mock.Anything, mock.Anything)
+		provider.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expires instead of retrying when the retry would be too late", func(t *testing.T) {
+		p, repo, queue, provider, suppressions := newProcessor(config.RetryConfig{MaxCount: 3, BaseDelay: 5 * time.Minute})
+		n := newQueued(domain.ChannelSMS, "+905551234567")
+		expiresAt := time.Now().Add(time.Minute)
+		n.ExpiresAt = &expiresAt
+
+		suppressions.On("FindActive", mock.Anything, domain.ChannelSMS, mock.Anything).
+			Return(map[string]*domain.Suppression{}, nil).Once()
+		repo.On("Update", mock.Anything, withStatus(domain.StatusProcessing)).Return(nil).Once()
+		provider.On("Send", mock.Anything, mock.Anything).
+			Return(nil, domain.NewProviderError(503, "unavailable", true)).Once()
+		repo.On("Update", mock.Anything, withStatus(domain.StatusExpired)).Return(nil).Once()
+
+		require.NoError(t, p.processNotification(ctx, n, logger))
+
+		assert.Equal(t, domain.StatusExpired, n.Status)
+		repo.AssertExpectations(t)
+		queue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("passes the remaining time to live to push providers", func(t *testing.T) {
+		p, repo, _, provider, suppressions := newProcessor(retryConfig)
+		n := newQueued(domain.ChannelPush, "device-token")
+		expiresAt := time.Now().Add(90 * time.Second)
+		n.ExpiresAt = &expiresAt
+
+		suppressions.On("FindActive", mock.Anything, domain.ChannelPush, mock.Anything).
+			Return(map[string]*domain.Suppression{}, nil).Once()
+		repo.On("Update", mock.Anything, withStatus(domain.StatusProcessing)).Return(nil).Once()
+		provider.On("Send", mock.Anything, mock.MatchedBy(func(req *domain.ProviderRequest) bool {
+			return req.TTL > 85 && req.TTL <= 90
+		})).Return(&domain.ProviderResponse{MessageID: "msg-1", Provider: "webhook"}, nil).Once()
+		repo.On("Update", mock.Anything, withStatus(domain.StatusSent)).Return(nil).Once()
+
+		require.NoError(t, p.processNotification(ctx, n, logger))
+
+		assert.Equal(t, domain.StatusSent, n.Status)
+		provider.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("sends push without a time to live when none is set", func(t *testing.T) {
+		p, repo, _, provider, suppressions := newProcessor(retryConfig)
+		n := newQueued(domain.ChannelPush, "device-token")
+
+		suppressions.On("FindActive", mock.Anything, domain.ChannelPush, mock.Anything).
+			Return(map[string]*domain.Suppression{}, nil).Once()
+		repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		provider.On("Send", mock.Anything, mock.MatchedBy(func(req *domain.ProviderRequest) bool {
+			return req.TTL == 0
+		})).Return(&domain.ProviderResponse{MessageID: "msg-1"}, nil).Once()
+
+		require.NoError(t, p.processNotification(ctx, n, logger))
+
+		provider.AssertExpectations(t)
+	})
+}
+
+func TestPushTTL(t *testing.T) {
+	assert.Equal(t, 0, pushTTL(0))
+	assert.Equal(t, 0, pushTTL(-time.Second))
+	assert.Equal(t, 1, pushTTL(100*time.Millisecond))
+	assert.Equal(t, 90, pushTTL(90*time.Second))
+}
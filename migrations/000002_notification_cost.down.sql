@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_sent_at;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS currency;
+ALTER TABLE notifications DROP COLUMN IF EXISTS cost;
+ALTER TABLE notifications DROP COLUMN IF EXISTS segments;
+ALTER TABLE notifications DROP COLUMN IF EXISTS provider;
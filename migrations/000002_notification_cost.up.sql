@@ -0,0 +1,8 @@
+-- Track the provider and cost of each sent notification
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS provider VARCHAR(100);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS segments INT NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS cost NUMERIC(14, 6);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+-- Create index for spend reports
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_at ON notifications(sent_at) WHERE cost IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_notifications_external_id;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS delivered_at;
+
+UPDATE notifications SET status = 'failed' WHERE status = 'undeliverable';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled'));
@@ -0,0 +1,9 @@
+-- Allow the undeliverable status reported by delivery receipts
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable'));
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for receipt lookups
+CREATE INDEX IF NOT EXISTS idx_notifications_external_id ON notifications(external_id) WHERE external_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_status_poll;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS status_checked_at;
@@ -0,0 +1,5 @@
+-- Track when a sent notification was last polled for its delivery status
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS status_checked_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for status polling
+CREATE INDEX IF NOT EXISTS idx_notifications_status_poll ON notifications(provider, sent_at) WHERE status = 'sent';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS tracking_events;
+
+ALTER TABLE templates DROP COLUMN IF EXISTS tracking_disabled;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS clicks;
+ALTER TABLE notifications DROP COLUMN IF EXISTS opens;
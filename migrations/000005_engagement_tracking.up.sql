@@ -0,0 +1,20 @@
+-- Engagement counters for email open and click tracking
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS opens INT NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS clicks INT NOT NULL DEFAULT 0;
+
+-- Allow templates to opt out of tracking
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS tracking_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create tracking events table
+CREATE TABLE IF NOT EXISTS tracking_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('open', 'click')),
+    url TEXT,
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create index for tracking events
+CREATE INDEX IF NOT EXISTS idx_tracking_events_notification_id ON tracking_events(notification_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS suppressions;
+
+UPDATE notifications SET status = 'cancelled' WHERE status = 'suppressed';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable'));
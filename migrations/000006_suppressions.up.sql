@@ -0,0 +1,20 @@
+-- Allow the suppressed status
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable', 'suppressed'));
+
+-- Create suppressions table
+CREATE TABLE IF NOT EXISTS suppressions (
+    recipient VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('sms', 'email', 'push')),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('bounce', 'complaint', 'unsubscribe', 'manual')),
+    note TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (channel, recipient)
+);
+
+-- Create indexes for suppressions
+CREATE INDEX IF NOT EXISTS idx_suppressions_reason ON suppressions(reason);
+CREATE INDEX IF NOT EXISTS idx_suppressions_created_at ON suppressions(created_at);
@@ -0,0 +1,18 @@
+-- Create inbound messages table
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(100) NOT NULL,
+    external_id VARCHAR(255),
+    from_number VARCHAR(255) NOT NULL,
+    to_number VARCHAR(255) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    action VARCHAR(20) NOT NULL DEFAULT '' CHECK (action IN ('', 'opt_out', 'opt_in')),
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for inbound messages
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inbound_messages_provider_external_id
+    ON inbound_messages(provider, external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_from_number ON inbound_messages(from_number);
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_received_at ON inbound_messages(received_at);
@@ -0,0 +1,2 @@
+-- Version counter for optimistic concurrency control on notification updates
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
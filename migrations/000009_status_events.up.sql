@@ -0,0 +1,22 @@
+-- Create notification status events table
+CREATE TABLE IF NOT EXISTS notification_status_events (
+    seq BIGSERIAL,
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(100),
+    instance VARCHAR(255),
+    error TEXT,
+    correlation_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create index for status events
+CREATE INDEX IF NOT EXISTS idx_notification_status_events_notification_id
+    ON notification_status_events(notification_id, created_at, seq);
+
+-- Seed the history of existing notifications with their current status
+INSERT INTO notification_status_events (id, notification_id, to_status, actor, error, created_at)
+SELECT gen_random_uuid(), id, status, 'migration', error_message, COALESCE(updated_at, created_at, NOW())
+FROM notifications;
@@ -0,0 +1,22 @@
+-- Create delivery attempts table
+CREATE TABLE IF NOT EXISTS delivery_attempts (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    attempt_number INT NOT NULL,
+    provider VARCHAR(100) NOT NULL DEFAULT '',
+    channel VARCHAR(10) NOT NULL,
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('succeeded', 'retryable_error', 'failed')),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    latency_ms BIGINT NOT NULL,
+    http_status INT,
+    provider_message_id VARCHAR(255),
+    response_body TEXT,
+    error TEXT,
+    correlation_id VARCHAR(255),
+    CONSTRAINT delivery_attempts_number_unique UNIQUE (notification_id, attempt_number)
+);
+
+-- Create index for provider reports
+CREATE INDEX IF NOT EXISTS idx_delivery_attempts_started_at
+    ON delivery_attempts(started_at, provider, channel);
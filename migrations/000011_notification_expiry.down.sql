@@ -0,0 +1,7 @@
+UPDATE notifications SET status = 'failed' WHERE status = 'expired';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable', 'suppressed'));
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS category;
+ALTER TABLE notifications DROP COLUMN IF EXISTS expires_at;
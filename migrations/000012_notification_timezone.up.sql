@@ -0,0 +1,2 @@
+-- Add recipient time zone used for quiet hours
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
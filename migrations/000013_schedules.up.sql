@@ -0,0 +1,36 @@
+-- Create recurring schedules table
+CREATE TABLE IF NOT EXISTS schedules (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('sms', 'email', 'push')),
+    template_name VARCHAR(255) NOT NULL,
+    template_vars JSONB,
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal' CHECK (priority IN ('high', 'normal', 'low')),
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    metadata JSONB,
+    cron VARCHAR(255),
+    rrule TEXT,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    max_occurrences INT,
+    occurrences INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed')),
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT schedules_recurrence_check CHECK ((cron IS NULL) <> (rrule IS NULL))
+);
+
+-- Create index for finding due schedules
+CREATE INDEX IF NOT EXISTS idx_schedules_due ON schedules(next_run_at) WHERE status = 'active';
+
+-- Create trigger for schedules
+DROP TRIGGER IF EXISTS update_schedules_updated_at ON schedules;
+CREATE TRIGGER update_schedules_updated_at
+    BEFORE UPDATE ON schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
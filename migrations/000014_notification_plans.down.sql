@@ -0,0 +1 @@
+DROP TABLE IF EXISTS notification_plans;
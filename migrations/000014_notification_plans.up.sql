@@ -0,0 +1,28 @@
+-- Create fallback plans table
+CREATE TABLE IF NOT EXISTS notification_plans (
+    id UUID PRIMARY KEY,
+    content TEXT NOT NULL DEFAULT '',
+    template_name VARCHAR(255),
+    template_vars JSONB,
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal' CHECK (priority IN ('high', 'normal', 'low')),
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    metadata JSONB,
+    steps JSONB NOT NULL,
+    current_step INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'delivered', 'sent', 'failed', 'cancelled')),
+    step_deadline TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create index for finding plans to evaluate
+CREATE INDEX IF NOT EXISTS idx_notification_plans_active ON notification_plans(step_deadline) WHERE status = 'active';
+
+-- Create trigger for notification plans
+DROP TRIGGER IF EXISTS update_notification_plans_updated_at ON notification_plans;
+CREATE TRIGGER update_notification_plans_updated_at
+    BEFORE UPDATE ON notification_plans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
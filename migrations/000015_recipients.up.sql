@@ -0,0 +1,18 @@
+-- Create recipient profiles table
+CREATE TABLE IF NOT EXISTS recipients (
+    user_id VARCHAR(255) PRIMARY KEY,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    device_tokens TEXT[] NOT NULL DEFAULT '{}',
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create trigger for recipients
+DROP TRIGGER IF EXISTS update_recipients_updated_at ON recipients;
+CREATE TRIGGER update_recipients_updated_at
+    BEFORE UPDATE ON recipients
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS topic_publications;
+DROP TABLE IF EXISTS topic_subscriptions;
+DROP TABLE IF EXISTS topics;
@@ -0,0 +1,67 @@
+-- Create topics table
+CREATE TABLE IF NOT EXISTS topics (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create topic subscriptions table
+CREATE TABLE IF NOT EXISTS topic_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(100) NOT NULL REFERENCES topics(name) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES recipients(user_id) ON DELETE CASCADE,
+    channels TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (topic, user_id)
+);
+
+-- Create indexes for fanning out a topic and for deleting a recipient
+CREATE INDEX IF NOT EXISTS idx_topic_subscriptions_topic_id ON topic_subscriptions(topic, id);
+CREATE INDEX IF NOT EXISTS idx_topic_subscriptions_user_id ON topic_subscriptions(user_id);
+
+-- Create topic publications table
+CREATE TABLE IF NOT EXISTS topic_publications (
+    id UUID PRIMARY KEY,
+    topic VARCHAR(100) NOT NULL REFERENCES topics(name) ON DELETE CASCADE,
+    content TEXT NOT NULL DEFAULT '',
+    template_name VARCHAR(255),
+    template_vars JSONB,
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal' CHECK (priority IN ('high', 'normal', 'low')),
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    metadata JSONB,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    last_subscription_id BIGINT NOT NULL DEFAULT 0,
+    subscribers BIGINT NOT NULL DEFAULT 0,
+    notifications BIGINT NOT NULL DEFAULT 0,
+    skipped BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create index for finding publications to fan out
+CREATE INDEX IF NOT EXISTS idx_topic_publications_due ON topic_publications(created_at) WHERE status IN ('pending', 'running');
+
+-- Create triggers for topics
+DROP TRIGGER IF EXISTS update_topics_updated_at ON topics;
+CREATE TRIGGER update_topics_updated_at
+    BEFORE UPDATE ON topics
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_topic_subscriptions_updated_at ON topic_subscriptions;
+CREATE TRIGGER update_topic_subscriptions_updated_at
+    BEFORE UPDATE ON topic_subscriptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_topic_publications_updated_at ON topic_publications;
+CREATE TRIGGER update_topic_publications_updated_at
+    BEFORE UPDATE ON topic_publications
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_notifications_held;
+
+UPDATE notifications SET status = 'cancelled' WHERE status = 'held';
+UPDATE notifications SET status = 'cancelled' WHERE status = 'digested';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable', 'suppressed', 'expired'));
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS digest_id;
+
+DROP TRIGGER IF EXISTS update_digest_rules_updated_at ON digest_rules;
+DROP TABLE IF EXISTS digest_rules;
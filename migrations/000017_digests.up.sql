@@ -0,0 +1,31 @@
+-- Create digest rules table
+CREATE TABLE IF NOT EXISTS digest_rules (
+    id UUID PRIMARY KEY,
+    category VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('sms', 'email', 'push')),
+    window_seconds INTEGER NOT NULL CHECK (window_seconds > 0),
+    max_count INTEGER NOT NULL CHECK (max_count > 1),
+    template_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (category, channel)
+);
+
+-- Create trigger for digest_rules
+DROP TRIGGER IF EXISTS update_digest_rules_updated_at ON digest_rules;
+CREATE TRIGGER update_digest_rules_updated_at
+    BEFORE UPDATE ON digest_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Link digested notifications to the digest they were merged into
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digest_id UUID;
+
+-- Allow the held and digested statuses
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'scheduled', 'queued', 'processing', 'sent', 'delivered', 'failed', 'cancelled', 'undeliverable', 'suppressed', 'expired', 'held', 'digested'));
+
+-- Index for finding the held notifications of a digest
+CREATE INDEX IF NOT EXISTS idx_notifications_held ON notifications(recipient, channel, category, created_at)
+    WHERE status = 'held';
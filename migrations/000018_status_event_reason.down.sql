@@ -0,0 +1 @@
+ALTER TABLE notification_status_events DROP COLUMN IF EXISTS reason;
@@ -0,0 +1,2 @@
+-- Record why a transition made on request happened, such as a manual retry
+ALTER TABLE notification_status_events ADD COLUMN IF NOT EXISTS reason VARCHAR(255);
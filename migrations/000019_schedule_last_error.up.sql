@@ -0,0 +1,2 @@
+-- Record why a schedule was paused after an occurrence could not be created
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS last_error TEXT;
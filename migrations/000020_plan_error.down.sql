@@ -0,0 +1 @@
+ALTER TABLE notification_plans DROP COLUMN IF EXISTS error;
@@ -0,0 +1,2 @@
+-- Record why a plan failed when a step's notification could not be created
+ALTER TABLE notification_plans ADD COLUMN IF NOT EXISTS error TEXT;